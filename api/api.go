@@ -0,0 +1,5 @@
+// Package api holds the protobuf definitions of the gRPC API used by other services of the platform.
+// The Go code next to each .proto file is generated with buf, protoc-gen-go and protoc-gen-go-grpc.
+package api
+
+//go:generate buf generate
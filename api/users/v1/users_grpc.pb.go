@@ -0,0 +1,770 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: users/v1/users.proto
+
+package usersv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	CandidatesService_GetCandidate_FullMethodName           = "/users.v1.CandidatesService/GetCandidate"
+	CandidatesService_SearchCandidates_FullMethodName       = "/users.v1.CandidatesService/SearchCandidates"
+	CandidatesService_CandidateExists_FullMethodName        = "/users.v1.CandidatesService/CandidateExists"
+	CandidatesService_UpdateCandidate_FullMethodName        = "/users.v1.CandidatesService/UpdateCandidate"
+	CandidatesService_DeleteCandidate_FullMethodName        = "/users.v1.CandidatesService/DeleteCandidate"
+	CandidatesService_AddCandidateSkills_FullMethodName     = "/users.v1.CandidatesService/AddCandidateSkills"
+	CandidatesService_RemoveCandidateSkills_FullMethodName  = "/users.v1.CandidatesService/RemoveCandidateSkills"
+	CandidatesService_GetCandidateInterviews_FullMethodName = "/users.v1.CandidatesService/GetCandidateInterviews"
+)
+
+// CandidatesServiceClient is the client API for CandidatesService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CandidatesServiceClient interface {
+	GetCandidate(ctx context.Context, in *GetCandidateRequest, opts ...grpc.CallOption) (*GetCandidateResponse, error)
+	SearchCandidates(ctx context.Context, in *SearchCandidatesRequest, opts ...grpc.CallOption) (*SearchCandidatesResponse, error)
+	CandidateExists(ctx context.Context, in *CandidateExistsRequest, opts ...grpc.CallOption) (*CandidateExistsResponse, error)
+	UpdateCandidate(ctx context.Context, in *UpdateCandidateRequest, opts ...grpc.CallOption) (*UpdateCandidateResponse, error)
+	DeleteCandidate(ctx context.Context, in *DeleteCandidateRequest, opts ...grpc.CallOption) (*DeleteCandidateResponse, error)
+	AddCandidateSkills(ctx context.Context, in *AddCandidateSkillsRequest, opts ...grpc.CallOption) (*AddCandidateSkillsResponse, error)
+	RemoveCandidateSkills(ctx context.Context, in *RemoveCandidateSkillsRequest, opts ...grpc.CallOption) (*RemoveCandidateSkillsResponse, error)
+	GetCandidateInterviews(ctx context.Context, in *GetCandidateInterviewsRequest, opts ...grpc.CallOption) (*GetCandidateInterviewsResponse, error)
+}
+
+type candidatesServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCandidatesServiceClient(cc grpc.ClientConnInterface) CandidatesServiceClient {
+	return &candidatesServiceClient{cc}
+}
+
+func (c *candidatesServiceClient) GetCandidate(ctx context.Context, in *GetCandidateRequest, opts ...grpc.CallOption) (*GetCandidateResponse, error) {
+	out := new(GetCandidateResponse)
+	err := c.cc.Invoke(ctx, CandidatesService_GetCandidate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *candidatesServiceClient) SearchCandidates(ctx context.Context, in *SearchCandidatesRequest, opts ...grpc.CallOption) (*SearchCandidatesResponse, error) {
+	out := new(SearchCandidatesResponse)
+	err := c.cc.Invoke(ctx, CandidatesService_SearchCandidates_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *candidatesServiceClient) CandidateExists(ctx context.Context, in *CandidateExistsRequest, opts ...grpc.CallOption) (*CandidateExistsResponse, error) {
+	out := new(CandidateExistsResponse)
+	err := c.cc.Invoke(ctx, CandidatesService_CandidateExists_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *candidatesServiceClient) UpdateCandidate(ctx context.Context, in *UpdateCandidateRequest, opts ...grpc.CallOption) (*UpdateCandidateResponse, error) {
+	out := new(UpdateCandidateResponse)
+	err := c.cc.Invoke(ctx, CandidatesService_UpdateCandidate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *candidatesServiceClient) DeleteCandidate(ctx context.Context, in *DeleteCandidateRequest, opts ...grpc.CallOption) (*DeleteCandidateResponse, error) {
+	out := new(DeleteCandidateResponse)
+	err := c.cc.Invoke(ctx, CandidatesService_DeleteCandidate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *candidatesServiceClient) AddCandidateSkills(ctx context.Context, in *AddCandidateSkillsRequest, opts ...grpc.CallOption) (*AddCandidateSkillsResponse, error) {
+	out := new(AddCandidateSkillsResponse)
+	err := c.cc.Invoke(ctx, CandidatesService_AddCandidateSkills_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *candidatesServiceClient) RemoveCandidateSkills(ctx context.Context, in *RemoveCandidateSkillsRequest, opts ...grpc.CallOption) (*RemoveCandidateSkillsResponse, error) {
+	out := new(RemoveCandidateSkillsResponse)
+	err := c.cc.Invoke(ctx, CandidatesService_RemoveCandidateSkills_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *candidatesServiceClient) GetCandidateInterviews(ctx context.Context, in *GetCandidateInterviewsRequest, opts ...grpc.CallOption) (*GetCandidateInterviewsResponse, error) {
+	out := new(GetCandidateInterviewsResponse)
+	err := c.cc.Invoke(ctx, CandidatesService_GetCandidateInterviews_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CandidatesServiceServer is the server API for CandidatesService service.
+// All implementations must embed UnimplementedCandidatesServiceServer
+// for forward compatibility
+type CandidatesServiceServer interface {
+	GetCandidate(context.Context, *GetCandidateRequest) (*GetCandidateResponse, error)
+	SearchCandidates(context.Context, *SearchCandidatesRequest) (*SearchCandidatesResponse, error)
+	CandidateExists(context.Context, *CandidateExistsRequest) (*CandidateExistsResponse, error)
+	UpdateCandidate(context.Context, *UpdateCandidateRequest) (*UpdateCandidateResponse, error)
+	DeleteCandidate(context.Context, *DeleteCandidateRequest) (*DeleteCandidateResponse, error)
+	AddCandidateSkills(context.Context, *AddCandidateSkillsRequest) (*AddCandidateSkillsResponse, error)
+	RemoveCandidateSkills(context.Context, *RemoveCandidateSkillsRequest) (*RemoveCandidateSkillsResponse, error)
+	GetCandidateInterviews(context.Context, *GetCandidateInterviewsRequest) (*GetCandidateInterviewsResponse, error)
+	mustEmbedUnimplementedCandidatesServiceServer()
+}
+
+// UnimplementedCandidatesServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedCandidatesServiceServer struct {
+}
+
+func (UnimplementedCandidatesServiceServer) GetCandidate(context.Context, *GetCandidateRequest) (*GetCandidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCandidate not implemented")
+}
+func (UnimplementedCandidatesServiceServer) SearchCandidates(context.Context, *SearchCandidatesRequest) (*SearchCandidatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchCandidates not implemented")
+}
+func (UnimplementedCandidatesServiceServer) CandidateExists(context.Context, *CandidateExistsRequest) (*CandidateExistsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CandidateExists not implemented")
+}
+func (UnimplementedCandidatesServiceServer) UpdateCandidate(context.Context, *UpdateCandidateRequest) (*UpdateCandidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCandidate not implemented")
+}
+func (UnimplementedCandidatesServiceServer) DeleteCandidate(context.Context, *DeleteCandidateRequest) (*DeleteCandidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCandidate not implemented")
+}
+func (UnimplementedCandidatesServiceServer) AddCandidateSkills(context.Context, *AddCandidateSkillsRequest) (*AddCandidateSkillsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddCandidateSkills not implemented")
+}
+func (UnimplementedCandidatesServiceServer) RemoveCandidateSkills(context.Context, *RemoveCandidateSkillsRequest) (*RemoveCandidateSkillsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveCandidateSkills not implemented")
+}
+func (UnimplementedCandidatesServiceServer) GetCandidateInterviews(context.Context, *GetCandidateInterviewsRequest) (*GetCandidateInterviewsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCandidateInterviews not implemented")
+}
+func (UnimplementedCandidatesServiceServer) mustEmbedUnimplementedCandidatesServiceServer() {}
+
+// UnsafeCandidatesServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CandidatesServiceServer will
+// result in compilation errors.
+type UnsafeCandidatesServiceServer interface {
+	mustEmbedUnimplementedCandidatesServiceServer()
+}
+
+func RegisterCandidatesServiceServer(s grpc.ServiceRegistrar, srv CandidatesServiceServer) {
+	s.RegisterService(&CandidatesService_ServiceDesc, srv)
+}
+
+func _CandidatesService_GetCandidate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCandidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CandidatesServiceServer).GetCandidate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CandidatesService_GetCandidate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CandidatesServiceServer).GetCandidate(ctx, req.(*GetCandidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CandidatesService_SearchCandidates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchCandidatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CandidatesServiceServer).SearchCandidates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CandidatesService_SearchCandidates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CandidatesServiceServer).SearchCandidates(ctx, req.(*SearchCandidatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CandidatesService_CandidateExists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CandidateExistsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CandidatesServiceServer).CandidateExists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CandidatesService_CandidateExists_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CandidatesServiceServer).CandidateExists(ctx, req.(*CandidateExistsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CandidatesService_UpdateCandidate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCandidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CandidatesServiceServer).UpdateCandidate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CandidatesService_UpdateCandidate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CandidatesServiceServer).UpdateCandidate(ctx, req.(*UpdateCandidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CandidatesService_DeleteCandidate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCandidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CandidatesServiceServer).DeleteCandidate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CandidatesService_DeleteCandidate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CandidatesServiceServer).DeleteCandidate(ctx, req.(*DeleteCandidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CandidatesService_AddCandidateSkills_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddCandidateSkillsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CandidatesServiceServer).AddCandidateSkills(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CandidatesService_AddCandidateSkills_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CandidatesServiceServer).AddCandidateSkills(ctx, req.(*AddCandidateSkillsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CandidatesService_RemoveCandidateSkills_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveCandidateSkillsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CandidatesServiceServer).RemoveCandidateSkills(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CandidatesService_RemoveCandidateSkills_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CandidatesServiceServer).RemoveCandidateSkills(ctx, req.(*RemoveCandidateSkillsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CandidatesService_GetCandidateInterviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCandidateInterviewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CandidatesServiceServer).GetCandidateInterviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CandidatesService_GetCandidateInterviews_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CandidatesServiceServer).GetCandidateInterviews(ctx, req.(*GetCandidateInterviewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CandidatesService_ServiceDesc is the grpc.ServiceDesc for CandidatesService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CandidatesService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "users.v1.CandidatesService",
+	HandlerType: (*CandidatesServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCandidate",
+			Handler:    _CandidatesService_GetCandidate_Handler,
+		},
+		{
+			MethodName: "SearchCandidates",
+			Handler:    _CandidatesService_SearchCandidates_Handler,
+		},
+		{
+			MethodName: "CandidateExists",
+			Handler:    _CandidatesService_CandidateExists_Handler,
+		},
+		{
+			MethodName: "UpdateCandidate",
+			Handler:    _CandidatesService_UpdateCandidate_Handler,
+		},
+		{
+			MethodName: "DeleteCandidate",
+			Handler:    _CandidatesService_DeleteCandidate_Handler,
+		},
+		{
+			MethodName: "AddCandidateSkills",
+			Handler:    _CandidatesService_AddCandidateSkills_Handler,
+		},
+		{
+			MethodName: "RemoveCandidateSkills",
+			Handler:    _CandidatesService_RemoveCandidateSkills_Handler,
+		},
+		{
+			MethodName: "GetCandidateInterviews",
+			Handler:    _CandidatesService_GetCandidateInterviews_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "users/v1/users.proto",
+}
+
+const (
+	RecruiterService_GetRecruiter_FullMethodName           = "/users.v1.RecruiterService/GetRecruiter"
+	RecruiterService_RecruiterExists_FullMethodName        = "/users.v1.RecruiterService/RecruiterExists"
+	RecruiterService_GetRecruiterInterviews_FullMethodName = "/users.v1.RecruiterService/GetRecruiterInterviews"
+)
+
+// RecruiterServiceClient is the client API for RecruiterService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RecruiterServiceClient interface {
+	GetRecruiter(ctx context.Context, in *GetRecruiterRequest, opts ...grpc.CallOption) (*GetRecruiterResponse, error)
+	RecruiterExists(ctx context.Context, in *RecruiterExistsRequest, opts ...grpc.CallOption) (*RecruiterExistsResponse, error)
+	GetRecruiterInterviews(ctx context.Context, in *GetRecruiterInterviewsRequest, opts ...grpc.CallOption) (*GetRecruiterInterviewsResponse, error)
+}
+
+type recruiterServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRecruiterServiceClient(cc grpc.ClientConnInterface) RecruiterServiceClient {
+	return &recruiterServiceClient{cc}
+}
+
+func (c *recruiterServiceClient) GetRecruiter(ctx context.Context, in *GetRecruiterRequest, opts ...grpc.CallOption) (*GetRecruiterResponse, error) {
+	out := new(GetRecruiterResponse)
+	err := c.cc.Invoke(ctx, RecruiterService_GetRecruiter_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *recruiterServiceClient) RecruiterExists(ctx context.Context, in *RecruiterExistsRequest, opts ...grpc.CallOption) (*RecruiterExistsResponse, error) {
+	out := new(RecruiterExistsResponse)
+	err := c.cc.Invoke(ctx, RecruiterService_RecruiterExists_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *recruiterServiceClient) GetRecruiterInterviews(ctx context.Context, in *GetRecruiterInterviewsRequest, opts ...grpc.CallOption) (*GetRecruiterInterviewsResponse, error) {
+	out := new(GetRecruiterInterviewsResponse)
+	err := c.cc.Invoke(ctx, RecruiterService_GetRecruiterInterviews_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RecruiterServiceServer is the server API for RecruiterService service.
+// All implementations must embed UnimplementedRecruiterServiceServer
+// for forward compatibility
+type RecruiterServiceServer interface {
+	GetRecruiter(context.Context, *GetRecruiterRequest) (*GetRecruiterResponse, error)
+	RecruiterExists(context.Context, *RecruiterExistsRequest) (*RecruiterExistsResponse, error)
+	GetRecruiterInterviews(context.Context, *GetRecruiterInterviewsRequest) (*GetRecruiterInterviewsResponse, error)
+	mustEmbedUnimplementedRecruiterServiceServer()
+}
+
+// UnimplementedRecruiterServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedRecruiterServiceServer struct {
+}
+
+func (UnimplementedRecruiterServiceServer) GetRecruiter(context.Context, *GetRecruiterRequest) (*GetRecruiterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecruiter not implemented")
+}
+func (UnimplementedRecruiterServiceServer) RecruiterExists(context.Context, *RecruiterExistsRequest) (*RecruiterExistsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecruiterExists not implemented")
+}
+func (UnimplementedRecruiterServiceServer) GetRecruiterInterviews(context.Context, *GetRecruiterInterviewsRequest) (*GetRecruiterInterviewsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecruiterInterviews not implemented")
+}
+func (UnimplementedRecruiterServiceServer) mustEmbedUnimplementedRecruiterServiceServer() {}
+
+// UnsafeRecruiterServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RecruiterServiceServer will
+// result in compilation errors.
+type UnsafeRecruiterServiceServer interface {
+	mustEmbedUnimplementedRecruiterServiceServer()
+}
+
+func RegisterRecruiterServiceServer(s grpc.ServiceRegistrar, srv RecruiterServiceServer) {
+	s.RegisterService(&RecruiterService_ServiceDesc, srv)
+}
+
+func _RecruiterService_GetRecruiter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecruiterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecruiterServiceServer).GetRecruiter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RecruiterService_GetRecruiter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecruiterServiceServer).GetRecruiter(ctx, req.(*GetRecruiterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RecruiterService_RecruiterExists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecruiterExistsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecruiterServiceServer).RecruiterExists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RecruiterService_RecruiterExists_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecruiterServiceServer).RecruiterExists(ctx, req.(*RecruiterExistsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RecruiterService_GetRecruiterInterviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecruiterInterviewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecruiterServiceServer).GetRecruiterInterviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RecruiterService_GetRecruiterInterviews_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecruiterServiceServer).GetRecruiterInterviews(ctx, req.(*GetRecruiterInterviewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RecruiterService_ServiceDesc is the grpc.ServiceDesc for RecruiterService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RecruiterService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "users.v1.RecruiterService",
+	HandlerType: (*RecruiterServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRecruiter",
+			Handler:    _RecruiterService_GetRecruiter_Handler,
+		},
+		{
+			MethodName: "RecruiterExists",
+			Handler:    _RecruiterService_RecruiterExists_Handler,
+		},
+		{
+			MethodName: "GetRecruiterInterviews",
+			Handler:    _RecruiterService_GetRecruiterInterviews_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "users/v1/users.proto",
+}
+
+const (
+	CompanyService_CreateCompany_FullMethodName = "/users.v1.CompanyService/CreateCompany"
+	CompanyService_UpdateCompany_FullMethodName = "/users.v1.CompanyService/UpdateCompany"
+	CompanyService_GetCompany_FullMethodName    = "/users.v1.CompanyService/GetCompany"
+	CompanyService_ListCompanies_FullMethodName = "/users.v1.CompanyService/ListCompanies"
+	CompanyService_CompanyExists_FullMethodName = "/users.v1.CompanyService/CompanyExists"
+)
+
+// CompanyServiceClient is the client API for CompanyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CompanyServiceClient interface {
+	CreateCompany(ctx context.Context, in *CreateCompanyRequest, opts ...grpc.CallOption) (*CreateCompanyResponse, error)
+	UpdateCompany(ctx context.Context, in *UpdateCompanyRequest, opts ...grpc.CallOption) (*UpdateCompanyResponse, error)
+	GetCompany(ctx context.Context, in *GetCompanyRequest, opts ...grpc.CallOption) (*GetCompanyResponse, error)
+	ListCompanies(ctx context.Context, in *ListCompaniesRequest, opts ...grpc.CallOption) (*ListCompaniesResponse, error)
+	CompanyExists(ctx context.Context, in *CompanyExistsRequest, opts ...grpc.CallOption) (*CompanyExistsResponse, error)
+}
+
+type companyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCompanyServiceClient(cc grpc.ClientConnInterface) CompanyServiceClient {
+	return &companyServiceClient{cc}
+}
+
+func (c *companyServiceClient) CreateCompany(ctx context.Context, in *CreateCompanyRequest, opts ...grpc.CallOption) (*CreateCompanyResponse, error) {
+	out := new(CreateCompanyResponse)
+	err := c.cc.Invoke(ctx, CompanyService_CreateCompany_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *companyServiceClient) UpdateCompany(ctx context.Context, in *UpdateCompanyRequest, opts ...grpc.CallOption) (*UpdateCompanyResponse, error) {
+	out := new(UpdateCompanyResponse)
+	err := c.cc.Invoke(ctx, CompanyService_UpdateCompany_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *companyServiceClient) GetCompany(ctx context.Context, in *GetCompanyRequest, opts ...grpc.CallOption) (*GetCompanyResponse, error) {
+	out := new(GetCompanyResponse)
+	err := c.cc.Invoke(ctx, CompanyService_GetCompany_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *companyServiceClient) ListCompanies(ctx context.Context, in *ListCompaniesRequest, opts ...grpc.CallOption) (*ListCompaniesResponse, error) {
+	out := new(ListCompaniesResponse)
+	err := c.cc.Invoke(ctx, CompanyService_ListCompanies_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *companyServiceClient) CompanyExists(ctx context.Context, in *CompanyExistsRequest, opts ...grpc.CallOption) (*CompanyExistsResponse, error) {
+	out := new(CompanyExistsResponse)
+	err := c.cc.Invoke(ctx, CompanyService_CompanyExists_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CompanyServiceServer is the server API for CompanyService service.
+// All implementations must embed UnimplementedCompanyServiceServer
+// for forward compatibility
+type CompanyServiceServer interface {
+	CreateCompany(context.Context, *CreateCompanyRequest) (*CreateCompanyResponse, error)
+	UpdateCompany(context.Context, *UpdateCompanyRequest) (*UpdateCompanyResponse, error)
+	GetCompany(context.Context, *GetCompanyRequest) (*GetCompanyResponse, error)
+	ListCompanies(context.Context, *ListCompaniesRequest) (*ListCompaniesResponse, error)
+	CompanyExists(context.Context, *CompanyExistsRequest) (*CompanyExistsResponse, error)
+	mustEmbedUnimplementedCompanyServiceServer()
+}
+
+// UnimplementedCompanyServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedCompanyServiceServer struct {
+}
+
+func (UnimplementedCompanyServiceServer) CreateCompany(context.Context, *CreateCompanyRequest) (*CreateCompanyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCompany not implemented")
+}
+func (UnimplementedCompanyServiceServer) UpdateCompany(context.Context, *UpdateCompanyRequest) (*UpdateCompanyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCompany not implemented")
+}
+func (UnimplementedCompanyServiceServer) GetCompany(context.Context, *GetCompanyRequest) (*GetCompanyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCompany not implemented")
+}
+func (UnimplementedCompanyServiceServer) ListCompanies(context.Context, *ListCompaniesRequest) (*ListCompaniesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCompanies not implemented")
+}
+func (UnimplementedCompanyServiceServer) CompanyExists(context.Context, *CompanyExistsRequest) (*CompanyExistsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompanyExists not implemented")
+}
+func (UnimplementedCompanyServiceServer) mustEmbedUnimplementedCompanyServiceServer() {}
+
+// UnsafeCompanyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CompanyServiceServer will
+// result in compilation errors.
+type UnsafeCompanyServiceServer interface {
+	mustEmbedUnimplementedCompanyServiceServer()
+}
+
+func RegisterCompanyServiceServer(s grpc.ServiceRegistrar, srv CompanyServiceServer) {
+	s.RegisterService(&CompanyService_ServiceDesc, srv)
+}
+
+func _CompanyService_CreateCompany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCompanyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompanyServiceServer).CreateCompany(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CompanyService_CreateCompany_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompanyServiceServer).CreateCompany(ctx, req.(*CreateCompanyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CompanyService_UpdateCompany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCompanyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompanyServiceServer).UpdateCompany(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CompanyService_UpdateCompany_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompanyServiceServer).UpdateCompany(ctx, req.(*UpdateCompanyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CompanyService_GetCompany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCompanyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompanyServiceServer).GetCompany(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CompanyService_GetCompany_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompanyServiceServer).GetCompany(ctx, req.(*GetCompanyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CompanyService_ListCompanies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCompaniesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompanyServiceServer).ListCompanies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CompanyService_ListCompanies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompanyServiceServer).ListCompanies(ctx, req.(*ListCompaniesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CompanyService_CompanyExists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompanyExistsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompanyServiceServer).CompanyExists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CompanyService_CompanyExists_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompanyServiceServer).CompanyExists(ctx, req.(*CompanyExistsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CompanyService_ServiceDesc is the grpc.ServiceDesc for CompanyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CompanyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "users.v1.CompanyService",
+	HandlerType: (*CompanyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateCompany",
+			Handler:    _CompanyService_CreateCompany_Handler,
+		},
+		{
+			MethodName: "UpdateCompany",
+			Handler:    _CompanyService_UpdateCompany_Handler,
+		},
+		{
+			MethodName: "GetCompany",
+			Handler:    _CompanyService_GetCompany_Handler,
+		},
+		{
+			MethodName: "ListCompanies",
+			Handler:    _CompanyService_ListCompanies_Handler,
+		},
+		{
+			MethodName: "CompanyExists",
+			Handler:    _CompanyService_CompanyExists_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "users/v1/users.proto",
+}
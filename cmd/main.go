@@ -1,9 +1,21 @@
 package main
 
 import (
+	"flag"
+	"os"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
 	"github.com/Zhiyenbek/sp-users-main-service/internal/app"
 )
 
 func main() {
-	app.Run()
+	var opts app.Options
+	flag.StringVar(&opts.ConfigFile, "config", config.DefaultFile, "path of the configuration file")
+	flag.StringVar(&opts.Profile, "profile", "", "configuration profile: dev, test or prod (default $USERS_PROFILE or dev)")
+	flag.StringVar(&opts.Storage, "storage", "", "storage: postgres, or memory for the demo mode (default app.storage)")
+	flag.Parse()
+
+	if err := app.Run(opts); err != nil {
+		os.Exit(1)
+	}
 }
@@ -1,6 +1,11 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/creasty/defaults"
@@ -8,36 +13,324 @@ import (
 )
 
 type Configs struct {
-	App   *AppConfig `json:"app" mapstructure:"app"`
-	DB    *DBConf    `json:"db" mapstructure:"db"`
-	Token *Token     `json:"token" mapstructure:"token"`
+	// Profile is the profile the configuration was loaded with
+	Profile string `json:"profile" mapstructure:"-"`
+
+	App   *AppConfig `json:"app" mapstructure:"app" default:"{}"`
+	GRPC  *GRPCConf  `json:"grpc" mapstructure:"grpc" default:"{}"`
+	DB    *DBConf    `json:"db" mapstructure:"db" default:"{}"`
+	Token *Token     `json:"token" mapstructure:"token" default:"{}"`
+	Redis *RedisConf `json:"redis" mapstructure:"redis" default:"{}"`
+	Cache *CacheConf `json:"cache" mapstructure:"cache" default:"{}"`
+
+	RateLimit *RateLimitConf `json:"rate_limit" mapstructure:"rate_limit" default:"{}"`
+	Jobs      *JobsConf      `json:"jobs" mapstructure:"jobs" default:"{}"`
+	SMTP      *SMTPConf      `json:"smtp" mapstructure:"smtp" default:"{}"`
+
+	Notifications *NotificationsConf `json:"notifications" mapstructure:"notifications" default:"{}"`
+	Events        *EventsConf        `json:"events" mapstructure:"events" default:"{}"`
+	Webhooks      *WebhooksConf      `json:"webhooks" mapstructure:"webhooks" default:"{}"`
+	Tracing       *TracingConf       `json:"tracing" mapstructure:"tracing" default:"{}"`
+	Health        *HealthConf        `json:"health" mapstructure:"health" default:"{}"`
+	Log           *LogConf           `json:"log" mapstructure:"log" default:"{}"`
+
+	CORS            *CORSConf            `json:"cors" mapstructure:"cors" default:"{}"`
+	SecurityHeaders *SecurityHeadersConf `json:"security_headers" mapstructure:"security_headers" default:"{}"`
+	CSRF            *CSRFConf            `json:"csrf" mapstructure:"csrf" default:"{}"`
+
+	Provisioning *ProvisioningConf `json:"provisioning" mapstructure:"provisioning" default:"{}"`
+	Invitations  *InvitationsConf  `json:"invitations" mapstructure:"invitations" default:"{}"`
+
+	DomainVerification *DomainVerificationConf `json:"domain_verification" mapstructure:"domain_verification" default:"{}"`
 }
 
 type AppConfig struct {
-	TimeOut time.Duration `json:"timeout" mapstructure:"timeout"`
-	Port    int           `json:"port" mapstructure:"port"`
+	TimeOut time.Duration `json:"timeout" mapstructure:"timeout" default:"60s"`
+	Port    int           `json:"port" mapstructure:"port" default:"3000"`
+	// Storage is postgres, or memory for the demo mode, which keeps candidates, recruiters and
+	// companies in memory and needs no database.
+	Storage string `json:"storage" mapstructure:"storage" default:"postgres"`
+}
+
+type GRPCConf struct {
+	Enabled bool `json:"enabled" mapstructure:"enabled"`
+	Port    int  `json:"port" mapstructure:"port" default:"9090"`
+	// Tokens are the bearer tokens of the services allowed to call the API.
+	Tokens   []string `json:"tokens" mapstructure:"tokens"`
+	CertFile string   `json:"cert_file" mapstructure:"cert_file"`
+	KeyFile  string   `json:"key_file" mapstructure:"key_file"`
+	// ClientCAFile turns on mTLS, only clients with a certificate signed by this CA are accepted.
+	ClientCAFile string `json:"client_ca_file" mapstructure:"client_ca_file"`
 }
 
 type DBConf struct {
-	Host     string        `json:"host" mapstructure:"host"`
-	Port     int           `json:"port" mapstructure:"port"`
-	Username string        `json:"username" mapstructure:"user"`
+	Host     string        `json:"host" mapstructure:"host" default:"localhost"`
+	Port     int           `json:"port" mapstructure:"port" default:"5432"`
+	Username string        `json:"username" mapstructure:"user" default:"postgres"`
 	Password string        `json:"password" mapstructure:"password"`
-	DBName   string        `json:"dbname" mapstructure:"db_name"`
-	SSLMode  string        `json:"sslmode" mapstructure:"ssl_mode"`
-	TimeOut  time.Duration `json:"timeout" mapstructure:"timeout"`
+	DBName   string        `json:"dbname" mapstructure:"db_name" default:"users"`
+	SSLMode  string        `json:"sslmode" mapstructure:"ssl_mode" default:"disable"`
+	TimeOut  time.Duration `json:"timeout" mapstructure:"timeout" default:"20s"`
+	// TxIsolation is the isolation level of the transactions: read_committed, repeatable_read or serializable.
+	TxIsolation string `json:"tx_isolation" mapstructure:"tx_isolation" default:"repeatable_read"`
+	// TxMaxAttempts bounds how many times a transaction is run when it fails on a serialization
+	// failure or a deadlock, waiting about TxRetryBackoff, doubled on every attempt, in between.
+	TxMaxAttempts  int           `json:"tx_max_attempts" mapstructure:"tx_max_attempts" default:"3"`
+	TxRetryBackoff time.Duration `json:"tx_retry_backoff" mapstructure:"tx_retry_backoff" default:"20ms"`
+}
+
+type RedisConf struct {
+	Host     string `json:"host" mapstructure:"host" default:"localhost"`
+	Port     int    `json:"port" mapstructure:"port" default:"6379"`
+	Password string `json:"password" mapstructure:"password"`
+	DB       int    `json:"db" mapstructure:"db"`
 }
 
+type CacheConf struct {
+	Enabled            bool          `json:"enabled" mapstructure:"enabled"`
+	KeyPrefix          string        `json:"key_prefix" mapstructure:"key_prefix" default:"users-main:"`
+	CandidateTTL       time.Duration `json:"candidate_ttl" mapstructure:"candidate_ttl" default:"5m"`
+	RecruiterTTL       time.Duration `json:"recruiter_ttl" mapstructure:"recruiter_ttl" default:"2m"`
+	CompanyTTL         time.Duration `json:"company_ttl" mapstructure:"company_ttl" default:"10m"`
+	FallbackTTL        time.Duration `json:"fallback_ttl" mapstructure:"fallback_ttl" default:"30s"`
+	FallbackMaxEntries int           `json:"fallback_max_entries" mapstructure:"fallback_max_entries" default:"10000"`
+}
+
+type RateLimitConf struct {
+	Enabled   bool   `json:"enabled" mapstructure:"enabled"`
+	KeyPrefix string `json:"key_prefix" mapstructure:"key_prefix" default:"users-main:ratelimit:"`
+	// TrustedProxies are the proxies whose X-Forwarded-For header is used to find the client IP.
+	TrustedProxies []string          `json:"trusted_proxies" mapstructure:"trusted_proxies"`
+	IP             *RateLimit        `json:"ip" mapstructure:"ip"`
+	User           *RateLimit        `json:"user" mapstructure:"user"`
+	Routes         []*RouteRateLimit `json:"routes" mapstructure:"routes"`
+}
+
+// RateLimit is a token bucket refilled with Rate requests per second, holding at most Burst requests.
+type RateLimit struct {
+	Rate  float64 `json:"rate" mapstructure:"rate"`
+	Burst int     `json:"burst" mapstructure:"burst"`
+}
+
+type RouteRateLimit struct {
+	Method    string `json:"method" mapstructure:"method"`
+	Path      string `json:"path" mapstructure:"path"`
+	RateLimit `mapstructure:",squash"`
+}
+
+// Token configures the verification of the access tokens issued by the auth service, sent in the
+// Authorization header as bearer tokens or in the access_token cookie. HS256 tokens are verified with
+// TokenSecret, RS256 and EdDSA tokens with the keys published at JWKSURL.
 type Token struct {
 	TokenSecret string `json:"token_secret" mapstructure:"token_secret"`
+	// JWKSURL serves the JSON Web Key Set of the auth service. It is fetched every JWKSRefreshInterval,
+	// and as soon as a token names an unknown key, at most once per JWKSMinRefreshInterval.
+	JWKSURL                string        `json:"jwks_url" mapstructure:"jwks_url"`
+	JWKSRefreshInterval    time.Duration `json:"jwks_refresh_interval" mapstructure:"jwks_refresh_interval" default:"10m"`
+	JWKSMinRefreshInterval time.Duration `json:"jwks_min_refresh_interval" mapstructure:"jwks_min_refresh_interval" default:"30s"`
+	JWKSTimeout            time.Duration `json:"jwks_timeout" mapstructure:"jwks_timeout" default:"5s"`
+	// Revocation rejects the tokens the auth service revoked in Redis, it is turned off when left out
+	Revocation *RevocationConf `json:"revocation" mapstructure:"revocation" default:"{}"`
+}
+
+// RevocationConf configures the denylist the auth service keeps in Redis: KeyPrefix+"jti:"+<token id>
+// revokes a single token, and KeyPrefix+"user:"+<user public id> holding a unix time revokes every token
+// of the user issued before it.
+type RevocationConf struct {
+	KeyPrefix string `json:"key_prefix" mapstructure:"key_prefix" default:"auth:revoked:"`
+	// FailClosed rejects every token while Redis is unavailable, instead of accepting them unchecked
+	FailClosed bool `json:"fail_closed" mapstructure:"fail_closed"`
+}
+
+type JobsConf struct {
+	SavedSearchInterval time.Duration `json:"saved_search_interval" mapstructure:"saved_search_interval"`
+}
+
+type SMTPConf struct {
+	Host     string `json:"host" mapstructure:"host"`
+	Port     int    `json:"port" mapstructure:"port" default:"25"`
+	Username string `json:"username" mapstructure:"username"`
+	Password string `json:"password" mapstructure:"password"`
+	From     string `json:"from" mapstructure:"from"`
+}
+
+type NotificationsConf struct {
+	WorkerInterval time.Duration `json:"worker_interval" mapstructure:"worker_interval"`
+	BatchSize      int           `json:"batch_size" mapstructure:"batch_size" default:"50"`
+	MaxAttempts    int           `json:"max_attempts" mapstructure:"max_attempts" default:"5"`
+	RetryBackoff   time.Duration `json:"retry_backoff" mapstructure:"retry_backoff" default:"1m"`
+}
+
+type EventsConf struct {
+	Publisher     string        `json:"publisher" mapstructure:"publisher" default:"log"`
+	FilePath      string        `json:"file_path" mapstructure:"file_path" default:"events.jsonl"`
+	NATSURL       string        `json:"nats_url" mapstructure:"nats_url"`
+	SubjectPrefix string        `json:"subject_prefix" mapstructure:"subject_prefix" default:"users"`
+	RelayInterval time.Duration `json:"relay_interval" mapstructure:"relay_interval"`
+	BatchSize     int           `json:"batch_size" mapstructure:"batch_size" default:"100"`
+	RetryBackoff  time.Duration `json:"retry_backoff" mapstructure:"retry_backoff" default:"10s"`
+}
+
+type WebhooksConf struct {
+	WorkerInterval time.Duration `json:"worker_interval" mapstructure:"worker_interval"`
+	BatchSize      int           `json:"batch_size" mapstructure:"batch_size" default:"50"`
+	MaxAttempts    int           `json:"max_attempts" mapstructure:"max_attempts" default:"8"`
+	RetryBackoff   time.Duration `json:"retry_backoff" mapstructure:"retry_backoff" default:"30s"`
+	Timeout        time.Duration `json:"timeout" mapstructure:"timeout" default:"10s"`
+	// AllowPrivateNetworks lets webhooks reach loopback and private addresses, for receivers
+	// running next to the service in development. It is refused in prod.
+	AllowPrivateNetworks bool `json:"allow_private_networks" mapstructure:"allow_private_networks"`
+}
+
+type TracingConf struct {
+	Exporter    string `json:"exporter" mapstructure:"exporter" default:"none"`
+	ServiceName string `json:"service_name" mapstructure:"service_name" default:"users-service"`
+	// SampleRatio is the share of new traces that are recorded. Traces started by a caller follow the caller's decision.
+	SampleRatio  float64 `json:"sample_ratio" mapstructure:"sample_ratio" default:"1"`
+	FilePath     string  `json:"file_path" mapstructure:"file_path" default:"traces.jsonl"`
+	OTLPEndpoint string  `json:"otlp_endpoint" mapstructure:"otlp_endpoint" default:"localhost:4317"`
+	OTLPInsecure bool    `json:"otlp_insecure" mapstructure:"otlp_insecure"`
+}
+
+type HealthConf struct {
+	CheckTimeout time.Duration `json:"check_timeout" mapstructure:"check_timeout" default:"2s"`
+	// ShutdownDelay is how long the service reports it is not ready before it stops accepting
+	// connections, so load balancers stop sending traffic first.
+	ShutdownDelay time.Duration `json:"shutdown_delay" mapstructure:"shutdown_delay" default:"5s"`
+}
+
+type LogConf struct {
+	// Level is one of debug, info, warn or error.
+	Level string `json:"level" mapstructure:"level" default:"info"`
+	// Format is json or console.
+	Format string `json:"format" mapstructure:"format" default:"json"`
+	// RedactKeys are redacted from log fields in addition to credentials and personal fields.
+	RedactKeys []string `json:"redact_keys" mapstructure:"redact_keys"`
+}
+
+type CORSConf struct {
+	// AllowedOrigins are the origins of the web apps allowed to call the API, e.g. https://app.example.com.
+	// A subdomain can be a wildcard, e.g. https://*.example.com. No origin is allowed when empty.
+	AllowedOrigins []string `json:"allowed_origins" mapstructure:"allowed_origins"`
+	// AllowCredentials lets the allowed origins send the access_token cookie.
+	AllowCredentials bool          `json:"allow_credentials" mapstructure:"allow_credentials" default:"true"`
+	MaxAge           time.Duration `json:"max_age" mapstructure:"max_age" default:"12h"`
 }
 
-func New() (*Configs, error) {
-	configFile := "config/config.yaml"
-	viper.SetConfigFile(configFile)
-	if err := viper.ReadInConfig(); err != nil {
+type SecurityHeadersConf struct {
+	// HSTSMaxAge is how long browsers only use HTTPS for the service. Zero leaves out the header.
+	HSTSMaxAge            time.Duration `json:"hsts_max_age" mapstructure:"hsts_max_age" default:"8760h"`
+	HSTSIncludeSubdomains bool          `json:"hsts_include_subdomains" mapstructure:"hsts_include_subdomains" default:"true"`
+	// ContentSecurityPolicy applies to the API responses. The Swagger UI has its own policy.
+	ContentSecurityPolicy string `json:"content_security_policy" mapstructure:"content_security_policy" default:"default-src 'none'; frame-ancestors 'none'"`
+	FrameOptions          string `json:"frame_options" mapstructure:"frame_options" default:"DENY"`
+	ReferrerPolicy        string `json:"referrer_policy" mapstructure:"referrer_policy" default:"no-referrer"`
+}
+
+// CSRFConf configures the double submit protection of the requests authenticated with the access_token cookie.
+type CSRFConf struct {
+	Enabled      bool   `json:"enabled" mapstructure:"enabled" default:"true"`
+	CookieName   string `json:"cookie_name" mapstructure:"cookie_name" default:"csrf_token"`
+	HeaderName   string `json:"header_name" mapstructure:"header_name" default:"X-CSRF-Token"`
+	CookieDomain string `json:"cookie_domain" mapstructure:"cookie_domain"`
+	CookieSecure bool   `json:"cookie_secure" mapstructure:"cookie_secure" default:"true"`
+	// CookieSameSite is strict, lax or none. Web apps on another site than the API need none.
+	CookieSameSite string `json:"cookie_same_site" mapstructure:"cookie_same_site" default:"lax"`
+}
+
+// ProvisioningConf configures the internal API the auth service creates the profiles of the users
+// signing up with.
+type ProvisioningConf struct {
+	// Tokens are the bearer tokens of the services allowed to call the API.
+	Tokens []string `json:"tokens" mapstructure:"tokens"`
+}
+
+// InvitationsConf configures the invitations company admins send recruiters by email
+type InvitationsConf struct {
+	// TTL is how long an invitation can be accepted
+	TTL time.Duration `json:"ttl" mapstructure:"ttl" default:"168h"`
+	// AcceptURL is the page of the web app accepting invitations, linked in the email with the
+	// token in its token query parameter. The email shows the bare token when it is empty.
+	AcceptURL string `json:"accept_url" mapstructure:"accept_url"`
+}
+
+// DomainVerificationConf configures how companies prove they own their email domains
+type DomainVerificationConf struct {
+	// Resolver looks up the TXT records of the domains: dns, or stub for local development, which
+	// answers with Records.
+	Resolver      string        `json:"resolver" mapstructure:"resolver" default:"dns"`
+	Records       []TXTRecord   `json:"records" mapstructure:"records"`
+	LookupTimeout time.Duration `json:"lookup_timeout" mapstructure:"lookup_timeout" default:"5s"`
+	// CodeTTL is how long the code sent to an address on the domain can be used
+	CodeTTL time.Duration `json:"code_ttl" mapstructure:"code_ttl" default:"24h"`
+}
+
+type TXTRecord struct {
+	Name  string `json:"name" mapstructure:"name"`
+	Value string `json:"value" mapstructure:"value"`
+}
+
+const (
+	// DefaultFile is read when no configuration file is given
+	DefaultFile = "config/config.yaml"
+	// EnvPrefix starts the environment variables overriding the configuration,
+	// e.g. USERS_DB_PASSWORD overrides db.password
+	EnvPrefix = "USERS"
+)
+
+const (
+	ProfileDev  = "dev"
+	ProfileTest = "test"
+	ProfileProd = "prod"
+)
+
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
+// New loads the configuration in layers, each overriding the previous one: the defaults declared on
+// the fields, the file, the file of the profile next to it (config.prod.yaml for config.yaml) and the
+// environment variables, then the overrides given on the command line, keyed like the file (app.storage).
+// The profile is taken from USERS_PROFILE when empty, and is dev by default.
+// Sections other than app, db, token, tracing, health, log, cors, security_headers and csrf are turned off
+// unless configured.
+func New(file, profile string, overrides map[string]interface{}) (*Configs, error) {
+	if file == "" {
+		file = DefaultFile
+	}
+	if profile == "" {
+		profile = os.Getenv(EnvPrefix + "_PROFILE")
+	}
+	if profile == "" {
+		profile = ProfileDev
+	}
+	switch profile {
+	case ProfileDev, ProfileTest, ProfileProd:
+	default:
+		return nil, fmt.Errorf("unknown profile %q, expected %s, %s or %s", profile, ProfileDev, ProfileTest, ProfileProd)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
 		return nil, err
 	}
+	profileFile := strings.TrimSuffix(file, filepath.Ext(file)) + "." + profile + filepath.Ext(file)
+	if _, err := os.Stat(profileFile); err == nil {
+		v.SetConfigFile(profileFile)
+		if err := v.MergeInConfig(); err != nil {
+			return nil, err
+		}
+	}
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	if err := bindEnv(v, reflect.TypeOf(Configs{}), ""); err != nil {
+		return nil, err
+	}
+	for key, value := range overrides {
+		v.Set(key, value)
+	}
 
 	cfg := &Configs{}
 
@@ -45,9 +338,74 @@ func New() (*Configs, error) {
 		return nil, err
 	}
 
-	if err := viper.Unmarshal(&cfg); err != nil {
+	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, err
 	}
 
+	settings := v.AllSettings()
+	optional(settings, "grpc", &cfg.GRPC)
+	optional(settings, "redis", &cfg.Redis)
+	optional(settings, "cache", &cfg.Cache)
+	optional(settings, "rate_limit", &cfg.RateLimit)
+	optional(settings, "jobs", &cfg.Jobs)
+	optional(settings, "smtp", &cfg.SMTP)
+	optional(settings, "notifications", &cfg.Notifications)
+	optional(settings, "events", &cfg.Events)
+	optional(settings, "webhooks", &cfg.Webhooks)
+	optional(settings, "provisioning", &cfg.Provisioning)
+	optional(settings, "invitations", &cfg.Invitations)
+	optional(settings, "domain_verification", &cfg.DomainVerification)
+	token, _ := settings["token"].(map[string]interface{})
+	optional(token, "revocation", &cfg.Token.Revocation)
+	cfg.Profile = profile
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
+
+// bindEnv binds every field of t to its environment variable, so fields missing from the file can be set as well
+func bindEnv(v *viper.Viper, t reflect.Type, prefix string) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		if opts == "squash" {
+			if err := bindEnv(v, field.Type, prefix); err != nil {
+				return err
+			}
+			continue
+		}
+		key := prefix + name
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		switch {
+		case fieldType.Kind() == reflect.Struct:
+			if err := bindEnv(v, fieldType, key+"."); err != nil {
+				return err
+			}
+		case fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() != reflect.String:
+			// lists of sections, like the route rate limits, can only be set in the file
+		default:
+			if err := v.BindEnv(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// optional turns off the section key unless it is set in the file or the environment
+func optional[T any](settings map[string]interface{}, key string, section **T) {
+	if _, ok := settings[key]; !ok {
+		*section = nil
+	}
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxDeliveryAttempts bounds the attempts of notifications and webhook deliveries, which wait longer
+// after every failed one
+const maxDeliveryAttempts = 100
+
+// Validate reports every invalid setting, so all of them can be fixed at once
+func (c *Configs) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	oneOf := func(key, value string, allowed ...string) {
+		for _, a := range allowed {
+			if value == a {
+				return
+			}
+		}
+		errs = append(errs, fmt.Errorf("%s must be one of %s, got %q", key, strings.Join(allowed, ", "), value))
+	}
+
+	check(c.App.TimeOut > 0, "app.timeout must be greater than zero")
+	check(validPort(c.App.Port), "app.port must be between 1 and 65535, got %d", c.App.Port)
+
+	oneOf("app.storage", c.App.Storage, StoragePostgres, StorageMemory)
+	check(c.App.Storage != StorageMemory || c.Profile != ProfileProd, "app.storage cannot be memory with the prod profile, the data would be lost on restart")
+
+	if c.App.Storage == StoragePostgres {
+		check(c.DB.Host != "", "db.host is required")
+		check(validPort(c.DB.Port), "db.port must be between 1 and 65535, got %d", c.DB.Port)
+		check(c.DB.DBName != "", "db.db_name is required")
+		check(c.DB.TimeOut > 0, "db.timeout must be greater than zero")
+		oneOf("db.tx_isolation", c.DB.TxIsolation, "read_committed", "repeatable_read", "serializable")
+		check(c.DB.TxMaxAttempts > 0, "db.tx_max_attempts must be greater than zero")
+		check(c.DB.TxRetryBackoff >= 0, "db.tx_retry_backoff must not be negative")
+	}
+
+	check(c.Token.TokenSecret != "" || c.Token.JWKSURL != "", "token.token_secret or token.jwks_url is required, set it in the file or in %s_TOKEN_TOKEN_SECRET or %s_TOKEN_JWKS_URL", EnvPrefix, EnvPrefix)
+	if c.Token.JWKSURL != "" {
+		check(strings.HasPrefix(c.Token.JWKSURL, "https://") || strings.HasPrefix(c.Token.JWKSURL, "http://"), "token.jwks_url must start with http:// or https://, got %q", c.Token.JWKSURL)
+		check(c.Token.JWKSRefreshInterval > 0 && c.Token.JWKSTimeout > 0, "token.jwks_refresh_interval and token.jwks_timeout must be greater than zero")
+		check(c.Token.JWKSMinRefreshInterval >= 0, "token.jwks_min_refresh_interval must not be negative")
+	}
+	check(c.Token.Revocation == nil || c.Redis != nil, "token.revocation requires redis")
+
+	if c.GRPC != nil && c.GRPC.Enabled {
+		check(validPort(c.GRPC.Port), "grpc.port must be between 1 and 65535, got %d", c.GRPC.Port)
+		check(c.GRPC.Port != c.App.Port, "grpc.port must differ from app.port")
+		check(len(c.GRPC.Tokens) > 0 || c.GRPC.ClientCAFile != "", "grpc.tokens or grpc.client_ca_file is required when grpc is enabled")
+		check((c.GRPC.CertFile == "") == (c.GRPC.KeyFile == ""), "grpc.cert_file and grpc.key_file must be set together")
+		check(c.GRPC.ClientCAFile == "" || c.GRPC.CertFile != "", "grpc.cert_file is required when grpc.client_ca_file is set")
+	}
+	if c.Redis != nil {
+		check(c.Redis.Host != "", "redis.host is required")
+		check(validPort(c.Redis.Port), "redis.port must be between 1 and 65535, got %d", c.Redis.Port)
+	}
+	if c.Cache != nil && c.Cache.Enabled {
+		check(c.Cache.CandidateTTL > 0 && c.Cache.RecruiterTTL > 0 && c.Cache.CompanyTTL > 0, "cache TTLs must be greater than zero")
+	}
+	if c.RateLimit != nil && c.RateLimit.Enabled {
+		for _, limit := range []struct {
+			key   string
+			limit *RateLimit
+		}{{"rate_limit.ip", c.RateLimit.IP}, {"rate_limit.user", c.RateLimit.User}} {
+			if limit.limit != nil {
+				check(limit.limit.Rate >= 0 && limit.limit.Burst >= 0, "%s rate and burst must not be negative", limit.key)
+			}
+		}
+		for i, route := range c.RateLimit.Routes {
+			check(route.Method != "" && route.Path != "", "rate_limit.routes[%d] needs a method and a path", i)
+		}
+	}
+	if c.Jobs != nil {
+		check(c.Jobs.SavedSearchInterval >= 0, "jobs.saved_search_interval must not be negative")
+	}
+	if c.Notifications != nil && c.Notifications.WorkerInterval > 0 {
+		check(c.Notifications.BatchSize > 0, "notifications.batch_size must be greater than zero")
+		check(c.Notifications.MaxAttempts > 0, "notifications.max_attempts must be greater than zero")
+		check(c.Notifications.MaxAttempts <= maxDeliveryAttempts, "notifications.max_attempts must be at most %d", maxDeliveryAttempts)
+	}
+	if c.Events != nil {
+		oneOf("events.publisher", c.Events.Publisher, "log", "file", "nats")
+		check(c.Events.Publisher != "file" || c.Events.FilePath != "", "events.file_path is required for the file publisher")
+		check(c.Events.Publisher != "nats" || c.Events.NATSURL != "", "events.nats_url is required for the nats publisher")
+		check(c.Events.RelayInterval <= 0 || c.Events.BatchSize > 0, "events.batch_size must be greater than zero")
+	}
+	if c.Webhooks != nil && c.Webhooks.WorkerInterval > 0 {
+		check(c.Webhooks.BatchSize > 0, "webhooks.batch_size must be greater than zero")
+		check(c.Webhooks.MaxAttempts > 0, "webhooks.max_attempts must be greater than zero")
+		check(c.Webhooks.MaxAttempts <= maxDeliveryAttempts, "webhooks.max_attempts must be at most %d", maxDeliveryAttempts)
+		check(c.Webhooks.Timeout > 0, "webhooks.timeout must be greater than zero")
+	}
+	if c.Webhooks != nil {
+		check(!c.Webhooks.AllowPrivateNetworks || c.Profile != ProfileProd, "webhooks.allow_private_networks must not be set in the %s profile", ProfileProd)
+	}
+	if c.Provisioning != nil {
+		check(len(c.Provisioning.Tokens) > 0, "provisioning.tokens is required when provisioning is configured, set it in the file or in %s_PROVISIONING_TOKENS", EnvPrefix)
+		for i, token := range c.Provisioning.Tokens {
+			check(token != "", "provisioning.tokens[%d] must not be empty", i)
+		}
+	}
+	if c.Invitations != nil {
+		check(c.Invitations.TTL > 0, "invitations.ttl must be greater than zero")
+		check(c.Invitations.AcceptURL == "" || strings.HasPrefix(c.Invitations.AcceptURL, "https://") || strings.HasPrefix(c.Invitations.AcceptURL, "http://"),
+			"invitations.accept_url must start with http:// or https://, got %q", c.Invitations.AcceptURL)
+	}
+	if c.DomainVerification != nil {
+		oneOf("domain_verification.resolver", c.DomainVerification.Resolver, "dns", "stub")
+		check(c.DomainVerification.LookupTimeout > 0, "domain_verification.lookup_timeout must be greater than zero")
+		check(c.DomainVerification.CodeTTL > 0, "domain_verification.code_ttl must be greater than zero")
+	}
+
+	oneOf("tracing.exporter", c.Tracing.Exporter, "none", "stdout", "file", "otlp")
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+	check(c.Tracing.Exporter != "file" || c.Tracing.FilePath != "", "tracing.file_path is required for the file exporter")
+	check(c.Tracing.Exporter != "otlp" || c.Tracing.OTLPEndpoint != "", "tracing.otlp_endpoint is required for the otlp exporter")
+
+	check(c.Health.CheckTimeout > 0, "health.check_timeout must be greater than zero")
+	check(c.Health.ShutdownDelay >= 0 && c.Health.ShutdownDelay < c.App.TimeOut, "health.shutdown_delay must be shorter than app.timeout")
+
+	oneOf("log.level", c.Log.Level, "debug", "info", "warn", "error")
+	oneOf("log.format", c.Log.Format, "json", "console")
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		check(origin != "*" || !c.CORS.AllowCredentials, "cors.allowed_origins cannot allow every origin when cors.allow_credentials is set, list the origins instead")
+		check(origin == "*" || strings.HasPrefix(origin, "https://") || strings.HasPrefix(origin, "http://"), "cors.allowed_origins must start with http:// or https://, got %q", origin)
+		check(!strings.HasSuffix(origin, "/"), "cors.allowed_origins must not end with a slash, got %q", origin)
+	}
+	check(c.SecurityHeaders.HSTSMaxAge >= 0, "security_headers.hsts_max_age must not be negative")
+	if c.CSRF.Enabled {
+		check(c.CSRF.CookieName != "" && c.CSRF.HeaderName != "", "csrf.cookie_name and csrf.header_name are required")
+		oneOf("csrf.cookie_same_site", c.CSRF.CookieSameSite, "strict", "lax", "none")
+		check(c.CSRF.CookieSameSite != "none" || c.CSRF.CookieSecure, "csrf.cookie_secure is required when csrf.cookie_same_site is none")
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+}
+
+func validPort(port int) bool {
+	return port > 0 && port <= 65535
+}
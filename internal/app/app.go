@@ -2,73 +2,202 @@ package app
 
 import (
 	"context"
-	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/auth"
+	grpchandler "github.com/Zhiyenbek/sp-users-main-service/internal/handler/grpc"
 	handler "github.com/Zhiyenbek/sp-users-main-service/internal/handler/http"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/health"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/metrics"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/openapi"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/ratelimit"
 	"github.com/Zhiyenbek/sp-users-main-service/internal/repository"
 	"github.com/Zhiyenbek/sp-users-main-service/internal/repository/connection"
 	"github.com/Zhiyenbek/sp-users-main-service/internal/service"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/tracing"
+	"github.com/go-redis/redis/v7"
 	"go.uber.org/zap"
 )
 
-func Run() error {
-	logger, _ := zap.NewDevelopment(zap.AddStacktrace(zap.PanicLevel))
+// Options are the command line options of the service
+type Options struct {
+	// ConfigFile is the path of the configuration file
+	ConfigFile string
+	// Profile selects the profile file merged over the configuration file
+	Profile string
+	// Storage overrides app.storage when set
+	Storage string
+}
+
+func Run(opts Options) error {
+	overrides := map[string]interface{}{}
+	if opts.Storage != "" {
+		overrides["app.storage"] = opts.Storage
+	}
+	cfg, err := config.New(opts.ConfigFile, opts.Profile, overrides)
+	if err != nil {
+		// the configured logger is not known yet, so the error is logged with the default one
+		if logger, logErr := logging.New(nil); logErr == nil {
+			logger.Sugar().Errorf("error while defining config %v", err)
+			logger.Sync()
+		}
+		return err
+	}
+	logger, err := logging.New(cfg.Log)
+	if err != nil {
+		return err
+	}
 
 	defer logger.Sync() // flushes buffer, if any
 	sugar := logger.Sugar()
 
-	cfg, err := config.New()
+	shutdownTracing, err := tracing.Init(cfg.Tracing)
 	if err != nil {
-		sugar.Errorf("error while defining config %v", err)
+		sugar.Errorf("error while initializing tracing: %v", err)
 		return err
 	}
-	db, err := connection.NewPostgresDB(cfg.DB)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.App.TimeOut)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			sugar.Errorf("error while flushing traces: %v", err)
+		}
+	}()
+	var rdb *redis.Client
+	if cfg.Redis != nil {
+		rdb = connection.NewRedisClient(cfg.Redis, sugar)
+		defer rdb.Close()
+	}
+	checker := health.NewChecker(cfg.Health.CheckTimeout)
+	if rdb != nil {
+		// Redis only backs the cache and the rate limiter, which fall back to memory without it
+		checker.Optional("redis", health.Redis(rdb))
+	}
+	var repos *repository.Repository
+	if cfg.App.Storage == config.StorageMemory {
+		store := repository.NewMemoryStore()
+		demo, err := store.SeedDemo(context.Background())
+		if err != nil {
+			sugar.Errorf("error while seeding demo data: %v", err)
+			return err
+		}
+		sugar.Warnw("demo mode, candidates, recruiters and companies are kept in memory and lost on shutdown, the other features are not available",
+			"company", demo.CompanyPublicID, "recruiter", demo.RecruiterPublicID, "candidates", demo.CandidatePublicIDs)
+		repos = repository.NewMemory(store)
+	} else {
+		db, err := connection.NewPostgresDB(cfg.DB, sugar)
+		if err != nil {
+			sugar.Errorf("error while creating database: %v", err)
+			return err
+		}
+		defer db.Close()
+		if err := metrics.RegisterPool(db); err != nil {
+			sugar.Errorf("error while registering database pool metrics: %v", err)
+			return err
+		}
+		checker.Require("postgres", health.Postgres(db))
+		checker.Require("schema", health.Schema(db, connection.SchemaTables))
+		repos = repository.New(db, rdb, cfg, sugar)
+	}
+	services := service.New(repos, sugar, cfg)
+	limiter := ratelimit.NewMemoryLimiter()
+	if rdb != nil {
+		prefix := ""
+		if cfg.RateLimit != nil {
+			prefix = cfg.RateLimit.KeyPrefix
+		}
+		limiter = ratelimit.NewFallbackLimiter(ratelimit.NewRedisLimiter(rdb, prefix), limiter, sugar)
+	}
+	spec, err := openapi.Load()
 	if err != nil {
-		sugar.Errorf("error while creating database: %v", err)
+		sugar.Errorf("error while loading openapi document: %v", err)
+		return err
+	}
+	keysCtx, stopKeys := context.WithCancel(context.Background())
+	defer stopKeys()
+	verifier := newVerifier(keysCtx, cfg, rdb, checker, sugar)
+	handlers := handler.New(services, sugar, cfg, limiter, spec, checker, verifier)
+	router := handlers.InitRoutes()
+	if err := spec.CheckRoutes(router.Routes()); err != nil {
+		sugar.Errorf("error while checking routes: %v", err)
 		return err
 	}
-	defer db.Close()
-	repos := repository.New(db, cfg, sugar)
-	services := service.New(repos, sugar, cfg)
-	handlers := handler.New(services, sugar, cfg)
+
+	defer func() {
+		if err := services.ClosePublisher(); err != nil {
+			sugar.Errorf("error while closing event publisher: %v", err)
+		}
+	}()
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	if cfg.App.Storage == config.StoragePostgres {
+		// the jobs work on the tables the memory storage does not have
+		startJobs(jobsCtx, cfg, services, sugar)
+	}
 
 	port, ok := os.LookupEnv("PORT")
 	if !ok {
-		log.Println("Couldn't get port. Using config port instead")
+		sugar.Info("Couldn't get port. Using config port instead")
 		port = strconv.Itoa(cfg.App.Port)
 
 	}
 
 	srv := http.Server{
 		Addr:    ":" + port,
-		Handler: handlers.InitRoutes(),
+		Handler: router,
 	}
 	errChan := make(chan error, 1)
 	go func(errChan chan<- error) {
-		sugar.Infof("server on port: %d have started", port)
+		sugar.Infof("server on port: %s have started", port)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			sugar.Error(err)
 			errChan <- err
 		}
 	}(errChan)
 
+	if cfg.GRPC != nil && cfg.GRPC.Enabled {
+		grpcServer, err := grpchandler.New(services, sugar, cfg.GRPC)
+		if err != nil {
+			sugar.Errorf("error while creating grpc server: %v", err)
+			return err
+		}
+		lis, err := net.Listen("tcp", ":"+strconv.Itoa(cfg.GRPC.Port))
+		if err != nil {
+			sugar.Errorf("error while listening for grpc: %v", err)
+			return err
+		}
+		defer grpcServer.GracefulStop()
+		go func(errChan chan<- error) {
+			sugar.Infof("grpc server on port: %d have started", cfg.GRPC.Port)
+			if err := grpcServer.Serve(lis); err != nil {
+				sugar.Error(err)
+				errChan <- err
+			}
+		}(errChan)
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
 
 	select {
 	case <-quit:
-		log.Println("killing signal was received, shutting down the server")
+		sugar.Info("killing signal was received, shutting down the server")
 	case err := <-errChan:
 		sugar.Errorf("ERROR: HTTP server error received: %v", err)
 	}
 
-	log.Println("Shutting down server...")
+	sugar.Info("Shutting down server...")
+	// readiness fails from now on, so load balancers stop sending requests before the server closes
+	checker.Shutdown()
+	time.Sleep(cfg.Health.ShutdownDelay)
 
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.App.TimeOut)
 	defer cancel()
@@ -78,3 +207,30 @@ func Run() error {
 	return nil
 
 }
+
+// newVerifier creates the verifier of the access tokens. The keys published by the auth service are
+// fetched now and then every token.jwks_refresh_interval until ctx is cancelled.
+func newVerifier(ctx context.Context, cfg *config.Configs, rdb *redis.Client, checker *health.Checker, sugar *zap.SugaredLogger) *auth.Verifier {
+	var keys *auth.KeySet
+	if cfg.Token.JWKSURL != "" {
+		client := &http.Client{Timeout: cfg.Token.JWKSTimeout}
+		keys = auth.NewKeySet(cfg.Token.JWKSURL, client, cfg.Token.JWKSMinRefreshInterval, sugar)
+		// tokens signed with a key that could not be fetched yet fetch it again, so the service starts anyway
+		if err := keys.Refresh(ctx); err != nil {
+			sugar.Errorf("error while fetching signing keys: %v", err)
+		}
+		checker.Optional("jwks", keys.Check)
+		go runPeriodically(ctx, cfg.Token.JWKSRefreshInterval, func(ctx context.Context) {
+			if err := keys.Refresh(ctx); err != nil {
+				sugar.Errorf("error while refreshing signing keys: %v", err)
+			}
+		})
+	}
+	var denylist auth.Denylist
+	failClosed := false
+	if cfg.Token.Revocation != nil && rdb != nil {
+		denylist = auth.NewRedisDenylist(rdb, cfg.Token.Revocation.KeyPrefix)
+		failClosed = cfg.Token.Revocation.FailClosed
+	}
+	return auth.NewVerifier(cfg.Token.TokenSecret, keys, denylist, failClosed, sugar)
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/service"
+	"go.uber.org/zap"
+)
+
+// startJobs starts the configured background jobs, they stop when ctx is cancelled.
+func startJobs(ctx context.Context, cfg *config.Configs, services *service.Service, sugar *zap.SugaredLogger) {
+	if cfg.Jobs != nil && cfg.Jobs.SavedSearchInterval > 0 {
+		go runPeriodically(ctx, cfg.Jobs.SavedSearchInterval, func(ctx context.Context) {
+			alerts, err := services.ProcessSavedSearchAlerts(ctx)
+			if err != nil {
+				sugar.Errorf("error while processing saved search alerts: %v", err)
+				return
+			}
+			sugar.Infof("saved search alerts processed, %d new alerts", alerts)
+		})
+	}
+	if cfg.Notifications != nil && cfg.Notifications.WorkerInterval > 0 {
+		go runPeriodically(ctx, cfg.Notifications.WorkerInterval, func(ctx context.Context) {
+			if _, err := services.DeliverNotifications(ctx); err != nil {
+				sugar.Errorf("error while delivering notifications: %v", err)
+			}
+		})
+	}
+	if cfg.Events != nil && cfg.Events.RelayInterval > 0 {
+		go runPeriodically(ctx, cfg.Events.RelayInterval, func(ctx context.Context) {
+			if _, err := services.PublishEvents(ctx); err != nil {
+				sugar.Errorf("error while publishing domain events: %v", err)
+			}
+		})
+	}
+	if cfg.Webhooks != nil && cfg.Webhooks.WorkerInterval > 0 {
+		go runPeriodically(ctx, cfg.Webhooks.WorkerInterval, func(ctx context.Context) {
+			if _, err := services.DeliverWebhooks(ctx); err != nil {
+				sugar.Errorf("error while delivering webhooks: %v", err)
+			}
+		})
+	}
+}
+
+// runPeriodically calls job every interval until ctx is cancelled.
+func runPeriodically(ctx context.Context, interval time.Duration, job func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			job(ctx)
+		}
+	}
+}
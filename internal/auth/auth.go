@@ -0,0 +1,106 @@
+// Package auth verifies the access tokens the auth service issues to users.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidToken is returned for tokens that are malformed, expired or not signed by the auth service
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrRevoked is returned for valid tokens the auth service revoked
+	ErrRevoked = errors.New("token revoked")
+)
+
+// Claims are the claims of an access token
+type Claims struct {
+	PublicID string `json:"user_public_id"`
+	Role     string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// Verifier verifies access tokens. HS256 tokens are verified with the shared secret, RS256 and
+// EdDSA tokens with the key of the key set they name.
+type Verifier struct {
+	secret     []byte
+	keys       *KeySet
+	denylist   Denylist
+	failClosed bool
+	logger     *zap.SugaredLogger
+}
+
+// NewVerifier creates a Verifier. An empty secret rejects HS256 tokens and a nil key set rejects
+// RS256 and EdDSA tokens. Revocation is not checked without a denylist; with one, failClosed
+// rejects every token while the denylist is unavailable instead of accepting them unchecked.
+func NewVerifier(secret string, keys *KeySet, denylist Denylist, failClosed bool, logger *zap.SugaredLogger) *Verifier {
+	return &Verifier{
+		secret:     []byte(secret),
+		keys:       keys,
+		denylist:   denylist,
+		failClosed: failClosed,
+		logger:     logger,
+	}
+}
+
+// Parse checks the signature and the expiry of the token and returns its claims. Revocation is not
+// checked, see Verify.
+func (v *Verifier) Parse(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, v.key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if !token.Valid || claims.PublicID == "" {
+		return nil, fmt.Errorf("%w: no user public id", ErrInvalidToken)
+	}
+	return claims, nil
+}
+
+// Verify parses the token and rejects it with ErrRevoked when the auth service revoked it
+func (v *Verifier) Verify(ctx context.Context, tokenString string) (*Claims, error) {
+	claims, err := v.Parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if v.denylist == nil {
+		return claims, nil
+	}
+	revoked, err := v.denylist.Revoked(ctx, claims)
+	if err != nil {
+		if v.failClosed {
+			return nil, fmt.Errorf("failed to check token revocation: %w", err)
+		}
+		logging.FromContext(ctx, v.logger).Warnf("token revocation unavailable, accepting the token unchecked: %v", err)
+		return claims, nil
+	}
+	if revoked {
+		return nil, ErrRevoked
+	}
+	return claims, nil
+}
+
+// key returns the key the token is verified with. The algorithm decides where the key comes from,
+// so a public key of the key set can never be used as an HMAC secret.
+func (v *Verifier) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if len(v.secret) == 0 {
+			return nil, fmt.Errorf("%s tokens are not accepted", token.Method.Alg())
+		}
+		return v.secret, nil
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodEd25519:
+		if v.keys == nil {
+			return nil, fmt.Errorf("%s tokens are not accepted", token.Method.Alg())
+		}
+		kid, _ := token.Header["kid"].(string)
+		return v.keys.Key(kid, token.Method.Alg())
+	default:
+		return nil, fmt.Errorf("%s tokens are not accepted", token.Method.Alg())
+	}
+}
@@ -0,0 +1,462 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+const testSecret = "test-secret"
+
+func TestVerifierParse(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherRSAKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+
+	keys := newTestKeySet(t, map[string]interface{}{
+		"rsa": &rsaKey.PublicKey,
+		"ed":  edPublic,
+	})
+	verifier := NewVerifier(testSecret, keys, nil, false, zap.NewNop().Sugar())
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{
+			name:  "hs256 with the secret",
+			token: sign(t, jwt.SigningMethodHS256, "", validClaims(), []byte(testSecret)),
+			valid: true,
+		},
+		{
+			name:  "hs256 signed with the rsa public key",
+			token: sign(t, jwt.SigningMethodHS256, "rsa", validClaims(), publicPEM),
+		},
+		{
+			name:  "hs256 signed with the rsa public key modulus",
+			token: sign(t, jwt.SigningMethodHS256, "rsa", validClaims(), rsaKey.PublicKey.N.Bytes()),
+		},
+		{
+			name:  "rs256 with the key it names",
+			token: sign(t, jwt.SigningMethodRS256, "rsa", validClaims(), rsaKey),
+			valid: true,
+		},
+		{
+			name:  "rs256 signed by a key of another set",
+			token: sign(t, jwt.SigningMethodRS256, "rsa", validClaims(), otherRSAKey),
+		},
+		{
+			name:  "rs256 naming the ed25519 key",
+			token: sign(t, jwt.SigningMethodRS256, "ed", validClaims(), rsaKey),
+		},
+		{
+			name:  "rs256 without a kid and a single rsa key",
+			token: sign(t, jwt.SigningMethodRS256, "", validClaims(), rsaKey),
+			valid: true,
+		},
+		{
+			name:  "eddsa with the key it names",
+			token: sign(t, jwt.SigningMethodEdDSA, "ed", validClaims(), edPrivate),
+			valid: true,
+		},
+		{
+			name:  "eddsa naming the rsa key",
+			token: sign(t, jwt.SigningMethodEdDSA, "rsa", validClaims(), edPrivate),
+		},
+		{
+			name:  "none",
+			token: sign(t, jwt.SigningMethodNone, "", validClaims(), jwt.UnsafeAllowNoneSignatureType),
+		},
+		{
+			name: "expired",
+			token: sign(t, jwt.SigningMethodHS256, "", &Claims{
+				PublicID:         "user",
+				RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))},
+			}, []byte(testSecret)),
+		},
+		{
+			name:  "without a user public id",
+			token: sign(t, jwt.SigningMethodHS256, "", &Claims{}, []byte(testSecret)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Parse(tt.token)
+			if tt.valid && err != nil {
+				t.Fatalf("expected the token to be accepted, got %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("expected ErrInvalidToken, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifierWithoutSecretOrKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hs256 := sign(t, jwt.SigningMethodHS256, "rsa", validClaims(), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+	if _, err := NewVerifier("", newTestKeySet(t, map[string]interface{}{"rsa": &rsaKey.PublicKey}), nil, false, zap.NewNop().Sugar()).Parse(hs256); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected hs256 to be rejected without a secret, got %v", err)
+	}
+	rs256 := sign(t, jwt.SigningMethodRS256, "rsa", validClaims(), rsaKey)
+	if _, err := NewVerifier(testSecret, nil, nil, false, zap.NewNop().Sugar()).Parse(rs256); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected rs256 to be rejected without a key set, got %v", err)
+	}
+}
+
+func TestVerifierKidlessWithSeveralKeys(t *testing.T) {
+	first, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := newTestKeySet(t, map[string]interface{}{"first": &first.PublicKey, "second": &second.PublicKey})
+	verifier := NewVerifier("", keys, nil, false, zap.NewNop().Sugar())
+	if _, err := verifier.Parse(sign(t, jwt.SigningMethodRS256, "", validClaims(), first)); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected a token without a kid to be rejected when several keys match, got %v", err)
+	}
+}
+
+func TestKeySetRefreshesForUnknownKid(t *testing.T) {
+	current, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		mu        sync.Mutex
+		published = map[string]interface{}{"current": &current.PublicKey}
+		fetches   int32
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		mu.Lock()
+		defer mu.Unlock()
+		writeKeySet(t, w, published)
+	}))
+	defer server.Close()
+
+	keys := NewKeySet(server.URL, server.Client(), time.Hour, zap.NewNop().Sugar())
+	if err := keys.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	verifier := NewVerifier("", keys, nil, false, zap.NewNop().Sugar())
+
+	// the auth service publishes the next key before signing with it, after minRefresh
+	mu.Lock()
+	published["next"] = &next.PublicKey
+	mu.Unlock()
+	keys.mu.Lock()
+	keys.fetchedAt = time.Now().Add(-time.Hour)
+	keys.mu.Unlock()
+	if _, err := verifier.Parse(sign(t, jwt.SigningMethodRS256, "next", validClaims(), next)); err != nil {
+		t.Fatalf("expected the published key to be fetched, got %v", err)
+	}
+	if n := atomic.LoadInt32(&fetches); n != 2 {
+		t.Fatalf("expected 2 fetches, got %d", n)
+	}
+
+	// unknown keys fetch the set at most once per minRefresh
+	for i := 0; i < 3; i++ {
+		if _, err := verifier.Parse(sign(t, jwt.SigningMethodRS256, "unknown", validClaims(), next)); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("expected an unknown key to be rejected, got %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(&fetches); n != 2 {
+		t.Fatalf("expected no fetch within minRefresh, got %d fetches", n)
+	}
+}
+
+func TestKeySetRefreshIsBoundedByTimeout(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := server.Client()
+	client.Timeout = 100 * time.Millisecond
+	verifier := NewVerifier("", NewKeySet(server.URL, client, 0, zap.NewNop().Sugar()), nil, false, zap.NewNop().Sugar())
+
+	start := time.Now()
+	if _, err := verifier.Parse(sign(t, jwt.SigningMethodRS256, "key", validClaims(), key)); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected the token to be rejected, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected the refresh to time out, took %s", elapsed)
+	}
+}
+
+func TestVerifyRevoked(t *testing.T) {
+	issuedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	tests := []struct {
+		name    string
+		claims  *Claims
+		values  map[string]string
+		revoked bool
+	}{
+		{
+			name:   "not revoked",
+			claims: &Claims{PublicID: "user", RegisteredClaims: jwt.RegisteredClaims{ID: "token", IssuedAt: jwt.NewNumericDate(issuedAt)}},
+		},
+		{
+			name:    "revoked jti",
+			claims:  &Claims{PublicID: "user", RegisteredClaims: jwt.RegisteredClaims{ID: "token", IssuedAt: jwt.NewNumericDate(issuedAt)}},
+			values:  map[string]string{"revoked:jti:token": "1"},
+			revoked: true,
+		},
+		{
+			name:   "another jti revoked",
+			claims: &Claims{PublicID: "user", RegisteredClaims: jwt.RegisteredClaims{ID: "token", IssuedAt: jwt.NewNumericDate(issuedAt)}},
+			values: map[string]string{"revoked:jti:other": "1"},
+		},
+		{
+			name:    "user revoked after the token was issued",
+			claims:  &Claims{PublicID: "user", RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(issuedAt)}},
+			values:  map[string]string{"revoked:user:user": strconv.FormatInt(issuedAt.Add(time.Minute).Unix(), 10)},
+			revoked: true,
+		},
+		{
+			name:   "user revoked before the token was issued",
+			claims: &Claims{PublicID: "user", RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(issuedAt)}},
+			values: map[string]string{"revoked:user:user": strconv.FormatInt(issuedAt.Add(-time.Minute).Unix(), 10)},
+		},
+		{
+			name:    "user revoked and a token without an issue time",
+			claims:  &Claims{PublicID: "user"},
+			values:  map[string]string{"revoked:user:user": strconv.FormatInt(issuedAt.Unix(), 10)},
+			revoked: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeRedis(t, tt.values, false)
+			verifier := NewVerifier(testSecret, nil, NewRedisDenylist(client, "revoked:"), true, zap.NewNop().Sugar())
+			_, err := verifier.Verify(context.Background(), sign(t, jwt.SigningMethodHS256, "", tt.claims, []byte(testSecret)))
+			if tt.revoked && !errors.Is(err, ErrRevoked) {
+				t.Fatalf("expected ErrRevoked, got %v", err)
+			}
+			if !tt.revoked && err != nil {
+				t.Fatalf("expected the token to be accepted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyDenylistUnavailable(t *testing.T) {
+	token := sign(t, jwt.SigningMethodHS256, "", validClaims(), []byte(testSecret))
+	denylist := NewRedisDenylist(newFakeRedis(t, nil, true), "revoked:")
+
+	closed := NewVerifier(testSecret, nil, denylist, true, zap.NewNop().Sugar())
+	if _, err := closed.Verify(context.Background(), token); err == nil {
+		t.Fatal("expected failClosed to reject the token while Redis fails")
+	}
+	open := NewVerifier(testSecret, nil, denylist, false, zap.NewNop().Sugar())
+	if _, err := open.Verify(context.Background(), token); err != nil {
+		t.Fatalf("expected the token to be accepted unchecked, got %v", err)
+	}
+}
+
+func validClaims() *Claims {
+	return &Claims{
+		PublicID: "user",
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, claims *Claims, key interface{}) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+	return signed
+}
+
+// newTestKeySet returns a key set fetched once from a server publishing the keys
+func newTestKeySet(t *testing.T, keys map[string]interface{}) *KeySet {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeKeySet(t, w, keys)
+	}))
+	t.Cleanup(server.Close)
+	set := NewKeySet(server.URL, server.Client(), time.Hour, zap.NewNop().Sugar())
+	if err := set.Refresh(context.Background()); err != nil {
+		t.Fatalf("fetching key set: %v", err)
+	}
+	return set
+}
+
+func writeKeySet(t *testing.T, w io.Writer, keys map[string]interface{}) {
+	set := jwkSet{}
+	for kid, key := range keys {
+		switch key := key.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, jwk{
+				Kty: "RSA",
+				Kid: kid,
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, jwk{
+				Kty: "OKP",
+				Kid: kid,
+				Use: "sig",
+				Alg: "EdDSA",
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(key),
+			})
+		}
+	}
+	if err := json.NewEncoder(w).Encode(set); err != nil {
+		t.Errorf("writing key set: %v", err)
+	}
+}
+
+// newFakeRedis serves MGET from the values, or fails every command, over the Redis protocol
+func newFakeRedis(t *testing.T, values map[string]string, fail bool) *redis.Client {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeRedis(conn, values, fail)
+		}
+	}()
+	client := redis.NewClient(&redis.Options{Addr: ln.Addr().String(), MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func serveFakeRedis(conn net.Conn, values map[string]string, fail bool) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		var reply strings.Builder
+		switch {
+		case fail:
+			reply.WriteString("-ERR unavailable\r\n")
+		case strings.EqualFold(args[0], "MGET"):
+			fmt.Fprintf(&reply, "*%d\r\n", len(args)-1)
+			for _, key := range args[1:] {
+				if value, ok := values[key]; ok {
+					fmt.Fprintf(&reply, "$%d\r\n%s\r\n", len(value), value)
+				} else {
+					reply.WriteString("$-1\r\n")
+				}
+			}
+		default:
+			reply.WriteString("+OK\r\n")
+		}
+		if _, err := io.WriteString(conn, reply.String()); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("invalid command %q", line)
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %q", line)
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis/v7"
+)
+
+// Denylist tells whether the auth service revoked a token
+type Denylist interface {
+	Revoked(ctx context.Context, claims *Claims) (bool, error)
+}
+
+type redisDenylist struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisDenylist creates a Denylist read from the keys the auth service writes in Redis: the key
+// prefix+"jti:"+<token id> revokes a single token, and the key prefix+"user:"+<user public id>
+// holding a unix time revokes every token of the user issued before it.
+func NewRedisDenylist(client *redis.Client, prefix string) Denylist {
+	return &redisDenylist{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (d *redisDenylist) Revoked(ctx context.Context, claims *Claims) (bool, error) {
+	keys := []string{d.prefix + "user:" + claims.PublicID}
+	if claims.ID != "" {
+		keys = append(keys, d.prefix+"jti:"+claims.ID)
+	}
+	values, err := d.client.WithContext(ctx).MGet(keys...).Result()
+	if err != nil {
+		return false, err
+	}
+	if len(values) > 1 && values[1] != nil {
+		return true, nil
+	}
+	since, ok := values[0].(string)
+	if !ok {
+		return false, nil
+	}
+	revokedAt, err := strconv.ParseInt(since, 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid revocation time %q of user %s: %w", since, claims.PublicID, err)
+	}
+	// a token without an issue time is revoked along with the others
+	if claims.IssuedAt == nil {
+		return true, nil
+	}
+	return claims.IssuedAt.Unix() < revokedAt, nil
+}
@@ -0,0 +1,257 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// defaultFetchTimeout bounds the fetches of a token naming an unknown key when the client has no timeout,
+// so a slow auth service cannot hold the requests waiting for them
+const defaultFetchTimeout = 10 * time.Second
+
+// jwkSet is a JSON Web Key Set, RFC 7517
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// N and E are the modulus and exponent of RSA keys
+	N string `json:"n"`
+	E string `json:"e"`
+	// Crv and X are the curve and public key of OKP keys
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+}
+
+type publicKey struct {
+	// alg is the algorithm the key is restricted to, empty for any RSA algorithm
+	alg string
+	key interface{}
+}
+
+func (k publicKey) verifies(alg string) bool {
+	if k.alg == "" {
+		_, ok := k.key.(*rsa.PublicKey)
+		return ok && strings.HasPrefix(alg, "RS")
+	}
+	return k.alg == alg
+}
+
+// KeySet holds the signing keys published by the auth service. Keys missing from the last fetched
+// set are no longer accepted, so keys are rotated by publishing the new key before signing with it
+// and removing the old key once its tokens expired.
+type KeySet struct {
+	url        string
+	client     *http.Client
+	minRefresh time.Duration
+	logger     *zap.SugaredLogger
+
+	mu        sync.RWMutex
+	keys      map[string]publicKey
+	fetchedAt time.Time
+	err       error
+
+	// refreshMu lets a single fetch run at a time
+	refreshMu sync.Mutex
+}
+
+// NewKeySet creates a KeySet fetched from url. A token naming a key missing from the set fetches it
+// again, at most once per minRefresh. The set is empty until Refresh is called.
+func NewKeySet(url string, client *http.Client, minRefresh time.Duration, logger *zap.SugaredLogger) *KeySet {
+	return &KeySet{
+		url:        url,
+		client:     client,
+		minRefresh: minRefresh,
+		logger:     logger,
+		keys:       map[string]publicKey{},
+	}
+}
+
+// Refresh fetches the key set and replaces the keys with it. The keys are kept when the fetch fails.
+func (s *KeySet) Refresh(ctx context.Context) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	return s.refresh(ctx)
+}
+
+func (s *KeySet) refresh(ctx context.Context) error {
+	keys, err := s.fetch(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetchedAt = time.Now()
+	s.err = err
+	if err != nil {
+		return err
+	}
+	s.keys = keys
+	return nil
+}
+
+// Check reports the error of the last fetch, for the health checks
+func (s *KeySet) Check(ctx context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.err != nil {
+		return s.err
+	}
+	if len(s.keys) == 0 {
+		return errors.New("no signing keys")
+	}
+	return nil
+}
+
+// Key returns the key with the kid for the algorithm. An empty kid is only accepted when a single
+// key of the set can verify the algorithm.
+func (s *KeySet) Key(kid, alg string) (interface{}, error) {
+	if key, ok := s.lookup(kid, alg); ok {
+		return key, nil
+	}
+	if s.refreshIfDue(kid) {
+		if key, ok := s.lookup(kid, alg); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("no %s signing key %q", alg, kid)
+}
+
+// refreshIfDue fetches the key set unless it was fetched less than minRefresh ago, and reports
+// whether it did. Tokens naming the same unknown key at once wait for a single fetch.
+func (s *KeySet) refreshIfDue(kid string) bool {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	s.mu.RLock()
+	due := time.Since(s.fetchedAt) >= s.minRefresh
+	s.mu.RUnlock()
+	if !due {
+		return false
+	}
+
+	timeout := s.client.Timeout
+	if timeout <= 0 {
+		timeout = defaultFetchTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := s.refresh(ctx); err != nil {
+		s.logger.Errorf("failed to refresh signing keys for key %q: %v", kid, err)
+	}
+	return true
+}
+
+func (s *KeySet) lookup(kid, alg string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if kid != "" {
+		key, ok := s.keys[kid]
+		return key.key, ok && key.verifies(alg)
+	}
+	var found interface{}
+	for _, key := range s.keys {
+		if !key.verifies(alg) {
+			continue
+		}
+		if found != nil {
+			return nil, false
+		}
+		found = key.key
+	}
+	return found, found != nil
+}
+
+func (s *KeySet) fetch(ctx context.Context) (map[string]publicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch signing keys: %s", resp.Status)
+	}
+	set := &jwkSet{}
+	if err := json.NewDecoder(resp.Body).Decode(set); err != nil {
+		return nil, fmt.Errorf("failed to decode signing keys: %w", err)
+	}
+
+	keys := make(map[string]publicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			s.logger.Warnf("skipping signing key %q: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("the key set has no usable signing keys")
+	}
+	return keys, nil
+}
+
+// publicKey decodes RSA keys, used with RS256, RS384 or RS512, and Ed25519 keys, used with EdDSA
+func (k *jwk) publicKey() (publicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return publicKey{}, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil || !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return publicKey{}, errors.New("invalid exponent")
+		}
+		if k.Alg != "" && !strings.HasPrefix(k.Alg, "RS") {
+			return publicKey{}, fmt.Errorf("unsupported algorithm %s for an RSA key", k.Alg)
+		}
+		return publicKey{alg: k.Alg, key: &rsa.PublicKey{N: n, E: int(e.Int64())}}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return publicKey{}, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		if k.Alg != "" && k.Alg != jwt.SigningMethodEdDSA.Alg() {
+			return publicKey{}, fmt.Errorf("unsupported algorithm %s for an Ed25519 key", k.Alg)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return publicKey{}, errors.New("invalid public key")
+		}
+		return publicKey{alg: jwt.SigningMethodEdDSA.Alg(), key: ed25519.PublicKey(x)}, nil
+	default:
+		return publicKey{}, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,13 @@
+package cache
+
+import (
+	"time"
+)
+
+// Cache stores JSON encoded values by key.
+// A missing or expired key is reported by Get returning false without an error.
+type Cache interface {
+	Get(key string, dest interface{}) (bool, error)
+	Set(key string, value interface{}, ttl time.Duration) error
+	Delete(keys ...string) error
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type fallbackCache struct {
+	primary     Cache
+	fallback    Cache
+	fallbackTTL time.Duration
+	logger      *zap.SugaredLogger
+}
+
+// NewFallbackCache creates a Cache that uses primary and switches to fallback for the calls
+// primary fails, e.g. while Redis is down.
+// Values stored in fallback live at most fallbackTTL, which bounds how stale a replica can serve
+// a value that another replica invalidated during the outage. Deletes always go to both caches.
+func NewFallbackCache(primary, fallback Cache, fallbackTTL time.Duration, logger *zap.SugaredLogger) Cache {
+	return &fallbackCache{
+		primary:     primary,
+		fallback:    fallback,
+		fallbackTTL: fallbackTTL,
+		logger:      logger,
+	}
+}
+
+func (c *fallbackCache) Get(key string, dest interface{}) (bool, error) {
+	found, err := c.primary.Get(key, dest)
+	if err == nil {
+		return found, nil
+	}
+	c.logger.Warnf("cache unavailable, using in-process fallback: %v", err)
+	fallbackCount.Add(1)
+	return c.fallback.Get(key, dest)
+}
+
+func (c *fallbackCache) Set(key string, value interface{}, ttl time.Duration) error {
+	err := c.primary.Set(key, value, ttl)
+	if err == nil {
+		return nil
+	}
+	c.logger.Warnf("cache unavailable, using in-process fallback: %v", err)
+	fallbackCount.Add(1)
+	if ttl > c.fallbackTTL {
+		ttl = c.fallbackTTL
+	}
+	return c.fallback.Set(key, value, ttl)
+}
+
+func (c *fallbackCache) Delete(keys ...string) error {
+	fallbackErr := c.fallback.Delete(keys...)
+	if err := c.primary.Delete(keys...); err != nil {
+		return err
+	}
+	return fallbackErr
+}
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+type memoryCache struct {
+	mu         sync.Mutex
+	entries    map[string]memoryEntry
+	maxEntries int
+}
+
+// NewMemoryCache creates an in-process Cache holding at most maxEntries keys.
+// When it is full, expired keys are dropped first and then arbitrary ones.
+func NewMemoryCache(maxEntries int) Cache {
+	return &memoryCache{
+		entries:    make(map[string]memoryEntry),
+		maxEntries: maxEntries,
+	}
+}
+
+func (c *memoryCache) Get(key string, dest interface{}) (bool, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		ok = false
+	}
+	c.mu.Unlock()
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(entry.data, dest); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *memoryCache) Set(key string, value interface{}, ttl time.Duration) error {
+	// values are stored encoded, so callers cannot modify a cached value through a shared pointer
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		c.evict()
+	}
+	c.entries[key] = memoryEntry{
+		data:      data,
+		expiresAt: time.Now().Add(ttl),
+	}
+	return nil
+}
+
+func (c *memoryCache) Delete(keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+// evict makes room for one entry. It must be called with the lock held.
+func (c *memoryCache) evict() {
+	now := time.Now()
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	for key := range c.entries {
+		if len(c.entries) < c.maxEntries {
+			return
+		}
+		delete(c.entries, key)
+	}
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"expvar"
+	"time"
+)
+
+var (
+	stats         = expvar.NewMap("cache")
+	fallbackCount = new(expvar.Int)
+)
+
+func init() {
+	stats.Set("fallbacks", fallbackCount)
+}
+
+type instrumentedCache struct {
+	next   Cache
+	hits   *expvar.Int
+	misses *expvar.Int
+	errors *expvar.Int
+}
+
+// WithMetrics counts the hits, misses and errors of the cache under name.
+// The counters are published with expvar as cache.<name>_hits, cache.<name>_misses and cache.<name>_errors.
+func WithMetrics(name string, next Cache) Cache {
+	return &instrumentedCache{
+		next:   next,
+		hits:   counter(name + "_hits"),
+		misses: counter(name + "_misses"),
+		errors: counter(name + "_errors"),
+	}
+}
+
+func counter(name string) *expvar.Int {
+	if v, ok := stats.Get(name).(*expvar.Int); ok {
+		return v
+	}
+	v := new(expvar.Int)
+	stats.Set(name, v)
+	return v
+}
+
+func (c *instrumentedCache) Get(key string, dest interface{}) (bool, error) {
+	found, err := c.next.Get(key, dest)
+	switch {
+	case err != nil:
+		c.errors.Add(1)
+	case found:
+		c.hits.Add(1)
+	default:
+		c.misses.Add(1)
+	}
+	return found, err
+}
+
+func (c *instrumentedCache) Set(key string, value interface{}, ttl time.Duration) error {
+	err := c.next.Set(key, value, ttl)
+	if err != nil {
+		c.errors.Add(1)
+	}
+	return err
+}
+
+func (c *instrumentedCache) Delete(keys ...string) error {
+	err := c.next.Delete(keys...)
+	if err != nil {
+		c.errors.Add(1)
+	}
+	return err
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+)
+
+type redisCache struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisCache creates a Cache stored in Redis. Every key is prefixed with prefix,
+// so several services can share a Redis database.
+func NewRedisCache(client *redis.Client, prefix string) Cache {
+	return &redisCache{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (c *redisCache) Get(key string, dest interface{}) (bool, error) {
+	data, err := c.client.Get(c.prefix + key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *redisCache) Set(key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(c.prefix+key, data, ttl).Err()
+}
+
+func (c *redisCache) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, 0, len(keys))
+	for _, key := range keys {
+		prefixed = append(prefixed, c.prefix+key)
+	}
+	return c.client.Del(prefixed...).Err()
+}
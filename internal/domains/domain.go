@@ -0,0 +1,82 @@
+// Package domains proves that companies own the email domains they claim.
+package domains
+
+import (
+	"errors"
+	"strings"
+)
+
+// RecordPrefix names the TXT record a company publishes under its domain to prove it owns it
+const RecordPrefix = "_sp-verification."
+
+var (
+	ErrInvalidDomain = errors.New("must be a domain name, e.g. example.com")
+	ErrFreeMail      = errors.New("is a free email provider and cannot be claimed")
+)
+
+// freeMail are the domains of public email providers. Anybody can have an address on them, so
+// no company can claim them.
+var freeMail = map[string]struct{}{
+	"gmail.com":      {},
+	"googlemail.com": {},
+	"yahoo.com":      {},
+	"outlook.com":    {},
+	"hotmail.com":    {},
+	"live.com":       {},
+	"icloud.com":     {},
+	"aol.com":        {},
+	"proton.me":      {},
+	"protonmail.com": {},
+	"gmx.com":        {},
+	"mail.ru":        {},
+	"yandex.ru":      {},
+	"yandex.kz":      {},
+}
+
+// Normalize returns the domain in lower case without a trailing dot. It fails with ErrInvalidDomain
+// for anything but a domain name of two labels or more, and with ErrFreeMail for public email providers.
+func Normalize(domain string) (string, error) {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if len(domain) > 253 {
+		return "", ErrInvalidDomain
+	}
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return "", ErrInvalidDomain
+	}
+	for _, label := range labels {
+		if !validLabel(label) {
+			return "", ErrInvalidDomain
+		}
+	}
+	if _, ok := freeMail[domain]; ok {
+		return "", ErrFreeMail
+	}
+	return domain, nil
+}
+
+func validLabel(label string) bool {
+	if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, c := range label {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// EmailDomain returns the domain of the email address in lower case, or an empty string if it has none
+func EmailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(email[at+1:])), ".")
+}
+
+// RecordName is the name of the TXT record proving the ownership of the domain
+func RecordName(domain string) string {
+	return RecordPrefix + domain
+}
@@ -0,0 +1,71 @@
+package domains
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+)
+
+const (
+	ResolverDNS  = "dns"
+	ResolverStub = "stub"
+)
+
+// Resolver looks up the TXT records of a name. A name without records has none, rather than an error.
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// NewResolver creates the resolver selected in the configuration.
+// TXT records are looked up in DNS when no resolver is configured.
+func NewResolver(cfg *config.DomainVerificationConf) (Resolver, error) {
+	if cfg == nil || cfg.Resolver == "" || cfg.Resolver == ResolverDNS {
+		return NewDNSResolver(), nil
+	}
+	switch cfg.Resolver {
+	case ResolverStub:
+		return NewStubResolver(cfg.Records), nil
+	default:
+		return nil, fmt.Errorf("unknown domain resolver %q", cfg.Resolver)
+	}
+}
+
+type dnsResolver struct {
+	resolver *net.Resolver
+}
+
+// NewDNSResolver creates a Resolver querying the DNS servers of the system
+func NewDNSResolver() Resolver {
+	return &dnsResolver{resolver: net.DefaultResolver}
+}
+
+func (r *dnsResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	records, err := r.resolver.LookupTXT(ctx, name)
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return nil, nil
+	}
+	return records, err
+}
+
+type stubResolver struct {
+	records map[string][]string
+}
+
+// NewStubResolver creates a Resolver answering with the records, for local development
+func NewStubResolver(records []config.TXTRecord) Resolver {
+	r := &stubResolver{records: make(map[string][]string, len(records))}
+	for _, record := range records {
+		name := strings.ToLower(strings.TrimSuffix(record.Name, "."))
+		r.records[name] = append(r.records[name], record.Value)
+	}
+	return r
+}
+
+func (r *stubResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return r.records[strings.ToLower(strings.TrimSuffix(name, "."))], nil
+}
@@ -0,0 +1,68 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+type natsPublisher struct {
+	conn          *nats.Conn
+	subjectPrefix string
+}
+
+// NewNATSPublisher creates a Publisher that publishes every event to the subject
+// "<subjectPrefix>.<event type>", e.g. "users.candidate.updated".
+// The event ID is sent as the Nats-Msg-Id header, so JetStream streams deduplicate redelivered events.
+// The connection is retried in the background, so the service starts even if NATS is down.
+func NewNATSPublisher(url, subjectPrefix string, logger *zap.SugaredLogger) (Publisher, error) {
+	if url == "" {
+		url = nats.DefaultURL
+	}
+	conn, err := nats.Connect(url,
+		nats.Name("sp-users-main-service"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				logger.Warnf("disconnected from nats: %v", err)
+			}
+		}),
+		nats.ReconnectHandler(func(c *nats.Conn) {
+			logger.Infof("reconnected to nats at %s", c.ConnectedUrl())
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &natsPublisher{
+		conn:          conn,
+		subjectPrefix: subjectPrefix,
+	}, nil
+}
+
+func (p *natsPublisher) Publish(ctx context.Context, event *models.DomainEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	subject := event.Type
+	if p.subjectPrefix != "" {
+		subject = p.subjectPrefix + "." + subject
+	}
+	msg := nats.NewMsg(subject)
+	msg.Header.Set(nats.MsgIdHdr, event.PublicID)
+	msg.Data = data
+	if err := p.conn.PublishMsg(msg); err != nil {
+		return err
+	}
+	// the message is only buffered by the client until the server acknowledges the flush
+	return p.conn.FlushWithContext(ctx)
+}
+
+func (p *natsPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,39 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"go.uber.org/zap"
+)
+
+const (
+	PublisherLog  = "log"
+	PublisherFile = "file"
+	PublisherNATS = "nats"
+)
+
+// Publisher delivers domain events to downstream services.
+// Publish must only return nil once the event has been accepted by the sink.
+type Publisher interface {
+	Publish(ctx context.Context, event *models.DomainEvent) error
+	Close() error
+}
+
+// NewPublisher creates the publisher selected in the configuration.
+// Events are written to the application log when no publisher is configured.
+func NewPublisher(cfg *config.EventsConf, logger *zap.SugaredLogger) (Publisher, error) {
+	if cfg == nil || cfg.Publisher == "" || cfg.Publisher == PublisherLog {
+		return NewLogPublisher(logger), nil
+	}
+	switch cfg.Publisher {
+	case PublisherFile:
+		return NewFilePublisher(cfg.FilePath)
+	case PublisherNATS:
+		return NewNATSPublisher(cfg.NATSURL, cfg.SubjectPrefix, logger)
+	default:
+		return nil, fmt.Errorf("unknown event publisher %q", cfg.Publisher)
+	}
+}
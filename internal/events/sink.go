@@ -0,0 +1,70 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"go.uber.org/zap"
+)
+
+type logPublisher struct {
+	logger *zap.SugaredLogger
+}
+
+// NewLogPublisher creates a Publisher that writes events to the application log.
+// It is meant for local development, where no broker is running.
+func NewLogPublisher(logger *zap.SugaredLogger) Publisher {
+	return &logPublisher{logger: logger}
+}
+
+func (p *logPublisher) Publish(_ context.Context, event *models.DomainEvent) error {
+	p.logger.Infow("domain event",
+		"id", event.PublicID,
+		"type", event.Type,
+		"aggregate_type", event.AggregateType,
+		"aggregate_id", event.AggregatePublicID,
+		"payload", event.Payload,
+		"occurred_at", event.OccurredAt,
+	)
+	return nil
+}
+
+func (p *logPublisher) Close() error {
+	return nil
+}
+
+type filePublisher struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewFilePublisher creates a Publisher that appends events to the file as JSON lines.
+func NewFilePublisher(path string) (Publisher, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &filePublisher{
+		file: file,
+		enc:  json.NewEncoder(file),
+	}, nil
+}
+
+func (p *filePublisher) Publish(_ context.Context, event *models.DomainEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.enc.Encode(event); err != nil {
+		return err
+	}
+	return p.file.Sync()
+}
+
+func (p *filePublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.file.Close()
+}
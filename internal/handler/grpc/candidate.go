@@ -0,0 +1,148 @@
+package grpchandler
+
+import (
+	"context"
+	"errors"
+
+	usersv1 "github.com/Zhiyenbek/sp-users-main-service/api/users/v1"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/service"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type candidatesServer struct {
+	usersv1.UnimplementedCandidatesServiceServer
+	service service.CandidatesService
+	logger  *zap.SugaredLogger
+}
+
+// exists returns a NotFound status when there is no candidate with the public ID
+func (s *candidatesServer) exists(ctx context.Context, publicID string) error {
+	if err := s.service.Exists(ctx, publicID); err != nil {
+		if errors.Is(err, models.ErrPermissionDenied) {
+			return status.Error(codes.NotFound, models.ErrUserNotFound.Error())
+		}
+		return toStatus(err)
+	}
+	return nil
+}
+
+func (s *candidatesServer) getCandidate(ctx context.Context, publicID string) (*usersv1.Candidate, error) {
+	candidate, err := s.service.GetCandidateByPublicID(ctx, publicID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res, err := toCandidate(candidate)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Errorf("failed to convert candidate %s: %v", publicID, err)
+		return nil, toStatus(err)
+	}
+	return res, nil
+}
+
+func (s *candidatesServer) GetCandidate(ctx context.Context, req *usersv1.GetCandidateRequest) (*usersv1.GetCandidateResponse, error) {
+	if err := s.exists(ctx, req.GetPublicId()); err != nil {
+		return nil, err
+	}
+	candidate, err := s.getCandidate(ctx, req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+	return &usersv1.GetCandidateResponse{Candidate: candidate}, nil
+}
+
+func (s *candidatesServer) SearchCandidates(ctx context.Context, req *usersv1.SearchCandidatesRequest) (*usersv1.SearchCandidatesResponse, error) {
+	candidates, count, err := s.service.GetCandidatesBySearch(ctx, searchArgs(req.GetSearch(), req.GetPage()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res, err := toCandidates(candidates)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Errorf("failed to convert candidates: %v", err)
+		return nil, toStatus(err)
+	}
+	return &usersv1.SearchCandidatesResponse{Candidates: res, Count: int32(count)}, nil
+}
+
+func (s *candidatesServer) CandidateExists(ctx context.Context, req *usersv1.CandidateExistsRequest) (*usersv1.CandidateExistsResponse, error) {
+	if err := s.service.Exists(ctx, req.GetPublicId()); err != nil {
+		if errors.Is(err, models.ErrPermissionDenied) {
+			return &usersv1.CandidateExistsResponse{Exists: false}, nil
+		}
+		return nil, toStatus(err)
+	}
+	return &usersv1.CandidateExistsResponse{Exists: true}, nil
+}
+
+func (s *candidatesServer) UpdateCandidate(ctx context.Context, req *usersv1.UpdateCandidateRequest) (*usersv1.UpdateCandidateResponse, error) {
+	if err := s.exists(ctx, req.GetPublicId()); err != nil {
+		return nil, err
+	}
+	updateData := &models.Candidate{
+		FirstName:       req.FirstName,
+		LastName:        req.LastName,
+		CurrentPosition: req.CurrentPosition,
+		Resume:          req.Resume,
+		Bio:             req.Bio,
+		Photo:           req.Photo,
+		Education:       req.Education,
+	}
+	if err := s.service.UpdateCandidateByID(ctx, req.GetPublicId(), updateData); err != nil {
+		return nil, toStatus(err)
+	}
+	candidate, err := s.getCandidate(ctx, req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+	return &usersv1.UpdateCandidateResponse{Candidate: candidate}, nil
+}
+
+func (s *candidatesServer) DeleteCandidate(ctx context.Context, req *usersv1.DeleteCandidateRequest) (*usersv1.DeleteCandidateResponse, error) {
+	if err := s.exists(ctx, req.GetPublicId()); err != nil {
+		return nil, err
+	}
+	if err := s.service.DeleteCandidateByID(ctx, req.GetPublicId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &usersv1.DeleteCandidateResponse{}, nil
+}
+
+func (s *candidatesServer) AddCandidateSkills(ctx context.Context, req *usersv1.AddCandidateSkillsRequest) (*usersv1.AddCandidateSkillsResponse, error) {
+	if len(req.GetSkills()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, models.ErrInvalidInput.Error())
+	}
+	if err := s.exists(ctx, req.GetPublicId()); err != nil {
+		return nil, err
+	}
+	if err := s.service.AddSkillsToCandidate(ctx, req.GetPublicId(), req.GetSkills()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &usersv1.AddCandidateSkillsResponse{}, nil
+}
+
+func (s *candidatesServer) RemoveCandidateSkills(ctx context.Context, req *usersv1.RemoveCandidateSkillsRequest) (*usersv1.RemoveCandidateSkillsResponse, error) {
+	if len(req.GetSkills()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, models.ErrInvalidInput.Error())
+	}
+	if err := s.exists(ctx, req.GetPublicId()); err != nil {
+		return nil, err
+	}
+	if err := s.service.DeleteSkillsFromCandidate(ctx, req.GetPublicId(), req.GetSkills()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &usersv1.RemoveCandidateSkillsResponse{}, nil
+}
+
+func (s *candidatesServer) GetCandidateInterviews(ctx context.Context, req *usersv1.GetCandidateInterviewsRequest) (*usersv1.GetCandidateInterviewsResponse, error) {
+	if err := s.exists(ctx, req.GetPublicId()); err != nil {
+		return nil, err
+	}
+	interviews, count, err := s.service.GetInterviewsByPublicID(ctx, req.GetPublicId(), searchArgs(req.GetSearch(), req.GetPage()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &usersv1.GetCandidateInterviewsResponse{Interviews: toInterviewResults(interviews), Count: int32(count)}, nil
+}
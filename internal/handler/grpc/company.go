@@ -0,0 +1,108 @@
+package grpchandler
+
+import (
+	"context"
+	"errors"
+
+	usersv1 "github.com/Zhiyenbek/sp-users-main-service/api/users/v1"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/service"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type companyServer struct {
+	usersv1.UnimplementedCompanyServiceServer
+	service service.CompanyService
+	logger  *zap.SugaredLogger
+}
+
+// exists returns a NotFound status when there is no company with the public ID
+func (s *companyServer) exists(ctx context.Context, publicID string) error {
+	if err := s.service.Exists(ctx, publicID); err != nil {
+		if errors.Is(err, models.ErrPermissionDenied) {
+			return status.Error(codes.NotFound, models.ErrCompanyNotFound.Error())
+		}
+		return toStatus(err)
+	}
+	return nil
+}
+
+func (s *companyServer) getCompany(ctx context.Context, publicID string) (*usersv1.Company, error) {
+	company, err := s.service.GetCompany(ctx, publicID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if company == nil {
+		return nil, status.Error(codes.NotFound, models.ErrCompanyNotFound.Error())
+	}
+	return toCompany(company), nil
+}
+
+func (s *companyServer) CreateCompany(ctx context.Context, req *usersv1.CreateCompanyRequest) (*usersv1.CreateCompanyResponse, error) {
+	company := &models.Company{
+		Name:        req.GetName(),
+		Logo:        req.GetLogo(),
+		Description: req.GetDescription(),
+	}
+	publicID, err := s.service.CreateCompany(ctx, company)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	company.PublicID = publicID
+	return &usersv1.CreateCompanyResponse{Company: toCompany(company)}, nil
+}
+
+func (s *companyServer) UpdateCompany(ctx context.Context, req *usersv1.UpdateCompanyRequest) (*usersv1.UpdateCompanyResponse, error) {
+	if err := s.exists(ctx, req.GetPublicId()); err != nil {
+		return nil, err
+	}
+	company := &models.Company{
+		PublicID:    req.GetPublicId(),
+		Name:        req.GetName(),
+		Logo:        req.GetLogo(),
+		Description: req.GetDescription(),
+	}
+	if err := s.service.UpdateCompany(ctx, company); err != nil {
+		return nil, toStatus(err)
+	}
+	res, err := s.getCompany(ctx, req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+	return &usersv1.UpdateCompanyResponse{Company: res}, nil
+}
+
+func (s *companyServer) GetCompany(ctx context.Context, req *usersv1.GetCompanyRequest) (*usersv1.GetCompanyResponse, error) {
+	if err := s.exists(ctx, req.GetPublicId()); err != nil {
+		return nil, err
+	}
+	res, err := s.getCompany(ctx, req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+	return &usersv1.GetCompanyResponse{Company: res}, nil
+}
+
+func (s *companyServer) ListCompanies(ctx context.Context, req *usersv1.ListCompaniesRequest) (*usersv1.ListCompaniesResponse, error) {
+	companies, count, err := s.service.GetCompanies(ctx, searchArgs(req.GetSearch(), req.GetPage()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := make([]*usersv1.Company, 0, len(companies))
+	for _, company := range companies {
+		res = append(res, toCompany(company))
+	}
+	return &usersv1.ListCompaniesResponse{Companies: res, Count: int32(count)}, nil
+}
+
+func (s *companyServer) CompanyExists(ctx context.Context, req *usersv1.CompanyExistsRequest) (*usersv1.CompanyExistsResponse, error) {
+	if err := s.service.Exists(ctx, req.GetPublicId()); err != nil {
+		if errors.Is(err, models.ErrPermissionDenied) {
+			return &usersv1.CompanyExistsResponse{Exists: false}, nil
+		}
+		return nil, toStatus(err)
+	}
+	return &usersv1.CompanyExistsResponse{Exists: true}, nil
+}
@@ -0,0 +1,135 @@
+package grpchandler
+
+import (
+	usersv1 "github.com/Zhiyenbek/sp-users-main-service/api/users/v1"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// searchArgs builds the search arguments, falling back to the default page like the HTTP API does
+func searchArgs(search string, page *usersv1.Page) *models.SearchArgs {
+	args := &models.SearchArgs{
+		Search:   search,
+		PageNum:  int(page.GetPageNum()),
+		PageSize: int(page.GetPageSize()),
+	}
+	if args.PageNum < 1 {
+		args.PageNum = models.DefaultPageNum
+	}
+	if args.PageSize < 1 {
+		args.PageSize = models.DefaultPageSize
+	}
+	return args
+}
+
+func toCandidate(candidate *models.Candidate) (*usersv1.Candidate, error) {
+	res := &usersv1.Candidate{
+		FirstName:       candidate.FirstName,
+		LastName:        candidate.LastName,
+		CurrentPosition: candidate.CurrentPosition,
+		Resume:          candidate.Resume,
+		Bio:             candidate.Bio,
+		Photo:           candidate.Photo,
+		Education:       candidate.Education,
+		Skills:          make([]string, 0, len(candidate.Skills)),
+		Interviews:      make([]*usersv1.Interview, 0, len(candidate.Interviews)),
+	}
+	if candidate.PublicID != nil {
+		res.PublicId = *candidate.PublicID
+	}
+	for _, skill := range candidate.Skills {
+		if skill != nil {
+			res.Skills = append(res.Skills, *skill)
+		}
+	}
+	for _, interview := range candidate.Interviews {
+		results, err := structpb.NewStruct(interview.Results)
+		if err != nil {
+			return nil, err
+		}
+		res.Interviews = append(res.Interviews, &usersv1.Interview{
+			Id:       int64(interview.ID),
+			PublicId: interview.PublicID,
+			Results:  results,
+		})
+	}
+	return res, nil
+}
+
+func toCandidates(candidates []*models.Candidate) ([]*usersv1.Candidate, error) {
+	res := make([]*usersv1.Candidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		c, err := toCandidate(candidate)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, c)
+	}
+	return res, nil
+}
+
+func toInterviewResults(interviews []*models.InterviewResults) []*usersv1.InterviewResult {
+	res := make([]*usersv1.InterviewResult, 0, len(interviews))
+	for _, interview := range interviews {
+		questions := make([]*usersv1.Question, 0, len(interview.Result.Questions))
+		for _, question := range interview.Result.Questions {
+			emotions := make([]*usersv1.EmotionResult, 0, len(question.EmotionResults))
+			for _, emotion := range question.EmotionResults {
+				emotions = append(emotions, &usersv1.EmotionResult{
+					Emotion:   emotion.Emotion,
+					ExactTime: emotion.ExactTime,
+					Duration:  emotion.Duration,
+				})
+			}
+			questions = append(questions, &usersv1.Question{
+				Question:       question.Question,
+				QuestionType:   question.QuestionType,
+				Evaluation:     question.Evaluation,
+				Score:          int32(question.Score),
+				VideoLink:      question.VideoLink,
+				Answer:         question.Answer,
+				Emotion:        question.Emotion,
+				EmotionResults: emotions,
+			})
+		}
+		res = append(res, &usersv1.InterviewResult{
+			PublicId:         interview.PublicID,
+			PositionPublicId: interview.PositionPublicID,
+			Score:            int32(interview.Result.Score),
+			Questions:        questions,
+		})
+	}
+	return res
+}
+
+func toRecruiter(recruiter *models.Recruiter) *usersv1.Recruiter {
+	positions := make([]*usersv1.Position, 0, len(recruiter.Positions))
+	for _, position := range recruiter.Positions {
+		positions = append(positions, &usersv1.Position{
+			PublicId: position.PublicID,
+			Name:     position.Name,
+			Status:   int32(position.Status),
+		})
+	}
+	return &usersv1.Recruiter{
+		PublicId:        recruiter.PublicID,
+		CompanyPublicId: recruiter.CompanyPublicID,
+		FirstName:       recruiter.FirstName,
+		LastName:        recruiter.LastName,
+		Photo:           recruiter.Photo,
+		Company:         toCompany(recruiter.Company),
+		Positions:       positions,
+	}
+}
+
+func toCompany(company *models.Company) *usersv1.Company {
+	if company == nil {
+		return nil
+	}
+	return &usersv1.Company{
+		PublicId:    company.PublicID,
+		Name:        company.Name,
+		Logo:        company.Logo,
+		Description: company.Description,
+	}
+}
@@ -0,0 +1,150 @@
+package grpchandler
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"strings"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const requestIDMetadata = "x-request-id"
+
+// requestIDRe limits the request IDs accepted from callers, so they cannot inject arbitrary text into logs
+var requestIDRe = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// requestIDUnary takes the request ID from the x-request-id metadata, or generates one, and adds it
+// to the context and the response headers, like the X-Request-ID header of the HTTP API.
+func requestIDUnary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(withRequestID(ctx), req)
+	}
+}
+
+func requestIDStream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: withRequestID(ss.Context())})
+	}
+}
+
+func withRequestID(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var id string
+	if values := md.Get(requestIDMetadata); len(values) > 0 && requestIDRe.MatchString(values[0]) {
+		id = values[0]
+	} else {
+		id = uuid.NewString()
+	}
+	// only fails when headers were already sent, which cannot happen before the handler runs
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, id))
+	return logging.WithRequestID(ctx, id)
+}
+
+// contextStream replaces the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// authUnary rejects calls without one of the tokens in the authorization metadata.
+// Health checks are always allowed so probes do not need a token.
+func authUnary(tokens []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authorize(ctx, tokens, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func authStream(tokens []string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorize(ss.Context(), tokens, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func authorize(ctx context.Context, tokens []string, method string) error {
+	if len(tokens) == 0 || strings.HasPrefix(method, "/"+healthpb.Health_ServiceDesc.ServiceName+"/") {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		token, ok := strings.CutPrefix(value, "Bearer ")
+		if !ok {
+			continue
+		}
+		for _, allowed := range tokens {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(allowed)) == 1 {
+				return nil
+			}
+		}
+	}
+	return status.Error(codes.Unauthenticated, "INVALID_TOKEN")
+}
+
+func recoverUnary(logger *zap.SugaredLogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logging.FromContext(ctx, logger).Errorf("panic in grpc method %s: %v\n%s", info.FullMethod, r, debug.Stack())
+				err = status.Error(codes.Internal, models.ErrInternalServer.Error())
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+func recoverStream(logger *zap.SugaredLogger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logging.FromContext(ss.Context(), logger).Errorf("panic in grpc method %s: %v\n%s", info.FullMethod, r, debug.Stack())
+				err = status.Error(codes.Internal, models.ErrInternalServer.Error())
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// toStatus maps service errors to gRPC status errors by their HTTP status, using the error code as the message like the HTTP API does
+func toStatus(err error) error {
+	var domainErr *models.Error
+	if !errors.As(err, &domainErr) {
+		return status.Error(codes.Internal, models.ErrInternalServer.Error())
+	}
+	switch domainErr.Status {
+	case http.StatusBadRequest:
+		return status.Error(codes.InvalidArgument, domainErr.Error())
+	case http.StatusUnauthorized:
+		return status.Error(codes.Unauthenticated, domainErr.Error())
+	case http.StatusForbidden:
+		return status.Error(codes.PermissionDenied, domainErr.Error())
+	case http.StatusNotFound:
+		return status.Error(codes.NotFound, domainErr.Error())
+	case http.StatusConflict:
+		return status.Error(codes.AlreadyExists, domainErr.Error())
+	case http.StatusTooManyRequests:
+		return status.Error(codes.ResourceExhausted, domainErr.Error())
+	default:
+		return status.Error(codes.Internal, models.ErrInternalServer.Error())
+	}
+}
@@ -0,0 +1,62 @@
+package grpchandler
+
+import (
+	"context"
+	"errors"
+
+	usersv1 "github.com/Zhiyenbek/sp-users-main-service/api/users/v1"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/service"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type recruiterServer struct {
+	usersv1.UnimplementedRecruiterServiceServer
+	service service.RecruiterService
+	logger  *zap.SugaredLogger
+}
+
+// exists returns a NotFound status when there is no recruiter with the public ID
+func (s *recruiterServer) exists(ctx context.Context, publicID string) error {
+	if err := s.service.Exists(ctx, publicID); err != nil {
+		if errors.Is(err, models.ErrPermissionDenied) {
+			return status.Error(codes.NotFound, models.ErrUserNotFound.Error())
+		}
+		return toStatus(err)
+	}
+	return nil
+}
+
+func (s *recruiterServer) GetRecruiter(ctx context.Context, req *usersv1.GetRecruiterRequest) (*usersv1.GetRecruiterResponse, error) {
+	if err := s.exists(ctx, req.GetPublicId()); err != nil {
+		return nil, err
+	}
+	recruiter, err := s.service.GetRecruiter(ctx, req.GetPublicId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &usersv1.GetRecruiterResponse{Recruiter: toRecruiter(recruiter)}, nil
+}
+
+func (s *recruiterServer) RecruiterExists(ctx context.Context, req *usersv1.RecruiterExistsRequest) (*usersv1.RecruiterExistsResponse, error) {
+	if err := s.service.Exists(ctx, req.GetPublicId()); err != nil {
+		if errors.Is(err, models.ErrPermissionDenied) {
+			return &usersv1.RecruiterExistsResponse{Exists: false}, nil
+		}
+		return nil, toStatus(err)
+	}
+	return &usersv1.RecruiterExistsResponse{Exists: true}, nil
+}
+
+func (s *recruiterServer) GetRecruiterInterviews(ctx context.Context, req *usersv1.GetRecruiterInterviewsRequest) (*usersv1.GetRecruiterInterviewsResponse, error) {
+	if err := s.exists(ctx, req.GetPublicId()); err != nil {
+		return nil, err
+	}
+	interviews, count, err := s.service.GetInterviewsByPublicID(ctx, req.GetPublicId(), searchArgs(req.GetSearch(), req.GetPage()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &usersv1.GetRecruiterInterviewsResponse{Interviews: toInterviewResults(interviews), Count: int32(count)}, nil
+}
@@ -0,0 +1,100 @@
+package grpchandler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	usersv1 "github.com/Zhiyenbek/sp-users-main-service/api/users/v1"
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/service"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Server is the gRPC API used by other services of the platform.
+type Server struct {
+	server *grpc.Server
+	health *health.Server
+}
+
+// New creates the gRPC server. Callers must authenticate with a client certificate,
+// a bearer token, or both, so at least one of them has to be configured.
+func New(services *service.Service, logger *zap.SugaredLogger, cfg *config.GRPCConf) (*Server, error) {
+	mTLS := cfg.ClientCAFile != ""
+	if !mTLS && len(cfg.Tokens) == 0 {
+		return nil, errors.New("grpc: neither client certificates nor tokens are configured")
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(requestIDUnary(), recoverUnary(logger), authUnary(cfg.Tokens)),
+		grpc.ChainStreamInterceptor(requestIDStream(), recoverStream(logger), authStream(cfg.Tokens)),
+	}
+	if cfg.CertFile != "" || mTLS {
+		tlsConfig, err := newTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	s := &Server{
+		server: grpc.NewServer(opts...),
+		health: health.NewServer(),
+	}
+	usersv1.RegisterCandidatesServiceServer(s.server, &candidatesServer{service: services.CandidatesService, logger: logger})
+	usersv1.RegisterRecruiterServiceServer(s.server, &recruiterServer{service: services.RecruiterService, logger: logger})
+	usersv1.RegisterCompanyServiceServer(s.server, &companyServer{service: services.CompanyService, logger: logger})
+	healthpb.RegisterHealthServer(s.server, s.health)
+
+	for name := range s.server.GetServiceInfo() {
+		s.health.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	return s, nil
+}
+
+// Serve accepts connections on lis until the server is stopped
+func (s *Server) Serve(lis net.Listener) error {
+	return s.server.Serve(lis)
+}
+
+// GracefulStop reports the services as not serving and waits for pending calls to finish
+func (s *Server) GracefulStop() {
+	s.health.Shutdown()
+	s.server.GracefulStop()
+}
+
+func newTLSConfig(cfg *config.GRPCConf) (*tls.Config, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, errors.New("grpc: cert_file and key_file are required to serve TLS")
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("grpc: load server certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if cfg.ClientCAFile == "" {
+		return tlsConfig, nil
+	}
+
+	ca, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("grpc: read client CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("grpc: no certificates found in client CA file")
+	}
+	tlsConfig.ClientCAs = pool
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	return tlsConfig, nil
+}
@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"errors"
 	"net/http"
 
 	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
@@ -9,63 +8,34 @@ import (
 )
 
 func (h *handler) GetMe(c *gin.Context) {
-	publicID := c.GetString("public_id")
 	role := c.GetString("role")
 	switch role {
 	case "candidate":
-		if err := h.service.CandidatesService.Exists(publicID); err != nil {
-			if errors.Is(err, models.ErrPermissionDenied) {
-				c.JSON(http.StatusUnauthorized, sendResponse(-1, nil, models.ErrPermissionDenied))
-				return
-			}
-			c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		publicID, ok := h.signedInCandidate(c)
+		if !ok {
 			return
 		}
-		res, err := h.service.GetCandidateByPublicID(publicID)
+		res, err := h.service.GetCandidateByPublicID(c.Request.Context(), publicID)
 		if err != nil {
-			var errMsg error
-			var code int
-			switch {
-			case errors.Is(err, models.ErrUserNotFound):
-				errMsg = models.ErrUserNotFound
-				code = http.StatusNotFound
-			default:
-				errMsg = models.ErrInternalServer
-				code = http.StatusInternalServerError
-			}
-			c.JSON(code, sendResponse(-1, nil, errMsg))
+			c.Error(err)
 			return
 		}
 
 		c.JSON(http.StatusOK, sendResponse(0, res, nil))
 		return
 	case "recruiter":
-		if err := h.service.RecruiterService.Exists(publicID); err != nil {
-			if errors.Is(err, models.ErrPermissionDenied) {
-				c.JSON(http.StatusUnauthorized, sendResponse(-1, nil, models.ErrPermissionDenied))
-				return
-			}
-			c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		publicID, ok := h.signedInRecruiter(c)
+		if !ok {
 			return
 		}
-		res, err := h.service.GetRecruiter(publicID)
+		res, err := h.service.GetRecruiter(c.Request.Context(), publicID)
 		if err != nil {
-			var errMsg error
-			var code int
-			switch {
-			case errors.Is(err, models.ErrUserNotFound):
-				errMsg = models.ErrUserNotFound
-				code = http.StatusNotFound
-			default:
-				errMsg = models.ErrInternalServer
-				code = http.StatusInternalServerError
-			}
-			c.JSON(code, sendResponse(-1, nil, errMsg))
+			c.Error(err)
 			return
 		}
 
 		c.JSON(http.StatusOK, sendResponse(0, res, nil))
 		return
 	}
-	c.JSON(http.StatusUnauthorized, sendResponse(0, nil, models.ErrPermissionDenied))
+	c.Error(unauthorized(models.ErrPermissionDenied))
 }
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/auth"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/metrics"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// VerifyToken authenticates the user with the access token sent as a bearer token, or else in the
+// access_token cookie, and sets its public ID and role in the context.
+func (h *handler) VerifyToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := accessToken(c)
+		if tokenString == "" {
+			c.Error(models.ErrInvalidToken)
+			c.Abort()
+			return
+		}
+		claims, err := h.verifier.Verify(c.Request.Context(), tokenString)
+		switch {
+		case errors.Is(err, auth.ErrRevoked):
+			metrics.TokensRejected.WithLabelValues("revoked").Inc()
+			c.Error(models.ErrTokenRevoked)
+			c.Abort()
+			return
+		case errors.Is(err, auth.ErrInvalidToken):
+			metrics.TokensRejected.WithLabelValues("invalid").Inc()
+			c.Error(models.ErrInvalidToken.Wrap(err))
+			c.Abort()
+			return
+		case err != nil:
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Set("role", claims.Role)
+		c.Set("public_id", claims.PublicID)
+		c.Next()
+	}
+}
+
+// accessToken returns the bearer token of the request, or else the access_token cookie
+func accessToken(c *gin.Context) string {
+	if token := bearerToken(c); token != "" {
+		return token
+	}
+	token, _ := c.Cookie(accessTokenCookie)
+	return token
+}
+
+// bearerToken returns the token of the Authorization header, or an empty string for any other scheme
+func bearerToken(c *gin.Context) string {
+	scheme, token, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -1,13 +1,11 @@
 package handler
 
 import (
-	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
 	"github.com/gin-gonic/gin"
-	"github.com/gin-gonic/gin/binding"
 )
 
 type GetCandidatesResult struct {
@@ -36,20 +34,15 @@ func (h *handler) GetCandidates(c *gin.Context) {
 		PageNum:  pageNum,
 		PageSize: pageSize,
 		Search:   c.Query("search"),
+		Filters: &models.CandidateFilters{
+			Skills:          c.QueryArray("skills"),
+			CurrentPosition: c.Query("current_position"),
+			Education:       c.Query("education"),
+		},
 	}
-	res, count, err := h.service.GetCandidatesBySearch(searchArgs)
+	res, count, err := h.service.GetCandidatesBySearch(c.Request.Context(), searchArgs)
 	if err != nil {
-		var errMsg error
-		var code int
-		switch {
-		case errors.Is(err, models.ErrUsernameExists):
-			errMsg = models.ErrUsernameExists
-			code = http.StatusBadRequest
-		default:
-			errMsg = models.ErrInternalServer
-			code = http.StatusInternalServerError
-		}
-		c.JSON(code, sendResponse(-1, nil, errMsg))
+		c.Error(err)
 		return
 	}
 
@@ -61,30 +54,23 @@ func (h *handler) GetCandidates(c *gin.Context) {
 
 func (h *handler) UpdateCandidate(c *gin.Context) {
 	req := &models.Candidate{}
-	if err := c.ShouldBindJSON(req); err != nil {
-		h.logger.Errorf("failed to parse request body when updating candidate. %s\n", err.Error())
-		c.AbortWithStatusJSON(http.StatusBadRequest, sendResponse(-1, nil, models.ErrInvalidInput))
+	if !h.bindJSON(c, req) {
 		return
 	}
 
-	publicID := c.GetString("public_id")
-	if err := h.service.CandidatesService.Exists(publicID); err != nil {
-		if errors.Is(err, models.ErrPermissionDenied) {
-			c.JSON(http.StatusUnauthorized, sendResponse(-1, nil, models.ErrPermissionDenied))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+	publicID, ok := h.signedInCandidate(c)
+	if !ok {
 		return
 	}
-	err := h.service.UpdateCandidateByID(publicID, req)
+	err := h.service.UpdateCandidateByID(c.Request.Context(), publicID, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		c.Error(err)
 		return
 	}
 
-	res, err := h.service.GetCandidateByPublicID(publicID)
+	res, err := h.service.GetCandidateByPublicID(c.Request.Context(), publicID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, sendResponse(0, res, nil))
@@ -92,19 +78,9 @@ func (h *handler) UpdateCandidate(c *gin.Context) {
 
 func (h *handler) GetCandidateByPublicID(c *gin.Context) {
 	publicID := c.Param("candidate_public_id")
-	res, err := h.service.GetCandidateByPublicID(publicID)
+	res, err := h.service.GetCandidateByPublicID(c.Request.Context(), publicID)
 	if err != nil {
-		var errMsg error
-		var code int
-		switch {
-		case errors.Is(err, models.ErrUserNotFound):
-			errMsg = models.ErrUserNotFound
-			code = http.StatusNotFound
-		default:
-			errMsg = models.ErrInternalServer
-			code = http.StatusInternalServerError
-		}
-		c.JSON(code, sendResponse(-1, nil, errMsg))
+		c.Error(err)
 		return
 	}
 
@@ -113,28 +89,21 @@ func (h *handler) GetCandidateByPublicID(c *gin.Context) {
 
 func (h *handler) CreateSkillsForCandidate(c *gin.Context) {
 	req := &skillsReq{}
-	if err := c.ShouldBindWith(req, binding.JSON); err != nil {
-		h.logger.Errorf("failed to parse request body when signing up candidate. %s\n", err.Error())
-		c.AbortWithStatusJSON(400, sendResponse(-1, nil, models.ErrInvalidInput))
+	if !h.bindJSON(c, req) {
 		return
 	}
 	if len(req.Skills) == 0 {
-		c.JSON(http.StatusBadRequest, sendResponse(-1, nil, models.ErrInvalidInput))
+		c.Error(models.ErrInvalidInput.WithField("skills", "must not be empty"))
 		return
 	}
 
-	publicID := c.GetString("public_id")
-	if err := h.service.CandidatesService.Exists(publicID); err != nil {
-		if errors.Is(err, models.ErrPermissionDenied) {
-			c.JSON(http.StatusUnauthorized, sendResponse(-1, nil, models.ErrPermissionDenied))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+	publicID, ok := h.signedInCandidate(c)
+	if !ok {
 		return
 	}
-	err := h.service.AddSkillsToCandidate(publicID, req.Skills)
+	err := h.service.AddSkillsToCandidate(c.Request.Context(), publicID, req.Skills)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusCreated, sendResponse(0, nil, nil))
@@ -142,77 +111,83 @@ func (h *handler) CreateSkillsForCandidate(c *gin.Context) {
 
 func (h *handler) DeleteSkillsFromCandidate(c *gin.Context) {
 	req := &skillsReq{}
-	if err := c.ShouldBindWith(req, binding.JSON); err != nil {
-		h.logger.Errorf("failed to parse request body when signing up candidate. %s\n", err.Error())
-		c.AbortWithStatusJSON(400, sendResponse(-1, nil, models.ErrInvalidInput))
+	if !h.bindJSON(c, req) {
 		return
 	}
 	if len(req.Skills) == 0 {
-		c.JSON(http.StatusBadRequest, sendResponse(-1, nil, models.ErrInvalidInput))
+		c.Error(models.ErrInvalidInput.WithField("skills", "must not be empty"))
 		return
 	}
 
-	publicID := c.GetString("public_id")
-	if err := h.service.CandidatesService.Exists(publicID); err != nil {
-		if errors.Is(err, models.ErrPermissionDenied) {
-			c.JSON(http.StatusUnauthorized, sendResponse(-1, nil, models.ErrPermissionDenied))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+	publicID, ok := h.signedInCandidate(c)
+	if !ok {
 		return
 	}
-	err := h.service.DeleteSkillsFromCandidate(publicID, req.Skills)
+	err := h.service.DeleteSkillsFromCandidate(c.Request.Context(), publicID, req.Skills)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusCreated, sendResponse(0, nil, nil))
 }
 
+// ReplaceCandidateSkills sets the skills of the signed in candidate to exactly the given ones, an
+// empty list removes them all
+func (h *handler) ReplaceCandidateSkills(c *gin.Context) {
+	req := &skillsReq{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+	if req.Skills == nil {
+		c.Error(models.ErrInvalidInput.WithField("skills", "is required"))
+		return
+	}
+
+	publicID, ok := h.signedInCandidate(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.ReplaceCandidateSkills(c.Request.Context(), publicID, req.Skills)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
+
 func (h *handler) UpdateCandidateByPublicID(c *gin.Context) {
 	req := &models.Candidate{}
-	if err := c.ShouldBindJSON(req); err != nil {
-		h.logger.Errorf("failed to parse request body when updating candidate. %s\n", err.Error())
-		c.AbortWithStatusJSON(http.StatusBadRequest, sendResponse(-1, nil, models.ErrInvalidInput))
+	if !h.bindJSON(c, req) {
 		return
 	}
 
 	publicID := c.Param("candidate_public_id")
-	if err := h.service.CandidatesService.Exists(publicID); err != nil {
-		if errors.Is(err, models.ErrPermissionDenied) {
-			c.JSON(http.StatusNotFound, sendResponse(-1, nil, models.ErrUserNotFound))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+	if err := h.service.CandidatesService.Exists(c.Request.Context(), publicID); err != nil {
+		c.Error(notFound(err, models.ErrUserNotFound))
 		return
 	}
-	err := h.service.UpdateCandidateByID(publicID, req)
+	err := h.service.UpdateCandidateByID(c.Request.Context(), publicID, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		c.Error(err)
 		return
 	}
 
-	res, err := h.service.GetCandidateByPublicID(publicID)
+	res, err := h.service.GetCandidateByPublicID(c.Request.Context(), publicID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, sendResponse(0, res, nil))
 }
 
 func (h *handler) DeleteCandidate(c *gin.Context) {
-	publicID := c.GetString("public_id")
-	if err := h.service.CandidatesService.Exists(publicID); err != nil {
-		if errors.Is(err, models.ErrPermissionDenied) {
-			c.JSON(http.StatusUnauthorized, sendResponse(-1, nil, models.ErrPermissionDenied))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+	publicID, ok := h.signedInCandidate(c)
+	if !ok {
 		return
 	}
-	err := h.service.DeleteCandidateByID(publicID)
+	err := h.service.DeleteCandidateByID(c.Request.Context(), publicID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		c.Error(err)
 		return
 	}
 
@@ -221,17 +196,13 @@ func (h *handler) DeleteCandidate(c *gin.Context) {
 
 func (h *handler) DeleteCandidateByPublicID(c *gin.Context) {
 	publicID := c.Param("candidate_public_id")
-	if err := h.service.CandidatesService.Exists(publicID); err != nil {
-		if errors.Is(err, models.ErrPermissionDenied) {
-			c.JSON(http.StatusNotFound, sendResponse(-1, nil, models.ErrUserNotFound))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+	if err := h.service.CandidatesService.Exists(c.Request.Context(), publicID); err != nil {
+		c.Error(notFound(err, models.ErrUserNotFound))
 		return
 	}
-	err := h.service.DeleteCandidateByID(publicID)
+	err := h.service.DeleteCandidateByID(c.Request.Context(), publicID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		c.Error(err)
 		return
 	}
 
@@ -240,12 +211,8 @@ func (h *handler) DeleteCandidateByPublicID(c *gin.Context) {
 
 func (h *handler) GetCandidateInterviewsByID(c *gin.Context) {
 	publicID := c.Param("candidate_public_id")
-	if err := h.service.CandidatesService.Exists(publicID); err != nil {
-		if errors.Is(err, models.ErrPermissionDenied) {
-			c.JSON(http.StatusNotFound, sendResponse(-1, nil, models.ErrUserNotFound))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+	if err := h.service.CandidatesService.Exists(c.Request.Context(), publicID); err != nil {
+		c.Error(notFound(err, models.ErrUserNotFound))
 		return
 	}
 	pageNum, err := strconv.Atoi(c.Query("page_num"))
@@ -263,9 +230,9 @@ func (h *handler) GetCandidateInterviewsByID(c *gin.Context) {
 		Search:   c.Query("search"),
 	}
 
-	res, count, err := h.service.CandidatesService.GetInterviewsByPublicID(publicID, searchArgs)
+	res, count, err := h.service.CandidatesService.GetInterviewsByPublicID(c.Request.Context(), publicID, searchArgs)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, sendResponse(0, InterviewResponse{
@@ -275,13 +242,8 @@ func (h *handler) GetCandidateInterviewsByID(c *gin.Context) {
 }
 
 func (h *handler) GetCandidateInterviews(c *gin.Context) {
-	publicID := c.GetString("public_id")
-	if err := h.service.CandidatesService.Exists(publicID); err != nil {
-		if errors.Is(err, models.ErrPermissionDenied) {
-			c.JSON(http.StatusNotFound, sendResponse(-1, nil, models.ErrUserNotFound))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+	publicID, ok := h.signedInCandidate(c)
+	if !ok {
 		return
 	}
 	pageNum, err := strconv.Atoi(c.Query("page_num"))
@@ -299,9 +261,9 @@ func (h *handler) GetCandidateInterviews(c *gin.Context) {
 		Search:   c.Query("search"),
 	}
 
-	res, count, err := h.service.CandidatesService.GetInterviewsByPublicID(publicID, searchArgs)
+	res, count, err := h.service.CandidatesService.GetInterviewsByPublicID(c.Request.Context(), publicID, searchArgs)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, sendResponse(0, InterviewResponse{
@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"errors"
 	"net/http"
 	"strconv"
 
@@ -14,42 +13,28 @@ type GetCompaniesResult struct {
 	Count     int               `json:"count"`
 }
 
-func (h *handler) CreateCompany(c *gin.Context) {
-	company := &models.Company{}
-	if err := c.ShouldBindJSON(&company); err != nil {
-		c.JSON(http.StatusBadRequest, sendResponse(-1, nil, models.ErrInvalidInput))
-		return
-	}
-
-	publicID, err := h.service.CompanyService.CreateCompany(company)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+func (h *handler) UpdateCompany(c *gin.Context) {
+	recruiterID, ok := h.signedInRecruiter(c)
+	if !ok {
 		return
 	}
-	company.PublicID = publicID
-
-	c.JSON(http.StatusCreated, sendResponse(0, company, nil))
-}
-
-func (h *handler) UpdateCompany(c *gin.Context) {
 	publicID := c.Param("public_id")
 
 	company := &models.Company{}
-	if err := c.ShouldBindJSON(&company); err != nil {
-		c.JSON(http.StatusBadRequest, sendResponse(-1, nil, models.ErrInvalidInput))
+	if !h.bindJSON(c, company) {
 		return
 	}
 
 	company.PublicID = publicID
 
-	if err := h.service.CompanyService.UpdateCompany(company); err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+	if err := h.service.CompanyService.EditCompany(c.Request.Context(), recruiterID, company); err != nil {
+		c.Error(err)
 		return
 	}
 
-	res, err := h.service.CompanyService.GetCompany(publicID)
+	res, err := h.service.CompanyService.GetCompany(c.Request.Context(), publicID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		c.Error(err)
 		return
 	}
 
@@ -58,17 +43,13 @@ func (h *handler) UpdateCompany(c *gin.Context) {
 
 func (h *handler) GetCompany(c *gin.Context) {
 	publicID := c.Param("public_id")
-	if err := h.service.CompanyService.Exists(publicID); err != nil {
-		if errors.Is(err, models.ErrPermissionDenied) {
-			c.JSON(http.StatusNotFound, sendResponse(-1, nil, models.ErrCompanyNotFound))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+	if err := h.service.CompanyService.Exists(c.Request.Context(), publicID); err != nil {
+		c.Error(notFound(err, models.ErrCompanyNotFound))
 		return
 	}
-	company, err := h.service.CompanyService.GetCompany(publicID)
+	company, err := h.service.CompanyService.GetCompany(c.Request.Context(), publicID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		c.Error(err)
 		return
 	}
 
@@ -92,9 +73,9 @@ func (h *handler) GetCompanies(c *gin.Context) {
 		Search:   c.Query("search"),
 	}
 
-	companies, count, err := h.service.CompanyService.GetCompanies(searchArgs)
+	companies, count, err := h.service.CompanyService.GetCompanies(c.Request.Context(), searchArgs)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		c.Error(err)
 		return
 	}
 
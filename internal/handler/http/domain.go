@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+type claimDomainReq struct {
+	Domain string `json:"domain" binding:"required,max=253"`
+	Method string `json:"method"`
+	Email  string `json:"email" binding:"omitempty,max=50,email"`
+}
+
+type verifyDomainReq struct {
+	Code string `json:"code"`
+}
+
+func (h *handler) GetCompanyDomains(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.GetDomains(c.Request.Context(), publicID, c.Param("public_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
+
+func (h *handler) ClaimCompanyDomain(c *gin.Context) {
+	req := &claimDomainReq{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.ClaimDomain(c.Request.Context(), publicID, c.Param("public_id"), &models.CompanyDomain{
+		Domain: req.Domain,
+		Method: req.Method,
+		Email:  req.Email,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sendResponse(0, res, nil))
+}
+
+func (h *handler) VerifyCompanyDomain(c *gin.Context) {
+	req := &verifyDomainReq{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.VerifyDomain(c.Request.Context(), publicID, c.Param("public_id"), c.Param("domain_public_id"), req.Code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
+
+func (h *handler) DeleteCompanyDomain(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteDomain(c.Request.Context(), publicID, c.Param("public_id"), c.Param("domain_public_id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, nil, nil))
+}
+
+func (h *handler) JoinCompany(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.JoinCompany(c.Request.Context(), publicID, c.Param("public_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
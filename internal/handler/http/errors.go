@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// HandleErrors renders the last error added to the context with c.Error into the response envelope.
+// Domain errors are sent with their own status; any other error is logged and sent as an internal server error.
+func (h *handler) HandleErrors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+
+		var domainErr *models.Error
+		if !errors.As(err, &domainErr) {
+			domainErr = models.ErrInternalServer.Wrap(err)
+		}
+		if domainErr.Status >= http.StatusInternalServerError {
+			logging.FromContext(c.Request.Context(), h.logger).Errorf("%s %s failed: %v", c.Request.Method, c.Request.URL.Path, err)
+		}
+		c.JSON(domainErr.Status, sendResponse(-1, nil, domainErr))
+	}
+}
+
+// bindJSON decodes the request body into req. When the body cannot be decoded
+// the error is added to the context and false is returned.
+func (h *handler) bindJSON(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.Error(bindError(err))
+		return false
+	}
+	return true
+}
+
+// bindError turns a body decoding error into an invalid input error naming the offending field
+func bindError(err error) *models.Error {
+	var (
+		validationErrs validator.ValidationErrors
+		typeErr        *json.UnmarshalTypeError
+		syntaxErr      *json.SyntaxError
+	)
+	switch {
+	case errors.As(err, &validationErrs):
+		return models.ErrInvalidInput.Wrap(err).WithFields(validationFields(validationErrs)...)
+	case errors.As(err, &typeErr):
+		return models.ErrInvalidInput.Wrap(err).WithField(typeErr.Field, fmt.Sprintf("must be of type %s", typeErr.Type))
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return models.ErrInvalidInput.Wrap(err).WithField("body", "is not valid JSON")
+	case errors.Is(err, io.EOF):
+		return models.ErrInvalidInput.Wrap(err).WithField("body", "is required")
+	default:
+		return models.ErrInvalidInput.Wrap(err)
+	}
+}
+
+// signedInCandidate returns the public ID of the signed in candidate. When the user is not
+// a candidate the error is added to the context and false is returned.
+func (h *handler) signedInCandidate(c *gin.Context) (string, bool) {
+	publicID := c.GetString("public_id")
+	if err := h.service.CandidatesService.Exists(c.Request.Context(), publicID); err != nil {
+		c.Error(unauthorized(err))
+		return "", false
+	}
+	return publicID, true
+}
+
+// signedInRecruiter returns the public ID of the signed in recruiter. When the user is not
+// a recruiter the error is added to the context and false is returned.
+func (h *handler) signedInRecruiter(c *gin.Context) (string, bool) {
+	publicID := c.GetString("public_id")
+	if err := h.service.RecruiterService.Exists(c.Request.Context(), publicID); err != nil {
+		c.Error(unauthorized(err))
+		return "", false
+	}
+	return publicID, true
+}
+
+// unauthorized reports a failed Exists check of the signed in user with 401, as the token belongs to no such user
+func unauthorized(err error) error {
+	if errors.Is(err, models.ErrPermissionDenied) {
+		return models.ErrPermissionDenied.WithStatus(http.StatusUnauthorized)
+	}
+	return err
+}
+
+// notFound reports a failed Exists check of the user or company in the path as notFoundErr
+func notFound(err error, notFoundErr *models.Error) error {
+	if errors.Is(err, models.ErrPermissionDenied) {
+		return notFoundErr
+	}
+	return err
+}
@@ -1,53 +1,163 @@
 package handler
 
 import (
+	"errors"
+	"expvar"
+	"net/http"
+
 	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/auth"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/health"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/openapi"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/ratelimit"
 	"github.com/Zhiyenbek/sp-users-main-service/internal/service"
-	"github.com/Zhiyenbek/users-auth-service/middleware"
-	"github.com/gin-contrib/cors"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/tracing"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
 )
 
 type handler struct {
-	service *service.Service
-	cfg     *config.Configs
-	logger  *zap.SugaredLogger
+	service  *service.Service
+	cfg      *config.Configs
+	logger   *zap.SugaredLogger
+	limiter  ratelimit.Limiter
+	spec     *openapi.Spec
+	health   *health.Checker
+	verifier *auth.Verifier
 }
 
 type Handler interface {
 	InitRoutes() *gin.Engine
 }
 
-func New(services *service.Service, logger *zap.SugaredLogger, cfg *config.Configs) Handler {
+func New(services *service.Service, logger *zap.SugaredLogger, cfg *config.Configs, limiter ratelimit.Limiter, spec *openapi.Spec, checker *health.Checker, verifier *auth.Verifier) Handler {
+	registerValidations()
 	return &handler{
-		service: services,
-		cfg:     cfg,
-		logger:  logger,
+		service:  services,
+		cfg:      cfg,
+		logger:   logger,
+		limiter:  limiter,
+		spec:     spec,
+		health:   checker,
+		verifier: verifier,
 	}
 }
 
 func (h *handler) InitRoutes() *gin.Engine {
-	router := gin.Default()
-	router.Use(cors.Default())
-	router.GET("/account", middleware.VerifyToken(h.cfg.Token.TokenSecret, h.logger), h.GetMe)
+	router := gin.New()
+	router.Use(gin.Recovery())
+	// scrapes of /metrics and probes are frequent and of no interest in traces
+	router.Use(otelgin.Middleware(tracing.ServiceName(h.cfg.Tracing), otelgin.WithFilter(func(r *http.Request) bool {
+		switch r.URL.Path {
+		case "/metrics", "/healthz", "/readyz":
+			return false
+		}
+		return true
+	})))
+	router.Use(h.RequestID())
+	router.Use(h.AccessLog())
+	router.Use(h.Metrics())
+	router.Use(h.SecurityHeaders())
+	if corsHandler := h.CORS(); corsHandler != nil {
+		router.Use(corsHandler)
+	}
+	router.Use(h.HandleErrors())
+	// probes are registered before the rate limit, so they are never throttled
+	router.GET("/healthz", h.Healthz)
+	router.GET("/readyz", h.Readyz)
+	router.GET("/health", h.Health)
+	if h.cfg.CSRF.Enabled {
+		router.Use(h.CSRF())
+		router.GET("/csrf-token", h.GetCSRFToken)
+	}
+	// the auth service provisions every user signing up from its own address, so its calls are
+	// authenticated by service token and not rate limited per IP
+	router.POST("/internal/candidates", h.ServiceAuth(), h.ValidateRequest(), h.ProvisionCandidate)
+	router.POST("/internal/recruiters", h.ServiceAuth(), h.ValidateRequest(), h.ProvisionRecruiter)
+	router.POST("/internal/companies", h.ServiceAuth(), h.ValidateRequest(), h.FindOrCreateCompany)
+	if h.cfg.RateLimit != nil && h.cfg.RateLimit.Enabled {
+		if err := router.SetTrustedProxies(h.cfg.RateLimit.TrustedProxies); err != nil {
+			h.logger.Errorf("invalid trusted proxies: %v", err)
+		}
+		router.Use(h.RateLimit())
+	}
+	router.Use(h.ValidateRequest())
+	router.GET("/openapi.json", h.GetOpenAPI)
+	router.StaticFS(openapi.SwaggerUIPath, openapi.SwaggerUI())
+	router.GET("/debug/vars", gin.WrapH(expvar.Handler()))
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	router.GET("/account", h.VerifyToken(), h.GetMe)
 	router.GET("/candidates", h.GetCandidates)
 	router.GET("/candidate/:candidate_public_id", h.GetCandidateByPublicID)
-	router.PUT("/candidate", middleware.VerifyToken(h.cfg.Token.TokenSecret, h.logger), h.UpdateCandidate)
+	router.PUT("/candidate", h.VerifyToken(), h.UpdateCandidate)
 	router.PUT("/candidate/:candidate_public_id", h.UpdateCandidateByPublicID)
 	router.DELETE("/candidate/:candidate_public_id", h.DeleteCandidateByPublicID)
-	router.DELETE("/candidate", middleware.VerifyToken(h.cfg.Token.TokenSecret, h.logger), h.DeleteCandidate)
-	router.POST("/candidate/skills", middleware.VerifyToken(h.cfg.Token.TokenSecret, h.logger), h.CreateSkillsForCandidate)
-	router.DELETE("/candidate/skills", middleware.VerifyToken(h.cfg.Token.TokenSecret, h.logger), h.DeleteSkillsFromCandidate)
+	router.DELETE("/candidate", h.VerifyToken(), h.DeleteCandidate)
+	router.POST("/candidate/skills", h.VerifyToken(), h.CreateSkillsForCandidate)
+	router.DELETE("/candidate/skills", h.VerifyToken(), h.DeleteSkillsFromCandidate)
+	router.PUT("/candidate/skills", h.VerifyToken(), h.ReplaceCandidateSkills)
 	router.GET("/candidate/:candidate_public_id/interviews", h.GetCandidateInterviewsByID)
-	router.GET("/candidate/interviews", middleware.VerifyToken(h.cfg.Token.TokenSecret, h.logger), h.GetCandidateInterviews)
+	router.GET("/candidate/interviews", h.VerifyToken(), h.GetCandidateInterviews)
 	router.GET("/recruiter/:recruiter_public_id", h.GetRecruiter)
 	router.GET("/recruiter/:recruiter_public_id/interviews", h.GetRecruiterInterviewsByID)
-	router.GET("/recruiter/interviews", middleware.VerifyToken(h.cfg.Token.TokenSecret, h.logger), h.GetRecruiterInterviews)
-	router.POST("/company", h.CreateCompany)
+	router.GET("/recruiter/interviews", h.VerifyToken(), h.GetRecruiterInterviews)
 	router.GET("/companies", h.GetCompanies)
 	router.GET("/company/:public_id", h.GetCompany)
-	router.PUT("/company/:public_id", h.UpdateCompany)
+	router.PUT("/company/:public_id", h.VerifyToken(), h.UpdateCompany)
+	router.POST("/company/:public_id/webhooks", h.VerifyToken(), h.CreateWebhook)
+	router.GET("/company/:public_id/webhooks", h.VerifyToken(), h.GetWebhooks)
+	router.GET("/company/:public_id/webhooks/:webhook_public_id", h.VerifyToken(), h.GetWebhook)
+	router.PUT("/company/:public_id/webhooks/:webhook_public_id", h.VerifyToken(), h.UpdateWebhook)
+	router.DELETE("/company/:public_id/webhooks/:webhook_public_id", h.VerifyToken(), h.DeleteWebhook)
+	router.GET("/company/:public_id/webhooks/:webhook_public_id/deliveries", h.VerifyToken(), h.GetWebhookDeliveries)
+	router.POST("/company/:public_id/webhooks/:webhook_public_id/deliveries/:delivery_public_id/replay", h.VerifyToken(), h.ReplayWebhookDelivery)
+	router.GET("/company/:public_id/members", h.VerifyToken(), h.GetCompanyMembers)
+	router.PUT("/company/:public_id/members/:recruiter_public_id", h.VerifyToken(), h.UpdateCompanyMember)
+	router.DELETE("/company/:public_id/members/:recruiter_public_id", h.VerifyToken(), h.RemoveCompanyMember)
+	router.POST("/company/:public_id/invitations", h.VerifyToken(), h.CreateInvitation)
+	router.GET("/company/:public_id/invitations", h.VerifyToken(), h.GetInvitations)
+	router.DELETE("/company/:public_id/invitations/:invitation_public_id", h.VerifyToken(), h.RevokeInvitation)
+	router.POST("/invitations/accept", h.VerifyToken(), h.AcceptInvitation)
+	router.POST("/company/:public_id/join-requests", h.VerifyToken(), h.CreateJoinRequest)
+	router.GET("/company/:public_id/join-requests", h.VerifyToken(), h.GetJoinRequests)
+	router.POST("/company/:public_id/join-requests/:join_request_public_id/approve", h.VerifyToken(), h.ApproveJoinRequest)
+	router.POST("/company/:public_id/join-requests/:join_request_public_id/reject", h.VerifyToken(), h.RejectJoinRequest)
+	router.GET("/company/:public_id/domains", h.VerifyToken(), h.GetCompanyDomains)
+	router.POST("/company/:public_id/domains", h.VerifyToken(), h.ClaimCompanyDomain)
+	router.POST("/company/:public_id/domains/:domain_public_id/verify", h.VerifyToken(), h.VerifyCompanyDomain)
+	router.DELETE("/company/:public_id/domains/:domain_public_id", h.VerifyToken(), h.DeleteCompanyDomain)
+	router.POST("/company/:public_id/join", h.VerifyToken(), h.JoinCompany)
+	router.POST("/shortlist", h.VerifyToken(), h.CreateShortlist)
+	router.GET("/shortlists", h.VerifyToken(), h.GetShortlists)
+	router.GET("/shortlist/:shortlist_public_id", h.VerifyToken(), h.GetShortlist)
+	router.PUT("/shortlist/:shortlist_public_id", h.VerifyToken(), h.UpdateShortlist)
+	router.DELETE("/shortlist/:shortlist_public_id", h.VerifyToken(), h.DeleteShortlist)
+	router.GET("/shortlist/:shortlist_public_id/candidates", h.VerifyToken(), h.GetShortlistCandidates)
+	router.POST("/shortlist/:shortlist_public_id/candidates", h.VerifyToken(), h.AddCandidateToShortlist)
+	router.DELETE("/shortlist/:shortlist_public_id/candidates/:candidate_public_id", h.VerifyToken(), h.RemoveCandidateFromShortlist)
+	router.GET("/shortlist/:shortlist_public_id/export", h.VerifyToken(), h.ExportShortlist)
+	router.GET("/candidate/:candidate_public_id/notes", h.VerifyToken(), h.GetCandidateNotes)
+	router.POST("/candidate/:candidate_public_id/notes", h.VerifyToken(), h.CreateCandidateNote)
+	router.GET("/interview/:interview_public_id/notes", h.VerifyToken(), h.GetInterviewNotes)
+	router.POST("/interview/:interview_public_id/notes", h.VerifyToken(), h.CreateInterviewNote)
+	router.GET("/recruiter/mentions", h.VerifyToken(), h.GetNoteMentions)
+	router.GET("/note/:note_public_id", h.VerifyToken(), h.GetNote)
+	router.PUT("/note/:note_public_id", h.VerifyToken(), h.UpdateNote)
+	router.DELETE("/note/:note_public_id", h.VerifyToken(), h.DeleteNote)
+	router.GET("/note/:note_public_id/history", h.VerifyToken(), h.GetNoteHistory)
+	router.POST("/saved-search", h.VerifyToken(), h.CreateSavedSearch)
+	router.GET("/saved-searches", h.VerifyToken(), h.GetSavedSearches)
+	router.GET("/saved-search/alerts", h.VerifyToken(), h.GetSavedSearchAlerts)
+	router.PUT("/saved-search/alerts/seen", h.VerifyToken(), h.MarkSavedSearchAlertsSeen)
+	router.GET("/saved-search/:saved_search_public_id", h.VerifyToken(), h.GetSavedSearch)
+	router.DELETE("/saved-search/:saved_search_public_id", h.VerifyToken(), h.DeleteSavedSearch)
+	router.GET("/saved-search/:saved_search_public_id/run", h.VerifyToken(), h.RunSavedSearch)
+	router.GET("/notifications", h.VerifyToken(), h.GetNotifications)
+	router.GET("/notifications/preferences", h.VerifyToken(), h.GetNotificationPreferences)
+	router.PUT("/notifications/preferences", h.VerifyToken(), h.UpdateNotificationPreferences)
 	return router
 }
 
@@ -57,6 +167,15 @@ func sendResponse(status int, data interface{}, err error) gin.H {
 		errResponse = gin.H{
 			"message": err.Error(),
 		}
+		var domainErr *models.Error
+		if errors.As(err, &domainErr) {
+			if domainErr.Message != "" {
+				errResponse["detail"] = domainErr.Message
+			}
+			if len(domainErr.Fields) > 0 {
+				errResponse["fields"] = domainErr.Fields
+			}
+		}
 	} else {
 		errResponse = nil
 	}
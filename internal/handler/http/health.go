@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/health"
+	"github.com/gin-gonic/gin"
+)
+
+// Healthz reports that the process is running. It checks no dependencies, so a failing
+// database does not get the service restarted.
+func (h *handler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// Readyz reports whether the service can take traffic: the database and schema are available
+// and it is not shutting down.
+func (h *handler) Readyz(c *gin.Context) {
+	if h.health.ShuttingDown() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": health.StatusShutdown})
+		return
+	}
+	report := h.health.Run(c.Request.Context())
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, gin.H{"status": report.Status})
+}
+
+// Health reports the state and latency of every dependency
+func (h *handler) Health(c *gin.Context) {
+	report := h.health.Run(c.Request.Context())
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
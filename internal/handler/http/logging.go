@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// requestIDRe limits the request IDs accepted from callers, so they cannot inject arbitrary text into logs
+var requestIDRe = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID takes the request ID from the X-Request-ID header, or generates one, and adds it to
+// the request context and the response.
+func (h *handler) RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if !requestIDRe.MatchString(id) {
+			id = uuid.NewString()
+		}
+		c.Header(requestIDHeader, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// AccessLog logs every served request. Probes and metric scrapes are only logged at debug level.
+// The query string is left out as it may hold search terms with personal data.
+func (h *handler) AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		log := logging.FromContext(c.Request.Context(), h.logger)
+		fields := []interface{}{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"route", c.FullPath(),
+			"status", c.Writer.Status(),
+			"bytes", c.Writer.Size(),
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+		}
+		switch c.Request.URL.Path {
+		case "/healthz", "/readyz", "/metrics":
+			log.Debugw("request served", fields...)
+		default:
+			log.Infow("request served", fields...)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+type GetInvitationsResult struct {
+	Invitations []*models.Invitation `json:"invitations"`
+	Count       int                  `json:"count"`
+}
+
+type GetJoinRequestsResult struct {
+	JoinRequests []*models.JoinRequest `json:"join_requests"`
+	Count        int                   `json:"count"`
+}
+
+type memberRoleReq struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type invitationReq struct {
+	Email string `json:"email" binding:"required,max=50,email"`
+	Role  string `json:"role"`
+}
+
+type acceptInvitationReq struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type joinRequestReq struct {
+	Message string `json:"message" binding:"max=200"`
+}
+
+type approveJoinRequestReq struct {
+	Role string `json:"role"`
+}
+
+func (h *handler) GetCompanyMembers(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.GetMembers(c.Request.Context(), publicID, c.Param("public_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
+
+func (h *handler) UpdateCompanyMember(c *gin.Context) {
+	req := &memberRoleReq{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.UpdateMemberRole(c.Request.Context(), publicID, c.Param("public_id"), c.Param("recruiter_public_id"), req.Role)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
+
+func (h *handler) RemoveCompanyMember(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	if err := h.service.RemoveMember(c.Request.Context(), publicID, c.Param("public_id"), c.Param("recruiter_public_id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, nil, nil))
+}
+
+func (h *handler) CreateInvitation(c *gin.Context) {
+	req := &invitationReq{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.CreateInvitation(c.Request.Context(), publicID, c.Param("public_id"), &models.Invitation{
+		Email: req.Email,
+		Role:  req.Role,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sendResponse(0, res, nil))
+}
+
+func (h *handler) GetInvitations(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	pageNum, err := strconv.Atoi(c.Query("page_num"))
+	if err != nil || pageNum < 1 {
+		pageNum = models.DefaultPageNum
+	}
+	pageSize, err := strconv.Atoi(c.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = models.DefaultPageSize
+	}
+
+	searchArgs := &models.SearchArgs{
+		PageNum:  pageNum,
+		PageSize: pageSize,
+	}
+	res, count, err := h.service.GetInvitations(c.Request.Context(), publicID, c.Param("public_id"), searchArgs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, sendResponse(0, GetInvitationsResult{
+		Invitations: res,
+		Count:       count,
+	}, nil))
+}
+
+func (h *handler) RevokeInvitation(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	if err := h.service.RevokeInvitation(c.Request.Context(), publicID, c.Param("public_id"), c.Param("invitation_public_id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, nil, nil))
+}
+
+func (h *handler) AcceptInvitation(c *gin.Context) {
+	req := &acceptInvitationReq{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.AcceptInvitation(c.Request.Context(), publicID, req.Token)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
+
+func (h *handler) CreateJoinRequest(c *gin.Context) {
+	req := &joinRequestReq{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.CreateJoinRequest(c.Request.Context(), publicID, c.Param("public_id"), req.Message)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sendResponse(0, res, nil))
+}
+
+func (h *handler) GetJoinRequests(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	pageNum, err := strconv.Atoi(c.Query("page_num"))
+	if err != nil || pageNum < 1 {
+		pageNum = models.DefaultPageNum
+	}
+	pageSize, err := strconv.Atoi(c.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = models.DefaultPageSize
+	}
+
+	searchArgs := &models.SearchArgs{
+		PageNum:  pageNum,
+		PageSize: pageSize,
+	}
+	res, count, err := h.service.GetJoinRequests(c.Request.Context(), publicID, c.Param("public_id"), c.Query("status"), searchArgs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, sendResponse(0, GetJoinRequestsResult{
+		JoinRequests: res,
+		Count:        count,
+	}, nil))
+}
+
+func (h *handler) ApproveJoinRequest(c *gin.Context) {
+	req := &approveJoinRequestReq{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.ApproveJoinRequest(c.Request.Context(), publicID, c.Param("public_id"), c.Param("join_request_public_id"), req.Role)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
+
+func (h *handler) RejectJoinRequest(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.RejectJoinRequest(c.Request.Context(), publicID, c.Param("public_id"), c.Param("join_request_public_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics records the duration and status of every request by its route.
+// Requests that match no route share the "unmatched" route so unknown paths do not create new series.
+func (h *handler) Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveRequest(c.Request.Method, route, strconv.Itoa(c.Writer.Status()), time.Since(start))
+	}
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+type GetNotesResult struct {
+	Notes []*models.Note `json:"notes"`
+	Count int            `json:"count"`
+}
+
+type noteReq struct {
+	Body   *string `json:"body"`
+	Rating *int    `json:"rating"`
+}
+
+func (h *handler) CreateCandidateNote(c *gin.Context) {
+	h.createNote(c, models.NoteSubjectCandidate, c.Param("candidate_public_id"))
+}
+
+func (h *handler) CreateInterviewNote(c *gin.Context) {
+	h.createNote(c, models.NoteSubjectInterview, c.Param("interview_public_id"))
+}
+
+func (h *handler) GetCandidateNotes(c *gin.Context) {
+	h.getNotes(c, models.NoteSubjectCandidate, c.Param("candidate_public_id"))
+}
+
+func (h *handler) GetInterviewNotes(c *gin.Context) {
+	h.getNotes(c, models.NoteSubjectInterview, c.Param("interview_public_id"))
+}
+
+func (h *handler) createNote(c *gin.Context, subjectType, subjectID string) {
+	req := &noteReq{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.CreateNote(c.Request.Context(), publicID, &models.Note{
+		SubjectType:     subjectType,
+		SubjectPublicID: subjectID,
+		Body:            req.Body,
+		Rating:          req.Rating,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sendResponse(0, res, nil))
+}
+
+func (h *handler) getNotes(c *gin.Context, subjectType, subjectID string) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	pageNum, err := strconv.Atoi(c.Query("page_num"))
+	if err != nil || pageNum < 1 {
+		pageNum = models.DefaultPageNum
+	}
+	pageSize, err := strconv.Atoi(c.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = models.DefaultPageSize
+	}
+
+	searchArgs := &models.SearchArgs{
+		PageNum:  pageNum,
+		PageSize: pageSize,
+	}
+
+	res, count, err := h.service.GetNotes(c.Request.Context(), publicID, subjectType, subjectID, searchArgs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, sendResponse(0, GetNotesResult{
+		Notes: res,
+		Count: count,
+	}, nil))
+}
+
+func (h *handler) GetNoteMentions(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	pageNum, err := strconv.Atoi(c.Query("page_num"))
+	if err != nil || pageNum < 1 {
+		pageNum = models.DefaultPageNum
+	}
+	pageSize, err := strconv.Atoi(c.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = models.DefaultPageSize
+	}
+
+	searchArgs := &models.SearchArgs{
+		PageNum:  pageNum,
+		PageSize: pageSize,
+	}
+
+	res, count, err := h.service.GetMentions(c.Request.Context(), publicID, searchArgs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, sendResponse(0, GetNotesResult{
+		Notes: res,
+		Count: count,
+	}, nil))
+}
+
+func (h *handler) GetNote(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.GetNote(c.Request.Context(), publicID, c.Param("note_public_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
+
+func (h *handler) UpdateNote(c *gin.Context) {
+	req := &noteReq{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+	if req.Body == nil && req.Rating == nil {
+		c.Error(models.ErrInvalidInput.WithField("body", "body or rating is required"))
+		return
+	}
+
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.UpdateNote(c.Request.Context(), publicID, &models.Note{
+		PublicID: c.Param("note_public_id"),
+		Body:     req.Body,
+		Rating:   req.Rating,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
+
+func (h *handler) DeleteNote(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteNote(c.Request.Context(), publicID, c.Param("note_public_id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, nil, nil))
+}
+
+func (h *handler) GetNoteHistory(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.GetNoteHistory(c.Request.Context(), publicID, c.Param("note_public_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+type GetNotificationsResult struct {
+	Notifications []*models.Notification `json:"notifications"`
+	Count         int                    `json:"count"`
+}
+
+type notificationPreferencesReq struct {
+	Preferences []*models.NotificationPreference `json:"preferences"`
+}
+
+func (h *handler) GetNotifications(c *gin.Context) {
+	publicID := c.GetString("public_id")
+	pageNum, err := strconv.Atoi(c.Query("page_num"))
+	if err != nil || pageNum < 1 {
+		pageNum = models.DefaultPageNum
+	}
+	pageSize, err := strconv.Atoi(c.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = models.DefaultPageSize
+	}
+
+	searchArgs := &models.SearchArgs{
+		PageNum:  pageNum,
+		PageSize: pageSize,
+	}
+
+	res, count, err := h.service.GetNotifications(c.Request.Context(), publicID, searchArgs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, sendResponse(0, GetNotificationsResult{
+		Notifications: res,
+		Count:         count,
+	}, nil))
+}
+
+func (h *handler) GetNotificationPreferences(c *gin.Context) {
+	publicID := c.GetString("public_id")
+	res, err := h.service.GetNotificationPreferences(c.Request.Context(), publicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
+
+func (h *handler) UpdateNotificationPreferences(c *gin.Context) {
+	req := &notificationPreferencesReq{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+	if len(req.Preferences) == 0 {
+		c.Error(models.ErrInvalidInput.WithField("preferences", "must not be empty"))
+		return
+	}
+
+	publicID := c.GetString("public_id")
+	if err := h.service.UpdateNotificationPreferences(c.Request.Context(), publicID, req.Preferences); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := h.service.GetNotificationPreferences(c.Request.Context(), publicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+// GetOpenAPI serves the OpenAPI document of the service
+func (h *handler) GetOpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", h.spec.JSON())
+}
+
+// ValidateRequest rejects requests that do not match the OpenAPI document of their route
+func (h *handler) ValidateRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.FullPath() == "" {
+			c.Next()
+			return
+		}
+		if err := h.spec.ValidateRequest(c.Request.Context(), c.Request, c.FullPath(), c.Params); err != nil {
+			c.Error(models.ErrInvalidInput.Wrap(err).WithFields(openapi.FieldErrors(err)...))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ServiceAuth lets through the requests with the bearer token of an allowed service. Every
+// request is rejected when no token is configured.
+func (h *handler) ServiceAuth() gin.HandlerFunc {
+	var tokens []string
+	if h.cfg.Provisioning != nil {
+		tokens = h.cfg.Provisioning.Tokens
+	}
+	return func(c *gin.Context) {
+		if !allowedServiceToken(tokens, bearerToken(c)) {
+			c.Error(models.ErrInvalidServiceToken)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// allowedServiceToken compares the token with every allowed one, in constant time
+func allowedServiceToken(tokens []string, token string) bool {
+	allowed := false
+	for _, t := range tokens {
+		if t != "" && subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			allowed = true
+		}
+	}
+	return allowed
+}
+
+func (h *handler) ProvisionCandidate(c *gin.Context) {
+	req := &models.CandidateProvision{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+
+	res, err := h.service.ProvisioningService.ProvisionCandidate(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(provisionedStatus(res.Created), sendResponse(0, res, nil))
+}
+
+func (h *handler) ProvisionRecruiter(c *gin.Context) {
+	req := &models.RecruiterProvision{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+
+	res, err := h.service.ProvisioningService.ProvisionRecruiter(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(provisionedStatus(res.Created), sendResponse(0, res, nil))
+}
+
+func (h *handler) FindOrCreateCompany(c *gin.Context) {
+	company := &models.Company{}
+	if !h.bindJSON(c, company) {
+		return
+	}
+
+	res, created, err := h.service.ProvisioningService.FindOrCreateCompany(c.Request.Context(), company)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(provisionedStatus(created), sendResponse(0, res, nil))
+}
+
+// provisionedStatus is 201 for a new resource, and 200 for a retried request that found the existing one
+func provisionedStatus(created bool) int {
+	if created {
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit limits requests per client: authenticated requests per user, anonymous requests per IP.
+// Routes with their own limit are limited by that as well, per route and client.
+// The bucket closest to running out is reported in the RateLimit-Limit, RateLimit-Remaining
+// and RateLimit-Reset headers.
+func (h *handler) RateLimit() gin.HandlerFunc {
+	cfg := h.cfg.RateLimit
+	routes := make(map[string]ratelimit.Limit)
+	for _, route := range cfg.Routes {
+		if isLimited(&route.RateLimit) {
+			routes[route.Method+" "+route.Path] = ratelimit.Limit{Rate: route.Rate, Burst: route.Burst}
+		}
+	}
+
+	return func(c *gin.Context) {
+		client, limit := "", ratelimit.Limit{}
+		if publicID := h.tokenPublicID(c); publicID != "" {
+			client = "user:" + publicID
+			if isLimited(cfg.User) {
+				limit = ratelimit.Limit{Rate: cfg.User.Rate, Burst: cfg.User.Burst}
+			}
+		} else {
+			client = "ip:" + c.ClientIP()
+			if isLimited(cfg.IP) {
+				limit = ratelimit.Limit{Rate: cfg.IP.Rate, Burst: cfg.IP.Burst}
+			}
+		}
+
+		var tightest *ratelimit.Result
+		check := func(key string, limit ratelimit.Limit) bool {
+			res, err := h.limiter.Allow(key, limit)
+			if err != nil {
+				logging.FromContext(c.Request.Context(), h.logger).Errorf("failed to check rate limit: %v", err)
+				return true
+			}
+			if tightest == nil || !res.Allowed || (tightest.Allowed && res.Remaining < tightest.Remaining) {
+				tightest = res
+			}
+			return res.Allowed
+		}
+
+		allowed := true
+		if limit.Burst > 0 {
+			allowed = check(client, limit)
+		}
+		route := c.Request.Method + " " + c.FullPath()
+		if routeLimit, ok := routes[route]; ok && allowed {
+			allowed = check("route:"+route+":"+client, routeLimit)
+		}
+
+		if tightest != nil {
+			c.Header("RateLimit-Limit", strconv.Itoa(tightest.Limit))
+			c.Header("RateLimit-Remaining", strconv.Itoa(tightest.Remaining))
+			c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(tightest.Reset)))
+		}
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(tightest.RetryAfter)))
+			c.Error(models.ErrRateLimited)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// tokenPublicID returns the public ID of the user from a valid access token, or an empty string.
+// The route itself still verifies the token and its revocation; an invalid token is only limited as anonymous.
+func (h *handler) tokenPublicID(c *gin.Context) string {
+	tokenString := accessToken(c)
+	if tokenString == "" {
+		return ""
+	}
+	claims, err := h.verifier.Parse(tokenString)
+	if err != nil {
+		return ""
+	}
+	return claims.PublicID
+}
+
+// isLimited reports whether the limit is configured. A missing rate or burst disables it.
+func isLimited(limit *config.RateLimit) bool {
+	return limit != nil && limit.Rate > 0 && limit.Burst > 0
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"errors"
 	"net/http"
 	"strconv"
 
@@ -11,29 +10,21 @@ import (
 
 func (h *handler) GetRecruiter(c *gin.Context) {
 	publicID := c.Param("recruiter_public_id")
-	if err := h.service.RecruiterService.Exists(publicID); err != nil {
-		if errors.Is(err, models.ErrPermissionDenied) {
-			c.JSON(http.StatusNotFound, sendResponse(-1, nil, models.ErrUserNotFound))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+	if err := h.service.RecruiterService.Exists(c.Request.Context(), publicID); err != nil {
+		c.Error(notFound(err, models.ErrUserNotFound))
 		return
 	}
-	res, err := h.service.RecruiterService.GetRecruiter(publicID)
+	res, err := h.service.RecruiterService.GetRecruiter(c.Request.Context(), publicID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, sendResponse(0, res, nil))
 }
 func (h *handler) GetRecruiterInterviewsByID(c *gin.Context) {
 	publicID := c.Param("recruiter_public_id")
-	if err := h.service.RecruiterService.Exists(publicID); err != nil {
-		if errors.Is(err, models.ErrPermissionDenied) {
-			c.JSON(http.StatusNotFound, sendResponse(-1, nil, models.ErrUserNotFound))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+	if err := h.service.RecruiterService.Exists(c.Request.Context(), publicID); err != nil {
+		c.Error(notFound(err, models.ErrUserNotFound))
 		return
 	}
 	pageNum, err := strconv.Atoi(c.Query("page_num"))
@@ -51,9 +42,9 @@ func (h *handler) GetRecruiterInterviewsByID(c *gin.Context) {
 		Search:   c.Query("search"),
 	}
 
-	res, count, err := h.service.RecruiterService.GetInterviewsByPublicID(publicID, searchArgs)
+	res, count, err := h.service.RecruiterService.GetInterviewsByPublicID(c.Request.Context(), publicID, searchArgs)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, sendResponse(0, InterviewResponse{
@@ -64,12 +55,8 @@ func (h *handler) GetRecruiterInterviewsByID(c *gin.Context) {
 
 func (h *handler) GetRecrutierInterviews(c *gin.Context) {
 	publicID := c.Param("recruiter_public_id")
-	if err := h.service.RecruiterService.Exists(publicID); err != nil {
-		if errors.Is(err, models.ErrPermissionDenied) {
-			c.JSON(http.StatusNotFound, sendResponse(-1, nil, models.ErrUserNotFound))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+	if err := h.service.RecruiterService.Exists(c.Request.Context(), publicID); err != nil {
+		c.Error(notFound(err, models.ErrUserNotFound))
 		return
 	}
 	pageNum, err := strconv.Atoi(c.Query("page_num"))
@@ -87,9 +74,9 @@ func (h *handler) GetRecrutierInterviews(c *gin.Context) {
 		Search:   c.Query("search"),
 	}
 
-	res, count, err := h.service.RecruiterService.GetInterviewsByPublicID(publicID, searchArgs)
+	res, count, err := h.service.RecruiterService.GetInterviewsByPublicID(c.Request.Context(), publicID, searchArgs)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, sendResponse(0, InterviewResponse{
@@ -99,13 +86,8 @@ func (h *handler) GetRecrutierInterviews(c *gin.Context) {
 }
 
 func (h *handler) GetRecruiterInterviews(c *gin.Context) {
-	publicID := c.GetString("public_id")
-	if err := h.service.RecruiterService.Exists(publicID); err != nil {
-		if errors.Is(err, models.ErrPermissionDenied) {
-			c.JSON(http.StatusNotFound, sendResponse(-1, nil, models.ErrUserNotFound))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
 		return
 	}
 	pageNum, err := strconv.Atoi(c.Query("page_num"))
@@ -123,9 +105,9 @@ func (h *handler) GetRecruiterInterviews(c *gin.Context) {
 		Search:   c.Query("search"),
 	}
 
-	res, count, err := h.service.RecruiterService.GetInterviewsByPublicID(publicID, searchArgs)
+	res, count, err := h.service.RecruiterService.GetInterviewsByPublicID(c.Request.Context(), publicID, searchArgs)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, sendResponse(-1, nil, models.ErrInternalServer))
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, sendResponse(0, InterviewResponse{
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/auth"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/health"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/openapi"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/ratelimit"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/repository"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TestRoutesInSpec fails when a route is added without documenting it in the OpenAPI document,
+// or the document describes an operation no route serves.
+func TestRoutesInSpec(t *testing.T) {
+	h := newTestHandler(t, nil)
+	if err := h.spec.CheckRoutes(h.InitRoutes().Routes()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTestHandler returns a handler over the memory storage, configured by the test profile with the overrides
+func newTestHandler(t *testing.T, overrides map[string]interface{}) *handler {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	settings := map[string]interface{}{"app.storage": config.StorageMemory}
+	for key, value := range overrides {
+		settings[key] = value
+	}
+	cfg, err := config.New("../../../config/config.yaml", config.ProfileTest, settings)
+	if err != nil {
+		t.Fatalf("loading config: %v", err)
+	}
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("loading openapi document: %v", err)
+	}
+
+	logger := zap.NewNop().Sugar()
+	services := service.New(repository.NewMemory(repository.NewMemoryStore()), logger, cfg)
+	verifier := auth.NewVerifier(cfg.Token.TokenSecret, nil, nil, false, logger)
+	return New(services, logger, cfg, ratelimit.NewMemoryLimiter(), spec, health.NewChecker(cfg.Health.CheckTimeout), verifier).(*handler)
+}
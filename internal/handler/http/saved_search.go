@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+type GetSavedSearchesResult struct {
+	SavedSearches []*models.SavedSearch `json:"saved_searches"`
+	Count         int                   `json:"count"`
+}
+
+type GetSavedSearchAlertsResult struct {
+	Alerts []*models.SavedSearchAlert `json:"alerts"`
+	Count  int                        `json:"count"`
+}
+
+type alertsSeenReq struct {
+	AlertPublicIDs []string `json:"alert_public_ids"`
+}
+
+func (h *handler) CreateSavedSearch(c *gin.Context) {
+	req := &models.SavedSearch{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.CreateSavedSearch(c.Request.Context(), publicID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sendResponse(0, res, nil))
+}
+
+func (h *handler) GetSavedSearches(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	pageNum, err := strconv.Atoi(c.Query("page_num"))
+	if err != nil || pageNum < 1 {
+		pageNum = models.DefaultPageNum
+	}
+	pageSize, err := strconv.Atoi(c.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = models.DefaultPageSize
+	}
+
+	searchArgs := &models.SearchArgs{
+		PageNum:  pageNum,
+		PageSize: pageSize,
+		Search:   c.Query("search"),
+	}
+
+	res, count, err := h.service.GetSavedSearches(c.Request.Context(), publicID, searchArgs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, sendResponse(0, GetSavedSearchesResult{
+		SavedSearches: res,
+		Count:         count,
+	}, nil))
+}
+
+func (h *handler) GetSavedSearch(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.GetSavedSearch(c.Request.Context(), publicID, c.Param("saved_search_public_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
+
+func (h *handler) DeleteSavedSearch(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteSavedSearch(c.Request.Context(), publicID, c.Param("saved_search_public_id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, nil, nil))
+}
+
+// RunSavedSearch runs the saved search again. page_num and page_size override the saved paging when given.
+func (h *handler) RunSavedSearch(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	pageNum, _ := strconv.Atoi(c.Query("page_num"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size"))
+
+	res, count, err := h.service.RunSavedSearch(c.Request.Context(), publicID, c.Param("saved_search_public_id"), &models.SearchArgs{
+		PageNum:  pageNum,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, sendResponse(0, GetCandidatesResult{
+		Candidates: res,
+		Count:      count,
+	}, nil))
+}
+
+// GetSavedSearchAlerts returns the recruiter's new-match alerts. unseen=true returns only the alerts not yet marked as seen.
+func (h *handler) GetSavedSearchAlerts(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	pageNum, err := strconv.Atoi(c.Query("page_num"))
+	if err != nil || pageNum < 1 {
+		pageNum = models.DefaultPageNum
+	}
+	pageSize, err := strconv.Atoi(c.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = models.DefaultPageSize
+	}
+	unseenOnly, _ := strconv.ParseBool(c.Query("unseen"))
+
+	searchArgs := &models.SearchArgs{
+		PageNum:  pageNum,
+		PageSize: pageSize,
+	}
+
+	res, count, err := h.service.GetSavedSearchAlerts(c.Request.Context(), publicID, unseenOnly, searchArgs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, sendResponse(0, GetSavedSearchAlertsResult{
+		Alerts: res,
+		Count:  count,
+	}, nil))
+}
+
+// MarkSavedSearchAlertsSeen marks the given alerts as seen. An empty list marks every alert of the recruiter as seen.
+func (h *handler) MarkSavedSearchAlertsSeen(c *gin.Context) {
+	req := &alertsSeenReq{}
+	if c.Request.ContentLength != 0 {
+		if !h.bindJSON(c, req) {
+			return
+		}
+	}
+
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	if err := h.service.MarkSavedSearchAlertsSeen(c.Request.Context(), publicID, req.AlertPublicIDs); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, nil, nil))
+}
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/openapi"
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// accessTokenCookie is the cookie the auth service stores the access token in
+const accessTokenCookie = "access_token"
+
+// swaggerUIPolicy allows the inline script and styles of the Swagger UI page
+const swaggerUIPolicy = "default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'none'"
+
+const csrfTokenKey = "csrf_token"
+
+// CORS lets the configured web apps call the API from the browser, with the access_token cookie
+// if credentials are allowed. It returns nil when no origin is configured.
+func (h *handler) CORS() gin.HandlerFunc {
+	cfg := h.cfg.CORS
+	if len(cfg.AllowedOrigins) == 0 {
+		return nil
+	}
+	return cors.New(cors.Config{
+		AllowOriginFunc:  h.allowedOrigin,
+		AllowMethods:     []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", requestIDHeader, h.cfg.CSRF.HeaderName},
+		ExposeHeaders:    []string{requestIDHeader, "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"},
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	})
+}
+
+// allowedOrigin reports whether origin is one of the configured origins. A wildcard matches
+// any subdomain, but not the domain itself.
+func (h *handler) allowedOrigin(origin string) bool {
+	for _, allowed := range h.cfg.CORS.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+		prefix, suffix, ok := strings.Cut(strings.ToLower(allowed), "*")
+		lower := strings.ToLower(origin)
+		if !ok || len(lower) <= len(prefix)+len(suffix) || !strings.HasPrefix(lower, prefix) || !strings.HasSuffix(lower, suffix) {
+			continue
+		}
+		if subdomain := lower[len(prefix) : len(lower)-len(suffix)]; !strings.ContainsAny(subdomain, "/:@") {
+			return true
+		}
+	}
+	return false
+}
+
+// SecurityHeaders tells browsers to only use HTTPS, to not guess content types, and to not
+// render the responses in frames or load anything from them.
+func (h *handler) SecurityHeaders() gin.HandlerFunc {
+	cfg := h.cfg.SecurityHeaders
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(int(cfg.HSTSMaxAge.Seconds()))
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		if hsts != "" {
+			header.Set("Strict-Transport-Security", hsts)
+		}
+		header.Set("X-Content-Type-Options", "nosniff")
+		if cfg.FrameOptions != "" {
+			header.Set("X-Frame-Options", cfg.FrameOptions)
+		}
+		if cfg.ReferrerPolicy != "" {
+			header.Set("Referrer-Policy", cfg.ReferrerPolicy)
+		}
+		if strings.HasPrefix(c.Request.URL.Path, openapi.SwaggerUIPath+"/") {
+			header.Set("Content-Security-Policy", swaggerUIPolicy)
+		} else if cfg.ContentSecurityPolicy != "" {
+			header.Set("Content-Security-Policy", cfg.ContentSecurityPolicy)
+		}
+		c.Next()
+	}
+}
+
+// CSRF protects the requests authenticated with the access_token cookie, which the browser sends
+// on its own, from being made by other sites. Every client gets a random token in a cookie, and
+// state changing requests with the access_token cookie must send the same token in a header,
+// which only pages of the allowed origins can read. Their Origin header, if any, must be allowed as well.
+// Requests with a bearer token are authenticated with it rather than the cookie, and other sites cannot
+// send one, so they are not checked.
+func (h *handler) CSRF() gin.HandlerFunc {
+	cfg := h.cfg.CSRF
+	return func(c *gin.Context) {
+		cookieToken, _ := c.Cookie(cfg.CookieName)
+		token := cookieToken
+		if !validCSRFToken(token) {
+			var err error
+			token, err = newCSRFToken()
+			if err != nil {
+				c.Error(err)
+				c.Abort()
+				return
+			}
+			h.setCSRFCookie(c, token)
+		}
+		c.Set(csrfTokenKey, token)
+
+		if isSafeMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if _, err := c.Cookie(accessTokenCookie); err != nil || bearerToken(c) != "" {
+			c.Next()
+			return
+		}
+		if origin := c.GetHeader("Origin"); origin != "" && !sameOrigin(c.Request, origin) && !h.allowedOrigin(origin) {
+			c.Error(models.ErrCSRFTokenInvalid.WithField("Origin", "is not allowed"))
+			c.Abort()
+			return
+		}
+		headerToken := c.GetHeader(cfg.HeaderName)
+		if !validCSRFToken(cookieToken) || subtle.ConstantTimeCompare([]byte(headerToken), []byte(cookieToken)) != 1 {
+			c.Error(models.ErrCSRFTokenInvalid.WithField(cfg.HeaderName, "must match the "+cfg.CookieName+" cookie"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// GetCSRFToken returns the CSRF token of the client, for web apps on another origin that cannot read the cookie
+func (h *handler) GetCSRFToken(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, sendResponse(0, gin.H{
+		"token":  c.GetString(csrfTokenKey),
+		"header": h.cfg.CSRF.HeaderName,
+	}, nil))
+}
+
+func (h *handler) setCSRFCookie(c *gin.Context, token string) {
+	cfg := h.cfg.CSRF
+	sameSite := http.SameSiteLaxMode
+	switch cfg.CookieSameSite {
+	case "strict":
+		sameSite = http.SameSiteStrictMode
+	case "none":
+		sameSite = http.SameSiteNoneMode
+	}
+	// readable by scripts on purpose, the pages of the service send it back in the header
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     cfg.CookieName,
+		Value:    token,
+		Path:     "/",
+		Domain:   cfg.CookieDomain,
+		Secure:   cfg.CookieSecure,
+		HttpOnly: false,
+		SameSite: sameSite,
+	})
+}
+
+const csrfTokenBytes = 32
+
+func newCSRFToken() (string, error) {
+	b := make([]byte, csrfTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate csrf token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func validCSRFToken(token string) bool {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	return err == nil && len(b) == csrfTokenBytes
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// sameOrigin reports whether origin is the host the request was sent to
+func sameOrigin(r *http.Request, origin string) bool {
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+type GetShortlistsResult struct {
+	Shortlists []*models.Shortlist `json:"shortlists"`
+	Count      int                 `json:"count"`
+}
+
+type GetShortlistCandidatesResult struct {
+	Candidates []*models.ShortlistCandidate `json:"candidates"`
+	Count      int                          `json:"count"`
+}
+
+type ExportShortlistResult struct {
+	Shortlist  *models.Shortlist            `json:"shortlist"`
+	Candidates []*models.ShortlistCandidate `json:"candidates"`
+}
+
+type shortlistCandidateReq struct {
+	CandidatePublicID string `json:"candidate_public_id"`
+	Note              string `json:"note"`
+}
+
+func (h *handler) CreateShortlist(c *gin.Context) {
+	req := &models.Shortlist{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+	if req.Name == nil || strings.TrimSpace(*req.Name) == "" {
+		c.Error(models.ErrInvalidInput.WithField("name", "is required"))
+		return
+	}
+
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.CreateShortlist(c.Request.Context(), publicID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sendResponse(0, res, nil))
+}
+
+func (h *handler) GetShortlists(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	pageNum, err := strconv.Atoi(c.Query("page_num"))
+	if err != nil || pageNum < 1 {
+		pageNum = models.DefaultPageNum
+	}
+	pageSize, err := strconv.Atoi(c.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = models.DefaultPageSize
+	}
+
+	searchArgs := &models.SearchArgs{
+		PageNum:  pageNum,
+		PageSize: pageSize,
+		Search:   c.Query("search"),
+	}
+
+	res, count, err := h.service.GetShortlists(c.Request.Context(), publicID, searchArgs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, sendResponse(0, GetShortlistsResult{
+		Shortlists: res,
+		Count:      count,
+	}, nil))
+}
+
+func (h *handler) GetShortlist(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.GetShortlist(c.Request.Context(), publicID, c.Param("shortlist_public_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
+
+func (h *handler) UpdateShortlist(c *gin.Context) {
+	req := &models.Shortlist{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+	if req.Name != nil && strings.TrimSpace(*req.Name) == "" {
+		c.Error(models.ErrInvalidInput.WithField("name", "must not be blank"))
+		return
+	}
+	req.PublicID = c.Param("shortlist_public_id")
+
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.UpdateShortlist(c.Request.Context(), publicID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
+
+func (h *handler) DeleteShortlist(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteShortlist(c.Request.Context(), publicID, c.Param("shortlist_public_id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, nil, nil))
+}
+
+func (h *handler) GetShortlistCandidates(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	pageNum, err := strconv.Atoi(c.Query("page_num"))
+	if err != nil || pageNum < 1 {
+		pageNum = models.DefaultPageNum
+	}
+	pageSize, err := strconv.Atoi(c.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = models.DefaultPageSize
+	}
+
+	searchArgs := &models.SearchArgs{
+		PageNum:  pageNum,
+		PageSize: pageSize,
+		Search:   c.Query("search"),
+	}
+
+	res, count, err := h.service.GetShortlistCandidates(c.Request.Context(), publicID, c.Param("shortlist_public_id"), searchArgs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, sendResponse(0, GetShortlistCandidatesResult{
+		Candidates: res,
+		Count:      count,
+	}, nil))
+}
+
+func (h *handler) AddCandidateToShortlist(c *gin.Context) {
+	req := &shortlistCandidateReq{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+	if req.CandidatePublicID == "" {
+		c.Error(models.ErrInvalidInput.WithField("candidate_public_id", "is required"))
+		return
+	}
+
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	err := h.service.AddCandidateToShortlist(c.Request.Context(), publicID, c.Param("shortlist_public_id"), req.CandidatePublicID, req.Note)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sendResponse(0, nil, nil))
+}
+
+func (h *handler) RemoveCandidateFromShortlist(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	err := h.service.RemoveCandidateFromShortlist(c.Request.Context(), publicID, c.Param("shortlist_public_id"), c.Param("candidate_public_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, nil, nil))
+}
+
+// ExportShortlist exports every candidate on the shortlist as CSV (default) or JSON when format=json
+func (h *handler) ExportShortlist(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	shortlist, res, err := h.service.ExportShortlist(c.Request.Context(), publicID, c.Param("shortlist_public_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if c.Query("format") == "json" {
+		c.JSON(http.StatusOK, sendResponse(0, ExportShortlistResult{
+			Shortlist:  shortlist,
+			Candidates: res,
+		}, nil))
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=shortlist-%s.csv", shortlist.PublicID))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"public_id", "first_name", "last_name", "current_position", "education", "skills", "note", "added_by", "added_at"})
+	for _, entry := range res {
+		candidate := entry.Candidate
+		skills := make([]string, 0, len(candidate.Skills))
+		for _, skill := range candidate.Skills {
+			if skill != nil {
+				skills = append(skills, *skill)
+			}
+		}
+		w.Write([]string{
+			stringValue(candidate.PublicID),
+			stringValue(candidate.FirstName),
+			stringValue(candidate.LastName),
+			stringValue(candidate.CurrentPosition),
+			stringValue(candidate.Education),
+			strings.Join(skills, ";"),
+			entry.Note,
+			entry.AddedBy,
+			entry.AddedAt.Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		logging.FromContext(c.Request.Context(), h.logger).Errorf("failed to write shortlist export. %s\n", err.Error())
+	}
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
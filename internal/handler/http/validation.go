@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// personNameRe allows letters in any script, spaces, hyphens, apostrophes and periods
+var personNameRe = regexp.MustCompile(`^[\p{L}\p{M}' .-]*$`)
+
+// registerValidations adds the custom rules used in binding tags to the validator of gin
+// and makes validation errors report fields by their JSON names
+func registerValidations() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	v.RegisterValidation("personname", func(fl validator.FieldLevel) bool {
+		return personNameRe.MatchString(fl.Field().String())
+	})
+	v.RegisterValidation("notblank", func(fl validator.FieldLevel) bool {
+		return strings.TrimSpace(fl.Field().String()) != ""
+	})
+	// omitempty only skips nil pointers, so an empty string sent to clear an optional URL is allowed explicitly
+	v.RegisterAlias("optionalurl", "eq=|url")
+}
+
+// validationFields describes every failed rule of a validation error
+func validationFields(errs validator.ValidationErrors) []models.FieldError {
+	fields := make([]models.FieldError, 0, len(errs))
+	for _, fe := range errs {
+		fields = append(fields, models.FieldError{
+			Field:   fe.Field(),
+			Message: validationMessage(fe),
+		})
+	}
+	return fields
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "notblank":
+		return "must not be blank"
+	case "max":
+		return fmt.Sprintf("must be at most %s characters long", fe.Param())
+	case "url", "optionalurl":
+		return "must be a valid URL"
+	case "personname":
+		return "may only contain letters, spaces, hyphens, apostrophes and periods"
+	default:
+		return fmt.Sprintf("failed the %s rule", fe.Tag())
+	}
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+type GetWebhookDeliveriesResult struct {
+	Deliveries []*models.WebhookDelivery `json:"deliveries"`
+	Count      int                       `json:"count"`
+}
+
+type webhookReq struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+	Active     *bool    `json:"active"`
+}
+
+func (h *handler) CreateWebhook(c *gin.Context) {
+	req := &webhookReq{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.CreateWebhook(c.Request.Context(), publicID, c.Param("public_id"), &models.Webhook{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+		Active:     req.Active,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sendResponse(0, res, nil))
+}
+
+func (h *handler) GetWebhooks(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.GetWebhooks(c.Request.Context(), publicID, c.Param("public_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
+
+func (h *handler) GetWebhook(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.GetWebhook(c.Request.Context(), publicID, c.Param("public_id"), c.Param("webhook_public_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
+
+func (h *handler) UpdateWebhook(c *gin.Context) {
+	req := &webhookReq{}
+	if !h.bindJSON(c, req) {
+		return
+	}
+
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.UpdateWebhook(c.Request.Context(), publicID, c.Param("public_id"), &models.Webhook{
+		PublicID:   c.Param("webhook_public_id"),
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+		Active:     req.Active,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, res, nil))
+}
+
+func (h *handler) DeleteWebhook(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteWebhook(c.Request.Context(), publicID, c.Param("public_id"), c.Param("webhook_public_id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse(0, nil, nil))
+}
+
+func (h *handler) GetWebhookDeliveries(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	pageNum, err := strconv.Atoi(c.Query("page_num"))
+	if err != nil || pageNum < 1 {
+		pageNum = models.DefaultPageNum
+	}
+	pageSize, err := strconv.Atoi(c.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = models.DefaultPageSize
+	}
+
+	searchArgs := &models.SearchArgs{
+		PageNum:  pageNum,
+		PageSize: pageSize,
+	}
+
+	res, count, err := h.service.GetWebhookDeliveries(c.Request.Context(), publicID, c.Param("public_id"), c.Param("webhook_public_id"), c.Query("status"), searchArgs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, sendResponse(0, GetWebhookDeliveriesResult{
+		Deliveries: res,
+		Count:      count,
+	}, nil))
+}
+
+func (h *handler) ReplayWebhookDelivery(c *gin.Context) {
+	publicID, ok := h.signedInRecruiter(c)
+	if !ok {
+		return
+	}
+	res, err := h.service.ReplayWebhookDelivery(c.Request.Context(), publicID, c.Param("public_id"), c.Param("webhook_public_id"), c.Param("delivery_public_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, sendResponse(0, res, nil))
+}
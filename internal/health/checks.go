@@ -0,0 +1,50 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Postgres checks that a connection can be acquired from the pool and answers
+func Postgres(pool *pgxpool.Pool) Check {
+	return func(ctx context.Context) error {
+		return pool.Ping(ctx)
+	}
+}
+
+// Redis checks that the Redis server answers
+func Redis(client *redis.Client) Check {
+	return func(ctx context.Context) error {
+		return client.WithContext(ctx).Ping().Err()
+	}
+}
+
+// Schema checks that the tables were created by the schema script
+func Schema(pool *pgxpool.Pool, tables []string) Check {
+	return func(ctx context.Context) error {
+		rows, err := pool.Query(ctx, `SELECT t FROM unnest($1::text[]) AS t WHERE to_regclass(t) IS NULL`, tables)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		var missing []string
+		for rows.Next() {
+			var table string
+			if err := rows.Scan(&table); err != nil {
+				return err
+			}
+			missing = append(missing, table)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("missing tables: %s", strings.Join(missing, ", "))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,134 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK = "ok"
+	// StatusDegraded means an optional dependency is failing. The service still serves requests.
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+	StatusShutdown    = "shutting_down"
+)
+
+const defaultTimeout = 2 * time.Second
+
+// Check returns an error if the dependency it checks cannot be used
+type Check func(ctx context.Context) error
+
+type check struct {
+	name     string
+	required bool
+	run      Check
+}
+
+// Report is the result of running every check
+type Report struct {
+	Status string                  `json:"status"`
+	Checks map[string]*CheckResult `json:"checks"`
+}
+
+// CheckResult is the result of a single check
+type CheckResult struct {
+	Status    string  `json:"status"`
+	Required  bool    `json:"required"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Checker runs the checks of the dependencies of the service and tracks whether it is shutting down.
+type Checker struct {
+	timeout      time.Duration
+	checks       []check
+	shuttingDown atomic.Bool
+}
+
+// NewChecker creates a checker that gives every check timeout to finish
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Checker{timeout: timeout}
+}
+
+// Require adds a check of a dependency the service cannot serve requests without
+func (c *Checker) Require(name string, run Check) {
+	c.checks = append(c.checks, check{name: name, required: true, run: run})
+}
+
+// Optional adds a check of a dependency the service can work without, such as the cache
+func (c *Checker) Optional(name string, run Check) {
+	c.checks = append(c.checks, check{name: name, run: run})
+}
+
+// Shutdown makes the service report it is not ready, so no new traffic is sent to it
+func (c *Checker) Shutdown() {
+	c.shuttingDown.Store(true)
+}
+
+// ShuttingDown reports whether Shutdown was called
+func (c *Checker) ShuttingDown() bool {
+	return c.shuttingDown.Load()
+}
+
+// Run runs all checks concurrently. The service is unavailable if a required check fails
+// or it is shutting down, and degraded if only optional checks fail.
+func (c *Checker) Run(ctx context.Context) *Report {
+	report := &Report{
+		Status: StatusOK,
+		Checks: make(map[string]*CheckResult, len(c.checks)),
+	}
+	results := make([]*CheckResult, len(c.checks))
+	var wg sync.WaitGroup
+	for i, ch := range c.checks {
+		wg.Add(1)
+		go func(i int, ch check) {
+			defer wg.Done()
+			results[i] = c.run(ctx, ch)
+		}(i, ch)
+	}
+	wg.Wait()
+
+	for i, ch := range c.checks {
+		res := results[i]
+		report.Checks[ch.name] = res
+		if res.Status == StatusOK {
+			continue
+		}
+		if ch.required {
+			report.Status = StatusUnavailable
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+	if c.ShuttingDown() {
+		report.Status = StatusShutdown
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, ch check) *CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	start := time.Now()
+	err := ch.run(ctx)
+	res := &CheckResult{
+		Status:    StatusOK,
+		Required:  ch.required,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		res.Status = StatusUnavailable
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// Ready reports whether the service can take traffic
+func (r *Report) Ready() bool {
+	return r.Status == StatusOK || r.Status == StatusDegraded
+}
@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request it serves
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the ID of the request served with ctx, or an empty string
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// FromContext returns log with the request ID and trace ID of ctx added to every line,
+// so the lines logged while serving a request can be found together.
+func FromContext(ctx context.Context, log *zap.SugaredLogger) *zap.SugaredLogger {
+	var fields []interface{}
+	if id := RequestID(ctx); id != "" {
+		fields = append(fields, "request_id", id)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields = append(fields, "trace_id", sc.TraceID().String())
+	}
+	if len(fields) == 0 {
+		return log
+	}
+	return log.With(fields...)
+}
@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"fmt"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// FormatJSON writes one JSON object per line, for log collectors
+	FormatJSON = "json"
+	// FormatConsole writes colored, human readable lines, for local development
+	FormatConsole = "console"
+)
+
+// New creates the logger configured by cfg. It logs JSON at info level when cfg is nil.
+// Credentials and personal data are redacted from every entry, see Redact.
+func New(cfg *config.LogConf) (*zap.Logger, error) {
+	format, level, redactKeys := FormatJSON, "info", []string(nil)
+	if cfg != nil {
+		if cfg.Format != "" {
+			format = cfg.Format
+		}
+		if cfg.Level != "" {
+			level = cfg.Level
+		}
+		redactKeys = cfg.RedactKeys
+	}
+
+	var zapCfg zap.Config
+	switch format {
+	case FormatJSON:
+		zapCfg = zap.NewProductionConfig()
+		zapCfg.EncoderConfig.TimeKey = "time"
+		zapCfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	case FormatConsole:
+		zapCfg = zap.NewDevelopmentConfig()
+		zapCfg.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+	lvl, err := zap.ParseAtomicLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	zapCfg.Level = lvl
+
+	return zapCfg.Build(
+		zap.AddStacktrace(zap.PanicLevel),
+		zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return newRedactingCore(core, redactKeys)
+		}),
+	)
+}
@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "users_service"
+
+var (
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of HTTP requests by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of repository calls by repository, method and outcome.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"repository", "method", "outcome"})
+
+	// TransactionRetries counts transactions run again after a serialization failure or a deadlock
+	TransactionRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "transaction_retries_total",
+		Help:      "Number of transactions retried, by the reason they failed.",
+	}, []string{"reason"})
+
+	// ProfileUpdates counts candidate profile updates
+	ProfileUpdates = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "profile_updates_total",
+		Help:      "Number of candidate profile updates.",
+	})
+
+	// ProfileDeletions counts deleted candidate profiles
+	ProfileDeletions = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "profile_deletions_total",
+		Help:      "Number of deleted candidate profiles.",
+	})
+
+	// SkillsAdded counts skills added to candidates
+	SkillsAdded = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "skills_added_total",
+		Help:      "Number of skills added to candidates.",
+	})
+
+	// SkillsRemoved counts skills removed from candidates
+	SkillsRemoved = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "skills_removed_total",
+		Help:      "Number of skills removed from candidates.",
+	})
+
+	// CompaniesCreated counts created companies
+	CompaniesCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "companies_created_total",
+		Help:      "Number of created companies.",
+	})
+
+	// InterviewsServed counts interview results returned to candidates and recruiters
+	InterviewsServed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "interviews_served_total",
+		Help:      "Number of interview results returned, by the role of the user they were listed for.",
+	}, []string{"role"})
+
+	// UsersProvisioned counts the profiles created for users signing up, by role
+	UsersProvisioned = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "users_provisioned_total",
+		Help:      "Number of profiles created for users signing up, by role.",
+	}, []string{"role"})
+
+	// TokensRejected counts rejected access tokens, by reason: invalid or revoked
+	TokensRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "access_tokens_rejected_total",
+		Help:      "Number of rejected access tokens, by reason.",
+	}, []string{"reason"})
+
+	// InvitationsSent counts the invitations to join a company sent by email
+	InvitationsSent = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "company_invitations_sent_total",
+		Help:      "Number of invitations to join a company sent.",
+	})
+
+	// MembershipChanges counts recruiters joining, leaving or changing role in a company, by change
+	MembershipChanges = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "company_membership_changes_total",
+		Help:      "Number of recruiters joining, leaving or changing role in a company, by change.",
+	}, []string{"change"})
+
+	// DomainVerifications counts the attempts of companies to verify an email domain, by method and result
+	DomainVerifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "company_domain_verifications_total",
+		Help:      "Number of attempts to verify a company email domain, by method and result.",
+	}, []string{"method", "result"})
+)
+
+// ObserveRequest records a served HTTP request. route is the route pattern, not the request path,
+// so the number of series stays bounded.
+func ObserveRequest(method, route, status string, duration time.Duration) {
+	httpRequestDuration.WithLabelValues(method, route, status).Observe(duration.Seconds())
+	httpRequests.WithLabelValues(method, route, status).Inc()
+}
+
+// ObserveQuery records a repository call that started at start
+func ObserveQuery(repository, method string, start time.Time, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	queryDuration.WithLabelValues(repository, method, outcome).Observe(time.Since(start).Seconds())
+}
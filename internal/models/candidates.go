@@ -2,15 +2,15 @@ package models
 
 type Candidate struct {
 	PublicID        *string     `json:"public_id"`
-	FirstName       *string     `json:"first_name"`
-	LastName        *string     `json:"last_name"`
-	CurrentPosition *string     `json:"current_position"`
-	Resume          *string     `json:"resume"`
-	Bio             *string     `json:"bio"`
+	FirstName       *string     `json:"first_name" binding:"omitempty,max=50,personname"`
+	LastName        *string     `json:"last_name" binding:"omitempty,max=50,personname"`
+	CurrentPosition *string     `json:"current_position" binding:"omitempty,max=50"`
+	Resume          *string     `json:"resume" binding:"omitempty,max=50"`
+	Bio             *string     `json:"bio" binding:"omitempty,max=50"`
 	Skills          []*string   `json:"skills"`
-	Photo           *string     `json:"photo"`
+	Photo           *string     `json:"photo" binding:"omitempty,max=50,optionalurl"`
 	Interviews      []Interview `json:"interviews,omitempty"`
-	Education       *string     `json:"education"`
+	Education       *string     `json:"education" binding:"omitempty,max=50"`
 }
 
 type Interview struct {
@@ -18,3 +18,16 @@ type Interview struct {
 	PublicID string                 `json:"public_id"`
 	Results  map[string]interface{} `json:"results"`
 }
+
+// SkillsChange is the outcome of a change of the skills of a candidate: the skills the candidate
+// has afterwards, and the ones the change added and removed.
+type SkillsChange struct {
+	Skills  []string `json:"skills"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// Changed reports whether any skill was added or removed
+func (c *SkillsChange) Changed() bool {
+	return len(c.Added) > 0 || len(c.Removed) > 0
+}
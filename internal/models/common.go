@@ -8,7 +8,18 @@ const (
 )
 
 type SearchArgs struct {
-	Search   string
-	PageNum  int
-	PageSize int
+	Search   string `json:"search"`
+	PageNum  int    `json:"page_num"`
+	PageSize int    `json:"page_size"`
+	// Filters narrow down candidate searches, other searches ignore them
+	Filters *CandidateFilters `json:"filters,omitempty"`
+}
+
+// CandidateFilters are the filters of a candidate search. Empty filters match every candidate.
+type CandidateFilters struct {
+	// Skills the candidate must all have, regardless of case
+	Skills []string `json:"skills,omitempty"`
+	// CurrentPosition and Education must be contained in the candidate's, regardless of case
+	CurrentPosition string `json:"current_position,omitempty"`
+	Education       string `json:"education,omitempty"`
 }
@@ -3,7 +3,9 @@ package models
 type Company struct {
 	ID          int    `json:"-"`
 	PublicID    string `json:"public_id"`
-	Name        string `json:"name"`
-	Logo        string `json:"logo"`
-	Description string `json:"description"`
+	Name        string `json:"name" binding:"required,notblank"`
+	Logo        string `json:"logo" binding:"omitempty,max=50,url"`
+	Description string `json:"description" binding:"max=50"`
+	// Verified tells the company proved it owns one of its email domains
+	Verified bool `json:"verified"`
 }
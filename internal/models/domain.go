@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+const (
+	// DomainMethodDNS proves the ownership of a domain with a TXT record published by the company,
+	// DomainMethodEmail with a code sent to an address on the domain.
+	DomainMethodDNS   = "dns"
+	DomainMethodEmail = "email"
+
+	DomainPending  = "pending"
+	DomainVerified = "verified"
+)
+
+// CompanyDomain is an email domain claimed by a company. Once its ownership is proven, the company
+// is verified and recruiters with an email address on the domain can join it without an invitation.
+type CompanyDomain struct {
+	PublicID        string `json:"public_id"`
+	CompanyPublicID string `json:"company_public_id"`
+	Domain          string `json:"domain"`
+	Method          string `json:"method"`
+	Status          string `json:"status"`
+	// DNSRecord is the TXT record to publish, for pending domains verified with DNS. Its value is DNSToken.
+	DNSRecord *DNSRecord `json:"dns_record,omitempty"`
+	DNSToken  string     `json:"-"`
+	// Email is the address the code was sent to, for domains verified by email
+	Email      string     `json:"email,omitempty"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	VerifiedAt *time.Time `json:"verified_at"`
+}
+
+type DNSRecord struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
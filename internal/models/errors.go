@@ -1,13 +1,105 @@
 package models
 
-import "errors"
+import (
+	"net/http"
+)
 
 var (
-	ErrInvalidInput        = errors.New("INVALID_INPUT")
-	ErrInternalServer      = errors.New("INTERNAL_SERVER_ERROR")
-	ErrCompanyDoesntExists = errors.New("COMPANY_DOES_NOT_EXIST")
-	ErrUsernameExists      = errors.New("USERNAME_EXISTS")
-	ErrUserNotFound        = errors.New("USER_NOT_FOUND")
-	ErrPermissionDenied    = errors.New("PERMISSION_DENIED")
-	ErrCompanyNotFound     = errors.New("COMPANY_NOT_FOUND")
+	ErrInvalidInput        = NewError("INVALID_INPUT", http.StatusBadRequest, "The request is invalid.")
+	ErrInternalServer      = NewError("INTERNAL_SERVER_ERROR", http.StatusInternalServerError, "Something went wrong, please try again later.")
+	ErrCompanyDoesntExists = NewError("COMPANY_DOES_NOT_EXIST", http.StatusNotFound, "The company does not exist.")
+	ErrUsernameExists      = NewError("USERNAME_EXISTS", http.StatusConflict, "The username is already taken.")
+	ErrUserNotFound        = NewError("USER_NOT_FOUND", http.StatusNotFound, "The user was not found.")
+	ErrUserExists          = NewError("USER_EXISTS", http.StatusConflict, "Another user has this public ID.")
+	ErrPermissionDenied    = NewError("PERMISSION_DENIED", http.StatusForbidden, "You are not allowed to do this.")
+	ErrCompanyNotFound     = NewError("COMPANY_NOT_FOUND", http.StatusNotFound, "The company was not found.")
+	ErrShortlistNotFound   = NewError("SHORTLIST_NOT_FOUND", http.StatusNotFound, "The shortlist was not found.")
+	ErrNoteNotFound        = NewError("NOTE_NOT_FOUND", http.StatusNotFound, "The note was not found.")
+	ErrInterviewNotFound   = NewError("INTERVIEW_NOT_FOUND", http.StatusNotFound, "The interview was not found.")
+	ErrSavedSearchNotFound = NewError("SAVED_SEARCH_NOT_FOUND", http.StatusNotFound, "The saved search was not found.")
+	ErrWebhookNotFound     = NewError("WEBHOOK_NOT_FOUND", http.StatusNotFound, "The webhook was not found.")
+	ErrDeliveryNotFound    = NewError("WEBHOOK_DELIVERY_NOT_FOUND", http.StatusNotFound, "The webhook delivery was not found.")
+	ErrRateLimited         = NewError("RATE_LIMIT_EXCEEDED", http.StatusTooManyRequests, "Too many requests, please slow down.")
+	ErrCSRFTokenInvalid    = NewError("CSRF_TOKEN_INVALID", http.StatusForbidden, "The request must send the CSRF token of the client.")
+	ErrNotSupported        = NewError("NOT_SUPPORTED", http.StatusNotImplemented, "This is not available with the configured storage.")
+	ErrInvalidServiceToken = NewError("INVALID_SERVICE_TOKEN", http.StatusUnauthorized, "The request must send the bearer token of an allowed service.")
+	ErrInvalidToken        = NewError("INVALID_TOKEN", http.StatusUnauthorized, "The request must send a valid access token.")
+	ErrTokenRevoked        = NewError("TOKEN_REVOKED", http.StatusUnauthorized, "The access token was revoked, please sign in again.")
+	ErrNoCompany           = NewError("NO_COMPANY", http.StatusForbidden, "You do not belong to a company.")
+	ErrAlreadyMember       = NewError("ALREADY_MEMBER", http.StatusConflict, "The recruiter already belongs to the company.")
+	ErrLastOwner           = NewError("LAST_OWNER", http.StatusConflict, "The company must keep at least one owner.")
+	ErrInvitationNotFound  = NewError("INVITATION_NOT_FOUND", http.StatusNotFound, "The invitation was not found.")
+	ErrInvitationInvalid   = NewError("INVITATION_INVALID", http.StatusGone, "The invitation expired, was revoked or was already used.")
+	ErrJoinRequestNotFound = NewError("JOIN_REQUEST_NOT_FOUND", http.StatusNotFound, "The join request was not found.")
+	ErrJoinRequestDecided  = NewError("JOIN_REQUEST_DECIDED", http.StatusConflict, "The join request was already approved or rejected.")
+	ErrDomainNotFound      = NewError("DOMAIN_NOT_FOUND", http.StatusNotFound, "The domain was not found.")
+	ErrDomainTaken         = NewError("DOMAIN_TAKEN", http.StatusConflict, "The domain was verified by another company.")
+	ErrDomainNotVerified   = NewError("DOMAIN_NOT_VERIFIED", http.StatusUnprocessableEntity, "The TXT record was not found, or the code is wrong or expired.")
+	ErrEmailDomainMismatch = NewError("EMAIL_DOMAIN_MISMATCH", http.StatusForbidden, "Your email address is not verified or not on a verified domain of the company.")
 )
+
+// Error is a domain error. Code is the stable identifier clients match on,
+// Status the HTTP status it is rendered with and Fields lists the rejected request fields.
+// Errors with the same code match each other with errors.Is, whatever their status, message or fields.
+type Error struct {
+	Code    string
+	Status  int
+	Message string
+	Fields  []FieldError
+	// Err is the underlying cause. It is logged but never sent to clients.
+	Err error
+}
+
+// FieldError tells why a single request field was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// NewError creates a domain error
+func NewError(code string, status int, message string) *Error {
+	return &Error{
+		Code:    code,
+		Status:  status,
+		Message: message,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Code
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithFields returns a copy of the error listing the given rejected fields
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	res := *e
+	res.Fields = append(append([]FieldError{}, e.Fields...), fields...)
+	return &res
+}
+
+// WithField returns a copy of the error listing a single rejected field
+func (e *Error) WithField(field, message string) *Error {
+	return e.WithFields(FieldError{Field: field, Message: message})
+}
+
+// WithStatus returns a copy of the error rendered with another HTTP status
+func (e *Error) WithStatus(status int) *Error {
+	res := *e
+	res.Status = status
+	return &res
+}
+
+// Wrap returns a copy of the error caused by err
+func (e *Error) Wrap(err error) *Error {
+	res := *e
+	res.Err = err
+	return &res
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+const (
+	EventCandidateCreated      = "candidate.created"
+	EventCandidateUpdated      = "candidate.updated"
+	EventCandidateDeleted      = "candidate.deleted"
+	EventRecruiterCreated      = "recruiter.created"
+	EventCompanyCreated        = "company.created"
+	EventCompanyMemberJoined   = "company.member_joined"
+	EventCompanyMemberLeft     = "company.member_left"
+	EventCompanyRoleChanged    = "company.role_changed"
+	EventCompanyDomainVerified = "company.domain_verified"
+	EventSkillsChanged         = "skills.changed"
+	EventInterviewResultStored = "interview.result_stored"
+
+	AggregateCandidate = "candidate"
+	AggregateRecruiter = "recruiter"
+	AggregateCompany   = "company"
+	AggregateInterview = "interview"
+)
+
+// DomainEvent is a change other services can react to.
+// Events are delivered at least once, so consumers should deduplicate them by PublicID.
+type DomainEvent struct {
+	PublicID          string                 `json:"id"`
+	Type              string                 `json:"type"`
+	AggregateType     string                 `json:"aggregate_type"`
+	AggregatePublicID string                 `json:"aggregate_id"`
+	Payload           map[string]interface{} `json:"payload"`
+	Attempts          int                    `json:"-"`
+	OccurredAt        time.Time              `json:"occurred_at"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+const (
+	// RoleOwner and RoleAdmin manage the members of the company, only owners may grant or take
+	// the owner role. Viewers can see the company's shared shortlists and notes.
+	RoleOwner     = "owner"
+	RoleAdmin     = "admin"
+	RoleRecruiter = "recruiter"
+	RoleViewer    = "viewer"
+
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationRevoked  = "revoked"
+	InvitationExpired  = "expired"
+
+	JoinRequestPending  = "pending"
+	JoinRequestApproved = "approved"
+	JoinRequestRejected = "rejected"
+)
+
+// CompanyRoles lists the roles of company members, from the most to the least privileged.
+var CompanyRoles = []string{RoleOwner, RoleAdmin, RoleRecruiter, RoleViewer}
+
+// RoleRank orders the roles by privilege, it is higher for more privileged roles and 0 for unknown ones.
+func RoleRank(role string) int {
+	for i, r := range CompanyRoles {
+		if r == role {
+			return len(CompanyRoles) - i
+		}
+	}
+	return 0
+}
+
+// Membership is the company of a recruiter and its role in it.
+// CompanyPublicID is empty for a recruiter that belongs to no company.
+type Membership struct {
+	CompanyPublicID string `json:"company_public_id"`
+	Role            string `json:"role"`
+}
+
+// IsAdmin tells whether the member manages the members of the company
+func (m *Membership) IsAdmin() bool {
+	return m.CompanyPublicID != "" && RoleRank(m.Role) >= RoleRank(RoleAdmin)
+}
+
+type CompanyMember struct {
+	PublicID  string `json:"public_id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Photo     string `json:"photo"`
+	Role      string `json:"role"`
+}
+
+// Invitation invites the owner of an email address to join a company. Its token is only sent
+// by email and stored hashed.
+type Invitation struct {
+	PublicID        string     `json:"public_id"`
+	CompanyPublicID string     `json:"company_public_id"`
+	Email           string     `json:"email"`
+	Role            string     `json:"role"`
+	Status          string     `json:"status"`
+	InvitedBy       string     `json:"invited_by"`
+	AcceptedBy      *string    `json:"accepted_by"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	AcceptedAt      *time.Time `json:"accepted_at"`
+}
+
+// JoinRequest is a recruiter's request to join a company, approved or rejected by its admins
+type JoinRequest struct {
+	PublicID          string     `json:"public_id"`
+	CompanyPublicID   string     `json:"company_public_id"`
+	RecruiterPublicID string     `json:"recruiter_public_id"`
+	FirstName         string     `json:"first_name"`
+	LastName          string     `json:"last_name"`
+	Message           string     `json:"message"`
+	Status            string     `json:"status"`
+	DecidedBy         *string    `json:"decided_by"`
+	CreatedAt         time.Time  `json:"created_at"`
+	DecidedAt         *time.Time `json:"decided_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+const (
+	NoteSubjectCandidate = "candidate"
+	NoteSubjectInterview = "interview"
+
+	MinNoteRating = 1
+	MaxNoteRating = 5
+)
+
+type Note struct {
+	PublicID        string    `json:"public_id"`
+	CompanyPublicID string    `json:"company_public_id"`
+	AuthorPublicID  string    `json:"author_public_id"`
+	SubjectType     string    `json:"subject_type"`
+	SubjectPublicID string    `json:"subject_public_id"`
+	Body            *string   `json:"body"`
+	Rating          *int      `json:"rating"`
+	Mentions        []string  `json:"mentions"`
+	Version         int       `json:"version"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type NoteRevision struct {
+	Version  int       `json:"version"`
+	Body     string    `json:"body"`
+	Rating   *int      `json:"rating"`
+	EditedBy string    `json:"edited_by"`
+	EditedAt time.Time `json:"edited_at"`
+}
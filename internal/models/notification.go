@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+const (
+	NotificationInterviewEvaluated  = "interview.evaluated"
+	NotificationApplicationReceived = "application.received"
+	NotificationNoteMention         = "note.mention"
+	NotificationSavedSearchMatch    = "saved_search.match"
+	// NotificationCompanyInvitation is sent to an email address, which may not belong to a user yet,
+	// so it cannot be turned off
+	NotificationCompanyInvitation = "company.invitation"
+	// NotificationDomainVerification sends the code proving a company owns an email domain
+	NotificationDomainVerification = "company.domain_verification"
+
+	NotificationStatusPending = "pending"
+	NotificationStatusSent    = "sent"
+	NotificationStatusFailed  = "failed"
+	NotificationStatusSkipped = "skipped"
+)
+
+// NotificationEventTypes lists every event a user can be notified about.
+var NotificationEventTypes = []string{
+	NotificationInterviewEvaluated,
+	NotificationApplicationReceived,
+	NotificationNoteMention,
+	NotificationSavedSearchMatch,
+}
+
+type Notification struct {
+	PublicID     string `json:"public_id"`
+	UserPublicID string `json:"user_public_id"`
+	// RecipientEmail is the address of a notification sent to no user, like an invitation
+	RecipientEmail string                 `json:"-"`
+	EventType      string                 `json:"event_type"`
+	Payload        map[string]interface{} `json:"payload"`
+	Status         string                 `json:"status"`
+	Attempts       int                    `json:"attempts"`
+	CreatedAt      time.Time              `json:"created_at"`
+	SentAt         *time.Time             `json:"sent_at"`
+}
+
+type NotificationPreference struct {
+	EventType    string `json:"event_type"`
+	EmailEnabled bool   `json:"email_enabled"`
+}
+
+type NotificationRecipient struct {
+	Email        string
+	FirstName    string
+	LastName     string
+	EmailEnabled bool
+}
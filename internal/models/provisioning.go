@@ -0,0 +1,48 @@
+package models
+
+// CandidateProvision is the profile of a candidate signing up with the auth service.
+// PublicID is optional, a request retried with the same one returns the profile created first.
+type CandidateProvision struct {
+	PublicID        string   `json:"public_id" binding:"omitempty,uuid"`
+	FirstName       string   `json:"first_name" binding:"required,notblank,max=50,personname"`
+	LastName        string   `json:"last_name" binding:"max=50,personname"`
+	Email           string   `json:"email" binding:"omitempty,max=50,email"`
+	Photo           string   `json:"photo" binding:"omitempty,max=50,url"`
+	CurrentPosition string   `json:"current_position" binding:"max=50"`
+	Education       string   `json:"education" binding:"max=50"`
+	Resume          string   `json:"resume" binding:"max=50"`
+	Bio             string   `json:"bio" binding:"max=50"`
+	Skills          []string `json:"skills"`
+}
+
+// RecruiterProvision is the profile of a recruiter signing up with the auth service. The recruiter
+// joins the company it was invited to with InvitationToken, or else the company with CompanyPublicID,
+// or else the company named CompanyName, which is created if there is none. The recruiter owns a
+// company without an owner. A company with an owner is only joined once an admin approves the join
+// request made for the recruiter, unless EmailVerified and the email is on a domain it verified.
+type RecruiterProvision struct {
+	PublicID  string `json:"public_id" binding:"omitempty,uuid"`
+	FirstName string `json:"first_name" binding:"required,notblank,max=50,personname"`
+	LastName  string `json:"last_name" binding:"max=50,personname"`
+	Email     string `json:"email" binding:"omitempty,max=50,email"`
+	// EmailVerified tells whether the auth service verified that the user owns the email address.
+	// Only a verified email lets the recruiter join a company that verified its domain.
+	EmailVerified   bool   `json:"email_verified"`
+	Photo           string `json:"photo" binding:"omitempty,max=50,url"`
+	CompanyPublicID string `json:"company_public_id" binding:"omitempty,uuid"`
+	CompanyName     string `json:"company_name"`
+	InvitationToken string `json:"invitation_token"`
+}
+
+// ProvisionedUser is the profile created for a user signing up
+type ProvisionedUser struct {
+	PublicID string `json:"public_id"`
+	// UserID is the serial ID of the user, which the credentials kept by the auth service refer to
+	UserID          int    `json:"user_id"`
+	CompanyPublicID string `json:"company_public_id,omitempty"`
+	Role            string `json:"role,omitempty"`
+	// JoinRequestPublicID is the join request made for a recruiter signing up to a company with an owner
+	JoinRequestPublicID string `json:"join_request_public_id,omitempty"`
+	// Created is false when the profile existed already, for a retried request
+	Created bool `json:"created"`
+}
@@ -3,6 +3,7 @@ package models
 type Recruiter struct {
 	PublicID        string     `json:"public_id"`
 	CompanyPublicID string     `json:"company_public_id"`
+	Role            string     `json:"role"`
 	FirstName       string     `json:"first_name"`
 	LastName        string     `json:"last_name"`
 	Photo           string     `json:"photo"`
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type SavedSearch struct {
+	PublicID          string     `json:"public_id"`
+	RecruiterPublicID string     `json:"recruiter_public_id"`
+	Name              string     `json:"name"`
+	Params            SearchArgs `json:"params"`
+	LastRunAt         *time.Time `json:"last_run_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+type SavedSearchAlert struct {
+	PublicID            string     `json:"public_id"`
+	SavedSearchPublicID string     `json:"saved_search_public_id"`
+	SavedSearchName     string     `json:"saved_search_name"`
+	Candidate           *Candidate `json:"candidate"`
+	Seen                bool       `json:"seen"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type Shortlist struct {
+	PublicID          string    `json:"public_id"`
+	RecruiterPublicID string    `json:"recruiter_public_id"`
+	CompanyPublicID   string    `json:"company_public_id"`
+	Name              *string   `json:"name"`
+	Description       *string   `json:"description"`
+	Shared            *bool     `json:"shared"`
+	CandidatesCount   int       `json:"candidates_count"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type ShortlistCandidate struct {
+	Candidate *Candidate `json:"candidate"`
+	Note      string     `json:"note"`
+	AddedBy   string     `json:"added_by"`
+	AddedAt   time.Time  `json:"added_at"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+const (
+	WebhookEventApplicationReceived   = "application.received"
+	WebhookEventInterviewResultStored = "interview.result_stored"
+
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookEventTypes lists every event a company can subscribe a webhook to.
+var WebhookEventTypes = []string{
+	WebhookEventApplicationReceived,
+	WebhookEventInterviewResultStored,
+}
+
+type Webhook struct {
+	PublicID        string    `json:"public_id"`
+	CompanyPublicID string    `json:"company_public_id"`
+	URL             string    `json:"url"`
+	Secret          string    `json:"secret,omitempty"`
+	EventTypes      []string  `json:"event_types"`
+	Active          *bool     `json:"active"`
+	CreatedBy       string    `json:"created_by"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	PublicID        string                 `json:"public_id"`
+	WebhookPublicID string                 `json:"webhook_public_id"`
+	EventType       string                 `json:"event_type"`
+	Payload         map[string]interface{} `json:"payload"`
+	Status          string                 `json:"status"`
+	Attempts        int                    `json:"attempts"`
+	LastStatusCode  *int                   `json:"last_status_code"`
+	LastError       *string                `json:"last_error"`
+	NextAttemptAt   *time.Time             `json:"next_attempt_at"`
+	CreatedAt       time.Time              `json:"created_at"`
+	DeliveredAt     *time.Time             `json:"delivered_at"`
+
+	// URL and Secret of the webhook, only set for deliveries claimed for sending.
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
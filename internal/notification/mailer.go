@@ -0,0 +1,64 @@
+package notification
+
+import (
+	"fmt"
+	"mime"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+)
+
+// Mailer delivers a single email.
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+type smtpMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer creates a Mailer that delivers through the configured SMTP server.
+// Authentication is only used when a username is configured, so a local SMTP sink works without credentials.
+func NewSMTPMailer(cfg *config.SMTPConf) Mailer {
+	m := &smtpMailer{
+		addr: cfg.Host + ":" + strconv.Itoa(cfg.Port),
+		from: cfg.From,
+	}
+	if cfg.Username != "" {
+		m.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return m
+}
+
+// Send delivers the email. The subject holds values users control, so it is written on a single line
+// and encoded, and the recipient must be a single address, so neither can add headers to the email.
+func (m *smtpMailer) Send(to, subject, body string) error {
+	if strings.ContainsAny(to, "\r\n") {
+		return fmt.Errorf("invalid recipient %q", to)
+	}
+	rcpt, err := mail.ParseAddress(to)
+	if err != nil {
+		return fmt.Errorf("invalid recipient %q: %w", to, err)
+	}
+
+	msg := strings.Builder{}
+	fmt.Fprintf(&msg, "From: %s\r\n", m.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", rcpt.String())
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", headerValue(subject)))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{rcpt.Address}, []byte(msg.String()))
+}
+
+// headerValue folds the line breaks of a header value into spaces
+func headerValue(s string) string {
+	return strings.Join(strings.FieldsFunc(s, func(r rune) bool { return r == '\r' || r == '\n' }), " ")
+}
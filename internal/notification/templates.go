@@ -0,0 +1,57 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// Templates renders the subject and body of the email sent for an event type.
+// Every event type has a template file named after it, e.g. templates/note.mention.tmpl,
+// defining a "subject" and a "body" template.
+type Templates struct {
+	templates map[string]*template.Template
+}
+
+// TemplateData is passed to the templates.
+type TemplateData struct {
+	FirstName string
+	LastName  string
+	Payload   map[string]interface{}
+}
+
+// NewTemplates parses the embedded templates. The templates are part of the binary,
+// so a template that fails to parse is a programming error and panics.
+func NewTemplates() *Templates {
+	entries, err := templateFS.ReadDir("templates")
+	if err != nil {
+		panic(err)
+	}
+	t := &Templates{templates: make(map[string]*template.Template, len(entries))}
+	for _, entry := range entries {
+		tmpl := template.Must(template.ParseFS(templateFS, "templates/"+entry.Name()))
+		t.templates[strings.TrimSuffix(entry.Name(), ".tmpl")] = tmpl
+	}
+	return t
+}
+
+func (t *Templates) Render(eventType string, data *TemplateData) (string, string, error) {
+	tmpl, ok := t.templates[eventType]
+	if !ok {
+		return "", "", fmt.Errorf("no email template for event %s", eventType)
+	}
+	subject := bytes.Buffer{}
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return "", "", err
+	}
+	body := bytes.Buffer{}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(subject.String()), strings.TrimSpace(body.String()) + "\n", nil
+}
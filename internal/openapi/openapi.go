@@ -0,0 +1,181 @@
+package openapi
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+)
+
+// SwaggerUIPath is where the bundled Swagger UI is served. Its static files are not part of the document.
+const SwaggerUIPath = "/swagger"
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+//go:embed swagger-ui
+var swaggerUI embed.FS
+
+// Spec is the OpenAPI document of the service with its operations indexed by route.
+type Spec struct {
+	json   []byte
+	routes map[string]*routers.Route
+}
+
+// Load parses and validates the embedded OpenAPI document.
+func Load() (*Spec, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(specYAML)
+	if err != nil {
+		return nil, fmt.Errorf("parse openapi document: %w", err)
+	}
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("invalid openapi document: %w", err)
+	}
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &Spec{
+		json:   raw,
+		routes: make(map[string]*routers.Route),
+	}
+	for path, item := range doc.Paths.Map() {
+		for method, operation := range item.Operations() {
+			spec.routes[routeKey(method, path)] = &routers.Route{
+				Spec:      doc,
+				Path:      path,
+				PathItem:  item,
+				Method:    method,
+				Operation: operation,
+			}
+		}
+	}
+	return spec, nil
+}
+
+// JSON returns the document encoded as JSON.
+func (s *Spec) JSON() []byte {
+	return s.json
+}
+
+// SwaggerUI returns the bundled Swagger UI, which renders the document served at /openapi.json.
+func SwaggerUI() http.FileSystem {
+	dist, _ := fs.Sub(swaggerUI, "swagger-ui")
+	return http.FS(dist)
+}
+
+// ValidateRequest checks the request against the operation documented for the gin route path.
+// Requests to routes missing from the document are not validated.
+// Authentication is left to the handlers, so security requirements are not checked.
+func (s *Spec) ValidateRequest(ctx context.Context, req *http.Request, routePath string, params gin.Params) error {
+	route, ok := s.routes[routeKey(req.Method, specPath(routePath))]
+	if !ok {
+		return nil
+	}
+
+	pathParams := make(map[string]string, len(params))
+	for _, param := range params {
+		pathParams[param.Key] = param.Value
+	}
+	input := &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}
+	return openapi3filter.ValidateRequest(ctx, input)
+}
+
+// FieldErrors lists the request fields rejected by ValidateRequest
+func FieldErrors(err error) []models.FieldError {
+	var reqErr *openapi3filter.RequestError
+	if !errors.As(err, &reqErr) {
+		return nil
+	}
+
+	field := "body"
+	if reqErr.Parameter != nil {
+		field = reqErr.Parameter.Name
+	}
+	message := reqErr.Reason
+	var schemaErr *openapi3.SchemaError
+	if errors.As(reqErr.Err, &schemaErr) {
+		if pointer := schemaErr.JSONPointer(); reqErr.Parameter == nil && len(pointer) > 0 {
+			field = strings.Join(pointer, ".")
+		}
+		message = schemaErr.Reason
+	} else if message == "" && reqErr.Err != nil {
+		message = reqErr.Err.Error()
+	}
+	return []models.FieldError{{Field: field, Message: message}}
+}
+
+// CheckRoutes returns an error listing the registered routes missing from the document
+// and the documented operations that have no route.
+func (s *Spec) CheckRoutes(routes gin.RoutesInfo) error {
+	registered := make(map[string]bool, len(routes))
+	missing := make([]string, 0)
+	for _, route := range routes {
+		if strings.HasPrefix(route.Path, SwaggerUIPath+"/") {
+			continue
+		}
+		key := routeKey(route.Method, specPath(route.Path))
+		registered[key] = true
+		if _, ok := s.routes[key]; !ok {
+			missing = append(missing, route.Method+" "+route.Path)
+		}
+	}
+
+	unrouted := make([]string, 0)
+	for key := range s.routes {
+		if !registered[key] {
+			unrouted = append(unrouted, key)
+		}
+	}
+
+	if len(missing) == 0 && len(unrouted) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	sort.Strings(unrouted)
+	var msg strings.Builder
+	msg.WriteString("openapi document does not match the routes")
+	if len(missing) > 0 {
+		fmt.Fprintf(&msg, "; undocumented routes: %s", strings.Join(missing, ", "))
+	}
+	if len(unrouted) > 0 {
+		fmt.Fprintf(&msg, "; documented operations without a route: %s", strings.Join(unrouted, ", "))
+	}
+	return errors.New(msg.String())
+}
+
+func routeKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// specPath converts a gin route path such as /candidate/:candidate_public_id
+// to the OpenAPI form /candidate/{candidate_public_id}.
+func specPath(routePath string) string {
+	segments := strings.Split(routePath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
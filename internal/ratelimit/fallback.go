@@ -0,0 +1,30 @@
+package ratelimit
+
+import (
+	"go.uber.org/zap"
+)
+
+type fallbackLimiter struct {
+	primary  Limiter
+	fallback Limiter
+	logger   *zap.SugaredLogger
+}
+
+// NewFallbackLimiter creates a Limiter that uses primary and switches to fallback for the
+// requests primary fails, e.g. while Redis is down.
+func NewFallbackLimiter(primary, fallback Limiter, logger *zap.SugaredLogger) Limiter {
+	return &fallbackLimiter{
+		primary:  primary,
+		fallback: fallback,
+		logger:   logger,
+	}
+}
+
+func (l *fallbackLimiter) Allow(key string, limit Limit) (*Result, error) {
+	res, err := l.primary.Allow(key, limit)
+	if err == nil {
+		return res, nil
+	}
+	l.logger.Warnf("rate limiter unavailable, using in-process fallback: %v", err)
+	return l.fallback.Allow(key, limit)
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// Limit is a token bucket that holds at most Burst tokens and refills Rate tokens per second.
+// Every request takes one token.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result is the state of a bucket after a request.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed. It is zero for allowed requests.
+	RetryAfter time.Duration
+}
+
+// Limiter takes a token from the bucket identified by key.
+type Limiter interface {
+	Allow(key string, limit Limit) (*Result, error)
+}
+
+// newResult builds the result from the tokens left in the bucket.
+func newResult(allowed bool, tokens float64, limit Limit) *Result {
+	res := &Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     refillTime(float64(limit.Burst)-tokens, limit.Rate),
+	}
+	if !allowed {
+		res.RetryAfter = refillTime(1-tokens, limit.Rate)
+	}
+	return res
+}
+
+func refillTime(tokens, rate float64) time.Duration {
+	if tokens <= 0 || rate <= 0 {
+		return 0
+	}
+	return time.Duration(tokens / rate * float64(time.Second))
+}
+
+// take refills the bucket for the time elapsed since it was last updated and takes a token if one is left.
+func take(tokens float64, last, now time.Time, limit Limit) (float64, bool) {
+	elapsed := now.Sub(last).Seconds()
+	if elapsed > 0 {
+		tokens = math.Min(float64(limit.Burst), tokens+elapsed*limit.Rate)
+	}
+	if tokens >= 1 {
+		return tokens - 1, true
+	}
+	return tokens, false
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryCleanupSize is the number of buckets above which full buckets are dropped.
+const memoryCleanupSize = 10000
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  Limit
+}
+
+type memoryLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewMemoryLimiter creates a Limiter that keeps the buckets in process.
+// Limits are enforced per replica, so the effective limit grows with the number of replicas.
+func NewMemoryLimiter() Limiter {
+	return &memoryLimiter{
+		buckets: make(map[string]*bucket),
+	}
+}
+
+func (l *memoryLimiter) Allow(key string, limit Limit) (*Result, error) {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= memoryCleanupSize {
+			l.cleanup(now)
+		}
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+	b.limit = limit
+	tokens, allowed := take(b.tokens, b.last, now, limit)
+	b.tokens, b.last = tokens, now
+	return newResult(allowed, tokens, limit), nil
+}
+
+// cleanup drops the buckets that have refilled, as they are the same as a new bucket.
+// It must be called with the lock held.
+func (l *memoryLimiter) cleanup(now time.Time) {
+	for key, b := range l.buckets {
+		if refillTime(float64(b.limit.Burst)-b.tokens, b.limit.Rate) <= now.Sub(b.last) {
+			delete(l.buckets, key)
+		}
+	}
+}
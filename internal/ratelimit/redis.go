@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+)
+
+// tokenBucketScript refills and takes a token from the bucket atomically.
+// The bucket expires once it would be full again, as a missing bucket is treated as full.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+if now > ts then
+	tokens = math.min(burst, tokens + (now - ts) / 1000 * rate)
+end
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+type redisLimiter struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisLimiter creates a Limiter that keeps the buckets in Redis, so limits are shared by all replicas.
+func NewRedisLimiter(client *redis.Client, prefix string) Limiter {
+	return &redisLimiter{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (l *redisLimiter) Allow(key string, limit Limit) (*Result, error) {
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	reply, err := tokenBucketScript.Run(l.client, []string{l.prefix + key}, limit.Rate, limit.Burst, now).Result()
+	if err != nil {
+		return nil, err
+	}
+	res, ok := reply.([]interface{})
+	if !ok || len(res) != 2 {
+		return nil, fmt.Errorf("unexpected rate limit script reply %v", reply)
+	}
+	allowed, _ := res[0].(int64)
+	tokensStr, _ := res[1].(string)
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return nil, err
+	}
+	return newResult(allowed == 1, math.Max(tokens, 0), limit), nil
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/cache"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"go.uber.org/zap"
+)
+
+func candidateCacheKey(publicID string) string { return "candidate:" + publicID }
+func recruiterCacheKey(publicID string) string { return "recruiter:" + publicID }
+func companyCacheKey(publicID string) string   { return "company:" + publicID }
+
+// cachedCandidateRepository caches candidate profiles.
+// Every write path of the repository invalidates the profile it changes, when it is called and once
+// more when the transaction of a unit of work it joined ends. Interviews are attached
+// by other services, so they can be stale for up to the TTL.
+type cachedCandidateRepository struct {
+	CandidateRepository
+	cache  cache.Cache
+	ttl    time.Duration
+	logger *zap.SugaredLogger
+}
+
+// NewCachedCandidateRepository decorates the candidate repository with a read cache.
+func NewCachedCandidateRepository(next CandidateRepository, c cache.Cache, ttl time.Duration, logger *zap.SugaredLogger) CandidateRepository {
+	return &cachedCandidateRepository{
+		CandidateRepository: next,
+		cache:               cache.WithMetrics("candidates", c),
+		ttl:                 ttl,
+		logger:              logger,
+	}
+}
+
+func (r *cachedCandidateRepository) GetCandidateByPublicID(ctx context.Context, publicID string) (*models.Candidate, error) {
+	candidate := &models.Candidate{}
+	if found, err := r.cache.Get(candidateCacheKey(publicID), candidate); err != nil {
+		logging.FromContext(ctx, r.logger).Warnf("Error occurred while reading candidate from cache: %v", err)
+	} else if found {
+		return candidate, nil
+	}
+
+	candidate, err := r.CandidateRepository.GetCandidateByPublicID(ctx, publicID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.cache.Set(candidateCacheKey(publicID), candidate, r.ttl); err != nil {
+		logging.FromContext(ctx, r.logger).Warnf("Error occurred while writing candidate to cache: %v", err)
+	}
+	return candidate, nil
+}
+
+func (r *cachedCandidateRepository) AddSkillsToCandidate(ctx context.Context, candidateID string, skills []string, events ...*models.DomainEvent) (*models.SkillsChange, error) {
+	defer r.invalidate(ctx, candidateID)
+	return r.CandidateRepository.AddSkillsToCandidate(ctx, candidateID, skills, events...)
+}
+
+func (r *cachedCandidateRepository) UpdateCandidateByID(ctx context.Context, candidateID string, updateData *models.Candidate, events ...*models.DomainEvent) error {
+	defer r.invalidate(ctx, candidateID)
+	return r.CandidateRepository.UpdateCandidateByID(ctx, candidateID, updateData, events...)
+}
+
+func (r *cachedCandidateRepository) DeleteCandidateByID(ctx context.Context, candidateID string, events ...*models.DomainEvent) error {
+	defer r.invalidate(ctx, candidateID)
+	return r.CandidateRepository.DeleteCandidateByID(ctx, candidateID, events...)
+}
+
+func (r *cachedCandidateRepository) DeleteSkillsFromCandidate(ctx context.Context, candidateID string, skills []string, events ...*models.DomainEvent) (*models.SkillsChange, error) {
+	defer r.invalidate(ctx, candidateID)
+	return r.CandidateRepository.DeleteSkillsFromCandidate(ctx, candidateID, skills, events...)
+}
+
+func (r *cachedCandidateRepository) ReplaceCandidateSkills(ctx context.Context, candidateID string, skills []string, events ...*models.DomainEvent) (*models.SkillsChange, error) {
+	defer r.invalidate(ctx, candidateID)
+	return r.CandidateRepository.ReplaceCandidateSkills(ctx, candidateID, skills, events...)
+}
+
+func (r *cachedCandidateRepository) invalidate(ctx context.Context, candidateID string) {
+	invalidate(ctx, r.cache, r.logger, candidateCacheKey(candidateID))
+}
+
+// cachedRecruiterRepository caches recruiter profiles.
+// The company of the recruiter is always read through the company repository, so company updates
+// are visible immediately. Positions are managed by another service and can be stale for up to the TTL.
+type cachedRecruiterRepository struct {
+	RecruiterRepository
+	companies CompanyRepository
+	cache     cache.Cache
+	ttl       time.Duration
+	logger    *zap.SugaredLogger
+}
+
+// NewCachedRecruiterRepository decorates the recruiter repository with a read cache.
+func NewCachedRecruiterRepository(next RecruiterRepository, companies CompanyRepository, c cache.Cache, ttl time.Duration, logger *zap.SugaredLogger) RecruiterRepository {
+	return &cachedRecruiterRepository{
+		RecruiterRepository: next,
+		companies:           companies,
+		cache:               cache.WithMetrics("recruiters", c),
+		ttl:                 ttl,
+		logger:              logger,
+	}
+}
+
+func (r *cachedRecruiterRepository) GetRecruiter(ctx context.Context, publicID string) (*models.Recruiter, error) {
+	recruiter := &models.Recruiter{}
+	found, err := r.cache.Get(recruiterCacheKey(publicID), recruiter)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Warnf("Error occurred while reading recruiter from cache: %v", err)
+	}
+	if !found {
+		recruiter, err = r.RecruiterRepository.GetRecruiter(ctx, publicID)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.cache.Set(recruiterCacheKey(publicID), recruiter, r.ttl); err != nil {
+			logging.FromContext(ctx, r.logger).Warnf("Error occurred while writing recruiter to cache: %v", err)
+		}
+	}
+
+	if recruiter.CompanyPublicID == "" {
+		recruiter.Company = nil
+		return recruiter, nil
+	}
+	company, err := r.companies.GetCompany(ctx, recruiter.CompanyPublicID)
+	if err != nil {
+		return nil, err
+	}
+	recruiter.Company = company
+	return recruiter, nil
+}
+
+// cachedMembershipRepository invalidates the cached profile of the recruiters whose company or
+// role it changes. The other calls are not cached.
+type cachedMembershipRepository struct {
+	MembershipRepository
+	cache  cache.Cache
+	logger *zap.SugaredLogger
+}
+
+// NewCachedMembershipRepository decorates the membership repository, sharing the cache of the recruiter repository.
+func NewCachedMembershipRepository(next MembershipRepository, c cache.Cache, logger *zap.SugaredLogger) MembershipRepository {
+	return &cachedMembershipRepository{
+		MembershipRepository: next,
+		cache:                c,
+		logger:               logger,
+	}
+}
+
+func (r *cachedMembershipRepository) SetMembership(ctx context.Context, recruiterPublicID, companyPublicID, role string, events ...*models.DomainEvent) error {
+	defer invalidate(ctx, r.cache, r.logger, recruiterCacheKey(recruiterPublicID))
+	return r.MembershipRepository.SetMembership(ctx, recruiterPublicID, companyPublicID, role, events...)
+}
+
+// cachedDomainRepository invalidates the cached company whose verified badge a domain change can
+// change. The other calls are not cached.
+type cachedDomainRepository struct {
+	DomainRepository
+	cache  cache.Cache
+	logger *zap.SugaredLogger
+}
+
+// NewCachedDomainRepository decorates the domain repository, sharing the cache of the company repository.
+func NewCachedDomainRepository(next DomainRepository, c cache.Cache, logger *zap.SugaredLogger) DomainRepository {
+	return &cachedDomainRepository{
+		DomainRepository: next,
+		cache:            c,
+		logger:           logger,
+	}
+}
+
+func (r *cachedDomainRepository) VerifyDomain(ctx context.Context, companyPublicID, domainPublicID, codeHash string, events ...*models.DomainEvent) (*models.CompanyDomain, error) {
+	defer r.invalidate(ctx, companyPublicID)
+	return r.DomainRepository.VerifyDomain(ctx, companyPublicID, domainPublicID, codeHash, events...)
+}
+
+func (r *cachedDomainRepository) DeleteDomain(ctx context.Context, companyPublicID, domainPublicID string) error {
+	defer r.invalidate(ctx, companyPublicID)
+	return r.DomainRepository.DeleteDomain(ctx, companyPublicID, domainPublicID)
+}
+
+func (r *cachedDomainRepository) invalidate(ctx context.Context, companyPublicID string) {
+	invalidate(ctx, r.cache, r.logger, companyCacheKey(companyPublicID))
+}
+
+// cachedCompanyRepository caches companies and invalidates them when they are updated.
+type cachedCompanyRepository struct {
+	CompanyRepository
+	cache  cache.Cache
+	ttl    time.Duration
+	logger *zap.SugaredLogger
+}
+
+// NewCachedCompanyRepository decorates the company repository with a read cache.
+func NewCachedCompanyRepository(next CompanyRepository, c cache.Cache, ttl time.Duration, logger *zap.SugaredLogger) CompanyRepository {
+	return &cachedCompanyRepository{
+		CompanyRepository: next,
+		cache:             cache.WithMetrics("companies", c),
+		ttl:               ttl,
+		logger:            logger,
+	}
+}
+
+func (r *cachedCompanyRepository) GetCompany(ctx context.Context, publicID string) (*models.Company, error) {
+	company := &models.Company{}
+	if found, err := r.cache.Get(companyCacheKey(publicID), company); err != nil {
+		logging.FromContext(ctx, r.logger).Warnf("Error occurred while reading company from cache: %v", err)
+	} else if found {
+		return company, nil
+	}
+
+	company, err := r.CompanyRepository.GetCompany(ctx, publicID)
+	if err != nil || company == nil {
+		return company, err
+	}
+	if err := r.cache.Set(companyCacheKey(publicID), company, r.ttl); err != nil {
+		logging.FromContext(ctx, r.logger).Warnf("Error occurred while writing company to cache: %v", err)
+	}
+	return company, nil
+}
+
+func (r *cachedCompanyRepository) UpdateCompany(ctx context.Context, company *models.Company) error {
+	defer invalidate(ctx, r.cache, r.logger, companyCacheKey(company.PublicID))
+	return r.CompanyRepository.UpdateCompany(ctx, company)
+}
+
+// invalidate drops the cached key. It runs even if the write failed, as the outcome of a failed commit
+// is not always known. In a transaction the key is dropped once more when the transaction ends, as a
+// read racing with it would cache the old row again until the TTL expires.
+func invalidate(ctx context.Context, c cache.Cache, logger *zap.SugaredLogger, key string) {
+	drop := func() {
+		if err := c.Delete(key); err != nil {
+			logging.FromContext(ctx, logger).Errorf("Error occurred while invalidating cached %s: %v", key, err)
+		}
+	}
+	drop()
+	afterTx(ctx, drop)
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
 	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -27,10 +29,11 @@ func NewCandidateRepository(db *pgxpool.Pool, cfg *config.DBConf, logger *zap.Su
 		logger: logger,
 	}
 }
-func (r *candidateRepository) GetCandidatesBySearch(searchArgs *models.SearchArgs) ([]*models.Candidate, int, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.TimeOut)
+func (r *candidateRepository) GetCandidatesBySearch(ctx context.Context, searchArgs *models.SearchArgs) ([]*models.Candidate, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
 	defer cancel()
-	query := `
+	where, params := candidateSearchCondition(searchArgs, nil)
+	query := fmt.Sprintf(`
 			SELECT
 				c.public_id,
 				c.current_position,
@@ -40,7 +43,7 @@ func (r *candidateRepository) GetCandidatesBySearch(searchArgs *models.SearchArg
 				u.photo,
 				u.first_name,
 				u.last_name,
-				array_agg(s.name) AS skills
+				COALESCE(array_agg(s.name ORDER BY s.name COLLATE "C") FILTER (WHERE s.name IS NOT NULL), '{}') AS skills
 			FROM
 				candidates c
 			JOIN
@@ -50,8 +53,9 @@ func (r *candidateRepository) GetCandidatesBySearch(searchArgs *models.SearchArg
 			LEFT JOIN
 				skills s ON cs.skill_id = s.id
 			WHERE
-				LOWER(u.first_name) LIKE LOWER($1) OR LOWER(u.last_name) LIKE LOWER($1)
+				%s
 			GROUP BY
+				c.id,
 				c.public_id,
 				c.current_position,
 				c.education,
@@ -60,34 +64,33 @@ func (r *candidateRepository) GetCandidatesBySearch(searchArgs *models.SearchArg
 				u.first_name,
 				u.last_name,
 				u.photo
-			OFFSET $2
-			LIMIT $3
-	`
+			ORDER BY
+				c.id
+			OFFSET $%d
+			LIMIT $%d
+	`, where, len(params)+1, len(params)+2)
 
 	countQuery := `
 	SELECT COUNT(DISTINCT c.id)
 	FROM candidates c
 	JOIN users u ON c.public_id = u.public_id
-	WHERE LOWER(u.first_name) LIKE LOWER($1) OR LOWER(u.last_name) LIKE LOWER($1)	
-	`
+	WHERE ` + where
 
 	var totalCount int
-	fmt.Println(query)
-	err := r.db.QueryRow(ctx, countQuery, "%"+searchArgs.Search+"%").Scan(&totalCount)
+	err := conn(ctx, r.db).QueryRow(ctx, countQuery, params...).Scan(&totalCount)
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
-		r.logger.Errorf("Error occurred while fetching candidates count: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while fetching candidates count: %v", err)
 		return nil, 0, err
 	}
 
-	rows, err := r.db.Query(ctx, query, "%"+searchArgs.Search+"%", (searchArgs.PageNum-1)*searchArgs.PageSize, searchArgs.PageSize)
+	rows, err := conn(ctx, r.db).Query(ctx, query, append(params, (searchArgs.PageNum-1)*searchArgs.PageSize, searchArgs.PageSize)...)
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
-		r.logger.Errorf("Error occurred while fetching candidates: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while fetching candidates: %v", err)
 		return nil, 0, err
 	}
 	defer rows.Close()
 
 	candidates := make([]*models.Candidate, 0)
-	fmt.Println(rows.RawValues())
 	for rows.Next() {
 		candidate := &models.Candidate{}
 
@@ -104,7 +107,7 @@ func (r *candidateRepository) GetCandidatesBySearch(searchArgs *models.SearchArg
 		)
 
 		if err != nil {
-			r.logger.Errorf("Error occurred while scanning candidate: %v", err)
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning candidate: %v", err)
 			return nil, 0, err
 		}
 
@@ -112,16 +115,65 @@ func (r *candidateRepository) GetCandidatesBySearch(searchArgs *models.SearchArg
 	}
 
 	if err := rows.Err(); err != nil {
-		r.logger.Errorf("Error occurred while iterating through candidate rows: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating through candidate rows: %v", err)
 		return nil, 0, err
 	}
 
 	return candidates, totalCount, nil
 }
 
-func (r *candidateRepository) GetCandidateByPublicID(publicID string) (*models.Candidate, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.TimeOut)
+// candidateSearchCondition builds the condition matching the candidates of the search, c and u being
+// the candidates and users tables. Its placeholders are numbered after params, which it returns with
+// their values appended. Saved searches match their candidates with it too, so both stay in sync.
+func candidateSearchCondition(searchArgs *models.SearchArgs, params []interface{}) (string, []interface{}) {
+	arg := func(value interface{}) string {
+		params = append(params, value)
+		return fmt.Sprintf("$%d", len(params))
+	}
+
+	search := arg("%" + searchArgs.Search + "%")
+	conditions := []string{fmt.Sprintf("(LOWER(u.first_name) LIKE LOWER(%[1]s) OR LOWER(u.last_name) LIKE LOWER(%[1]s))", search)}
+	if f := searchArgs.Filters; f != nil {
+		if f.CurrentPosition != "" {
+			conditions = append(conditions, "c.current_position ILIKE "+arg("%"+f.CurrentPosition+"%"))
+		}
+		if f.Education != "" {
+			conditions = append(conditions, "c.education ILIKE "+arg("%"+f.Education+"%"))
+		}
+		if len(f.Skills) > 0 {
+			conditions = append(conditions, fmt.Sprintf(`NOT EXISTS (
+				SELECT 1 FROM unnest(%s::text[]) AS f(name)
+				WHERE NOT EXISTS (
+					SELECT 1
+					FROM candidate_skills fcs
+					JOIN skills fs ON fs.id = fcs.skill_id
+					WHERE fcs.candidate_id = c.id AND lower(fs.name) = lower(f.name)
+				)
+			)`, arg(f.Skills)))
+		}
+	}
+	return strings.Join(conditions, " AND "), params
+}
+
+// GetCandidateByPublicID reads the candidate with its skills and interviews in a read only transaction,
+// so they are consistent with each other.
+func (r *candidateRepository) GetCandidateByPublicID(ctx context.Context, publicID string) (*models.Candidate, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
 	defer cancel()
+
+	var result *models.Candidate
+	err := inTx(ctx, r.db, r.cfg, r.logger, true, func(ctx context.Context) error {
+		var err error
+		result, err = r.getCandidate(ctx, publicID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *candidateRepository) getCandidate(ctx context.Context, publicID string) (*models.Candidate, error) {
 	var candidateID int
 	result := &models.Candidate{}
 	query := `SELECT c.id, c.public_id, c.current_position, c.education, c.resume, c.bio, u.first_name, u.last_name, u.photo
@@ -129,7 +181,7 @@ func (r *candidateRepository) GetCandidateByPublicID(publicID string) (*models.C
 	JOIN users u ON c.public_id = u.public_id
 	WHERE c.public_id = $1`
 
-	err := r.db.QueryRow(ctx, query, publicID).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, publicID).Scan(
 		&candidateID,
 		&result.PublicID,
 		&result.CurrentPosition,
@@ -145,19 +197,19 @@ func (r *candidateRepository) GetCandidateByPublicID(publicID string) (*models.C
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, models.ErrUserNotFound
 		}
-		r.logger.Errorf("Error occurred while checking user existence: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while checking user existence: %v", err)
 		return nil, err
 	}
 
-	query = `SELECT array_agg(DISTINCT s.name) from skills s
+	query = `SELECT COALESCE(array_agg(DISTINCT s.name COLLATE "C" ORDER BY s.name COLLATE "C"), '{}') from skills s
 	INNER JOIN candidate_skills cs ON cs.skill_id = s.id
 	INNER JOIN candidates c ON cs.candidate_id = c.id
 	WHERE cs.candidate_id = $1`
-	err = r.db.QueryRow(ctx, query, candidateID).Scan(
+	err = conn(ctx, r.db).QueryRow(ctx, query, candidateID).Scan(
 		&result.Skills,
 	)
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
-		r.logger.Errorf("Error occurred while checking user existence: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while checking user existence: %v", err)
 		return nil, err
 	}
 
@@ -166,7 +218,7 @@ func (r *candidateRepository) GetCandidateByPublicID(publicID string) (*models.C
 	INNER JOIN candidates c ON ui.candidate_id = c.id
 	WHERE ui.candidate_id = $1`
 	interviewIDs := make([]string, 0)
-	err = r.db.QueryRow(ctx, query, candidateID).Scan(
+	err = conn(ctx, r.db).QueryRow(ctx, query, candidateID).Scan(
 		&interviewIDs,
 	)
 	for i := range interviewIDs {
@@ -175,209 +227,274 @@ func (r *candidateRepository) GetCandidateByPublicID(publicID string) (*models.C
 		})
 	}
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
-		r.logger.Errorf("Error occurred while checking user existence: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while checking user existence: %v", err)
 		return nil, err
 	}
 
 	return result, nil
 }
 
-func (r *candidateRepository) UpdateCandidateByID(candidateID string, updateData *models.Candidate) error {
-	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.TimeOut)
+func (r *candidateRepository) UpdateCandidateByID(ctx context.Context, candidateID string, updateData *models.Candidate, events ...*models.DomainEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
 	defer cancel()
 
-	query := `
-	UPDATE candidates
-	SET
-		current_position = COALESCE($2, current_position),
-		education = COALESCE($3, education),
-		resume = COALESCE($4, resume),
-		bio = COALESCE($5, bio)
-	WHERE
-		public_id = $1
-	`
-
-	_, err := r.db.Exec(ctx, query, candidateID, updateData.CurrentPosition, updateData.Education, updateData.Resume, updateData.Bio)
-	if err != nil {
-		r.logger.Errorf("Error updating candidate: %v", err)
-		return err
-	}
-
-	query = `
-	UPDATE users
-	SET
-		first_name = COALESCE($2, first_name),
-		last_name = COALESCE($3, last_name),
-		photo = COALESCE($4, photo)
-	WHERE
-		public_id = $1
-	`
-
-	_, err = r.db.Exec(ctx, query, candidateID, updateData.FirstName, updateData.LastName, updateData.Photo)
-	if err != nil {
-		r.logger.Errorf("Error updating candidate's user data: %v", err)
-		return err
-	}
-
-	return nil
-}
+	return inTx(ctx, r.db, r.cfg, r.logger, false, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+		query := `
+		UPDATE candidates
+		SET
+			current_position = COALESCE($2, current_position),
+			education = COALESCE($3, education),
+			resume = COALESCE($4, resume),
+			bio = COALESCE($5, bio)
+		WHERE
+			public_id = $1
+		`
 
-func (r *candidateRepository) DeleteCandidateByID(candidateID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.TimeOut)
-	defer cancel()
+		_, err := tx.Exec(ctx, query, candidateID, updateData.CurrentPosition, updateData.Education, updateData.Resume, updateData.Bio)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error updating candidate: %v", err)
+			return err
+		}
 
-	query := `
-    DELETE FROM candidates
-    WHERE public_id = $1
-    `
+		query = `
+		UPDATE users
+		SET
+			first_name = COALESCE($2, first_name),
+			last_name = COALESCE($3, last_name),
+			photo = COALESCE($4, photo)
+		WHERE
+			public_id = $1
+		`
 
-	_, err := r.db.Exec(ctx, query, candidateID)
-	if err != nil {
-		r.logger.Errorf("Error deleting candidate: %v", err)
-		return err
-	}
+		_, err = tx.Exec(ctx, query, candidateID, updateData.FirstName, updateData.LastName, updateData.Photo)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error updating candidate's user data: %v", err)
+			return err
+		}
 
-	return nil
+		if err = insertEvents(ctx, tx, events); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while writing candidate events: %v", err)
+			return err
+		}
+		return nil
+	})
 }
-func (r *candidateRepository) AddSkillsToCandidate(candidateID string, skills []string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.TimeOut)
-	defer cancel()
 
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
-	if err != nil {
-		r.logger.Errorf("Error beginning transaction: %v", err)
-		return err
-	}
+func (r *candidateRepository) DeleteCandidateByID(ctx context.Context, candidateID string, events ...*models.DomainEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
 
-	// Loop over the skills array
-	for _, skillName := range skills {
-		// Check if the skill already exists in the database
-		var skillID int
+	return inTx(ctx, r.db, r.cfg, r.logger, false, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
 		query := `
-		SELECT id FROM skills WHERE name = $1
+		DELETE FROM candidates
+		WHERE public_id = $1
 		`
-		err := tx.QueryRow(ctx, query, skillName).Scan(&skillID)
+
+		_, err := tx.Exec(ctx, query, candidateID)
 		if err != nil {
-			if err == pgx.ErrNoRows {
-				// Skill doesn't exist, so insert it into the database
-				insertQuery := `
-				INSERT INTO skills (name) VALUES ($1)
-				RETURNING id
-				`
-				err = tx.QueryRow(ctx, insertQuery, skillName).Scan(&skillID)
-				if err != nil {
-					r.logger.Errorf("Error inserting new skill: %v", err)
-					tx.Rollback(ctx)
-					return err
-				}
-			} else {
-				r.logger.Errorf("Error checking skill existence: %v", err)
-				tx.Rollback(ctx)
-				return err
-			}
+			logging.FromContext(ctx, r.logger).Errorf("Error deleting candidate: %v", err)
+			return err
 		}
 
-		// Associate the skill with the candidate
-		insertQuery := `
-		INSERT INTO candidate_skills (candidate_id, skill_id) VALUES (
-			(SELECT id FROM candidates WHERE public_id = $1),
-			$2
-		) ON CONFLICT DO NOTHING
-		`
-		_, err = tx.Exec(ctx, insertQuery, candidateID, skillID)
-		if err != nil {
-			r.logger.Errorf("Error adding skill to candidate: %v", err)
-			tx.Rollback(ctx)
+		if err = insertEvents(ctx, tx, events); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while writing candidate events: %v", err)
 			return err
 		}
-	}
+		return nil
+	})
+}
 
-	err = tx.Commit(ctx)
-	if err != nil {
-		r.logger.Errorf("Error committing transaction: %v", err)
+// AddSkillsToCandidate adds the skills to the candidate, creating the ones that do not exist yet
+func (r *candidateRepository) AddSkillsToCandidate(ctx context.Context, candidateID string, skills []string, events ...*models.DomainEvent) (*models.SkillsChange, error) {
+	skills = normalizeSkills(skills)
+	return r.changeSkills(ctx, candidateID, events, func(ctx context.Context, tx dbtx, id int, change *models.SkillsChange) error {
+		added, err := r.addSkills(ctx, tx, id, skills)
+		change.Added = added
 		return err
+	})
+}
+
+// DeleteSkillsFromCandidate removes the skills from the candidate, skills the candidate does not
+// have are ignored. Nothing is changed for a missing candidate.
+func (r *candidateRepository) DeleteSkillsFromCandidate(ctx context.Context, candidateID string, skills []string, events ...*models.DomainEvent) (*models.SkillsChange, error) {
+	skills = normalizeSkills(skills)
+	change, err := r.changeSkills(ctx, candidateID, events, func(ctx context.Context, tx dbtx, id int, change *models.SkillsChange) error {
+		removed, err := r.removeSkills(ctx, tx, id, skills, false)
+		change.Removed = removed
+		return err
+	})
+	if errors.Is(err, models.ErrUserNotFound) {
+		return newSkillsChange(), nil
 	}
+	return change, err
+}
 
-	return nil
+func (r *candidateRepository) ReplaceCandidateSkills(ctx context.Context, candidateID string, skills []string, events ...*models.DomainEvent) (*models.SkillsChange, error) {
+	skills = normalizeSkills(skills)
+	return r.changeSkills(ctx, candidateID, events, func(ctx context.Context, tx dbtx, id int, change *models.SkillsChange) error {
+		added, err := r.addSkills(ctx, tx, id, skills)
+		if err != nil {
+			return err
+		}
+		change.Added = added
+		removed, err := r.removeSkills(ctx, tx, id, skills, true)
+		change.Removed = removed
+		return err
+	})
 }
 
-func (r *candidateRepository) DeleteSkillsFromCandidate(candidateID string, skills []string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.TimeOut)
+// changeSkills runs fn on the skills of the candidate in a transaction, then reads the skills the
+// candidate has afterwards and writes the events if fn changed any. The candidate row is locked,
+// so concurrent changes of the skills of a candidate are applied one after the other.
+func (r *candidateRepository) changeSkills(ctx context.Context, candidateID string, events []*models.DomainEvent, fn func(ctx context.Context, tx dbtx, id int, change *models.SkillsChange) error) (*models.SkillsChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
 	defer cancel()
 
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
-	if err != nil {
-		r.logger.Errorf("Error beginning transaction: %v", err)
-		return err
-	}
+	var change *models.SkillsChange
+	err := inTx(ctx, r.db, r.cfg, r.logger, false, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+		change = newSkillsChange()
 
-	// Rollback the transaction if an error occurs
-	defer func() {
-		if err != nil {
-			tx.Rollback(ctx)
+		var id int
+		query := `SELECT id FROM candidates WHERE public_id = $1 FOR UPDATE`
+		err := tx.QueryRow(ctx, query, candidateID).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrUserNotFound
 		}
-	}()
-
-	// Loop over the skills array
-	for _, skillName := range skills {
-		// Get the skill ID
-		var skillID int
-		skillQuery := `
-		SELECT id FROM skills WHERE name = $1
-		`
-		err := tx.QueryRow(ctx, skillQuery, skillName).Scan(&skillID)
 		if err != nil {
-			if err == pgx.ErrNoRows {
-				r.logger.Warnf("Skill %s does not exist", skillName)
-				continue // Skill doesn't exist, continue to the next skill
-			} else {
-				r.logger.Errorf("Error retrieving skill ID: %v", err)
-				tx.Rollback(ctx)
-				return err
-			}
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while locking candidate: %v", err)
+			return err
 		}
 
-		// Delete the skill from the candidate
-		deleteQuery := `
-		DELETE FROM candidate_skills
-		WHERE candidate_id = (SELECT id FROM candidates WHERE public_id = $1)
-		AND skill_id = $2
-		`
-		_, err = tx.Exec(ctx, deleteQuery, candidateID, skillID)
-		if err != nil {
-			tx.Rollback(ctx)
-			r.logger.Errorf("Error deleting skill from candidate: %v", err)
+		if err := fn(ctx, tx, id, change); err != nil {
 			return err
 		}
+
+		query = `
+			SELECT s.name
+			FROM candidate_skills cs
+			JOIN skills s ON s.id = cs.skill_id
+			WHERE cs.candidate_id = $1
+			ORDER BY s.name COLLATE "C"`
+		if change.Skills, err = queryNames(ctx, tx, query, id); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while getting candidate skills: %v", err)
+			return err
+		}
+
+		if !change.Changed() {
+			return nil
+		}
+		stampSkillsChange(events, change)
+		if err := insertEvents(ctx, tx, events); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while writing candidate events: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return change, nil
+}
 
-	err = tx.Commit(ctx)
+// addSkills upserts the skills and links them to the candidate, and returns the ones the candidate
+// did not have. The upsert updates the existing skills to nothing, so RETURNING yields them too.
+func (r *candidateRepository) addSkills(ctx context.Context, tx dbtx, candidateID int, skills []string) ([]string, error) {
+	if len(skills) == 0 {
+		return []string{}, nil
+	}
+	query := `
+		WITH input AS (
+			SELECT DISTINCT ON (lower(name)) name
+			FROM unnest($2::text[]) WITH ORDINALITY AS t(name, n)
+			ORDER BY lower(name), n
+		), skill AS (
+			INSERT INTO skills (name)
+			SELECT name FROM input
+			ON CONFLICT ((lower(name))) DO UPDATE SET name = skills.name
+			RETURNING id, name
+		), added AS (
+			INSERT INTO candidate_skills (candidate_id, skill_id)
+			SELECT $1, id FROM skill
+			ON CONFLICT DO NOTHING
+			RETURNING skill_id
+		)
+		SELECT skill.name
+		FROM skill
+		JOIN added ON added.skill_id = skill.id
+		ORDER BY skill.name COLLATE "C"`
+
+	added, err := queryNames(ctx, tx, query, candidateID, skills)
 	if err != nil {
-		r.logger.Errorf("Error committing transaction: %v", err)
-		return err
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while adding skills to candidate: %v", err)
+		return nil, err
+	}
+	return added, nil
+}
+
+// removeSkills unlinks the given skills from the candidate, or with except every other skill, and
+// returns the ones removed
+func (r *candidateRepository) removeSkills(ctx context.Context, tx dbtx, candidateID int, skills []string, except bool) ([]string, error) {
+	match := `lower(s.name) = ANY (SELECT lower(name) FROM unnest($2::text[]) AS t(name))`
+	if except {
+		match = `lower(s.name) <> ALL (SELECT lower(name) FROM unnest($2::text[]) AS t(name))`
+	}
+	query := `
+		WITH removed AS (
+			DELETE FROM candidate_skills cs
+			USING skills s
+			WHERE cs.skill_id = s.id AND cs.candidate_id = $1 AND ` + match + `
+			RETURNING s.name
+		)
+		SELECT name FROM removed ORDER BY name COLLATE "C"`
+
+	removed, err := queryNames(ctx, tx, query, candidateID, skills)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while removing skills from candidate: %v", err)
+		return nil, err
 	}
+	return removed, nil
+}
 
-	return nil
+// queryNames runs a query selecting a single text column
+func queryNames(ctx context.Context, tx dbtx, query string, args ...interface{}) ([]string, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
 }
 
-func (r *candidateRepository) Exists(publicID string) (bool, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.TimeOut)
+func (r *candidateRepository) Exists(ctx context.Context, publicID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
 	defer cancel()
 
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM candidates WHERE public_id = $1)`
 
-	err := r.db.QueryRow(ctx, query, publicID).Scan(&exists)
+	err := conn(ctx, r.db).QueryRow(ctx, query, publicID).Scan(&exists)
 	if err != nil {
-		r.logger.Errorf("Error occurred while checking user existence: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while checking user existence: %v", err)
 		return false, err
 	}
 
 	return exists, nil
 }
 
-func (r *candidateRepository) GetInterviewsByPublicID(publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.TimeOut)
+func (r *candidateRepository) GetInterviewsByPublicID(ctx context.Context, publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
 	defer cancel()
 
 	query := `
@@ -387,16 +504,17 @@ func (r *candidateRepository) GetInterviewsByPublicID(publicID string, searchArg
 	INNER JOIN candidates c ON c.id = ui.candidate_id
 	INNER JOIN positions p ON p.id = ui.position_id
 	WHERE c.public_id = $1
-	GROUP BY i.public_id, i.results, p.public_id
+	GROUP BY i.id, i.public_id, i.results, p.public_id
+	ORDER BY i.id
 	LIMIT $2 OFFSET $3;
 `
 	offset := (searchArgs.PageNum - 1) * searchArgs.PageSize
-	rows, err := r.db.Query(ctx, query, publicID, searchArgs.PageSize, offset)
+	rows, err := conn(ctx, r.db).Query(ctx, query, publicID, searchArgs.PageSize, offset)
 	if err != nil {
 		if errors.Is(pgx.ErrNoRows, err) {
 			return nil, 0, nil
 		}
-		r.logger.Errorf("Error occurred while retrieving interview result: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving interview result: %v", err)
 		return nil, 0, err
 	}
 	defer rows.Close()
@@ -410,7 +528,7 @@ func (r *candidateRepository) GetInterviewsByPublicID(publicID string, searchArg
 			&result.PositionPublicID,
 		)
 		if err != nil {
-			r.logger.Errorf("Error occurred while retrieving interview result: %v", err)
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving interview result: %v", err)
 			return nil, 0, err
 		}
 		result.RawResult = resultBytes
@@ -418,7 +536,7 @@ func (r *candidateRepository) GetInterviewsByPublicID(publicID string, searchArg
 	}
 
 	if err := rows.Err(); err != nil {
-		r.logger.Errorf("Error occurred while iterating over interview result for position rows: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over interview result for position rows: %v", err)
 		return nil, 0, err
 	}
 	query = `
@@ -429,12 +547,12 @@ func (r *candidateRepository) GetInterviewsByPublicID(publicID string, searchArg
 	WHERE c.public_id = $1
 `
 	var totalCount int
-	err = r.db.QueryRow(ctx, query, publicID).Scan(&totalCount)
+	err = conn(ctx, r.db).QueryRow(ctx, query, publicID).Scan(&totalCount)
 	if err != nil {
 		if errors.Is(pgx.ErrNoRows, err) {
 			return nil, 0, nil
 		}
-		r.logger.Errorf("Error occurred while retrieving position count: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving position count: %v", err)
 		return nil, 0, err
 	}
 	return res, totalCount, nil
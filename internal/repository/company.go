@@ -2,14 +2,21 @@ package repository
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 
 	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
 	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"go.uber.org/zap"
 )
 
+// companyVerified tells whether the company selected from companies verified one of its domains
+const companyVerified = `EXISTS (
+	SELECT 1 FROM company_domains d WHERE d.company_public_id = companies.public_id AND d.verified_at IS NOT NULL
+)`
+
 type companyRepository struct {
 	db     *pgxpool.Pool
 	cfg    *config.DBConf
@@ -24,18 +31,42 @@ func NewCompanyRepository(db *pgxpool.Pool, cfg *config.DBConf, logger *zap.Suga
 	}
 }
 
-// CreateCompany creates a new company in the database
-func (r *companyRepository) CreateCompany(company *models.Company) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.TimeOut)
+// CreateCompany creates a new company in the database.
+// The events are written in the same transaction and stamped with the public ID of the new company.
+func (r *companyRepository) CreateCompany(ctx context.Context, company *models.Company, events ...*models.DomainEvent) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
 	defer cancel()
+
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error beginning transaction: %v", err)
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
 	var publicID string
 	query := `
 		INSERT INTO companies (name, logo, description)
 		VALUES ($1, $2, $3) RETURNING public_id`
 
-	err := r.db.QueryRow(ctx, query, company.Name, company.Logo, company.Description).Scan(&publicID)
+	err = tx.QueryRow(ctx, query, company.Name, company.Logo, company.Description).Scan(&publicID)
 	if err != nil {
-		r.logger.Errorf("Error occurred while creating company: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while creating company: %v", err)
+		return "", err
+	}
+
+	for _, event := range events {
+		if event != nil {
+			event.AggregatePublicID = publicID
+		}
+	}
+	if err = insertEvents(ctx, tx, events); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while writing company events: %v", err)
+		return "", err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error committing transaction: %v", err)
 		return "", err
 	}
 
@@ -43,8 +74,8 @@ func (r *companyRepository) CreateCompany(company *models.Company) (string, erro
 }
 
 // UpdateCompany updates an existing company in the database
-func (r *companyRepository) UpdateCompany(company *models.Company) error {
-	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.TimeOut)
+func (r *companyRepository) UpdateCompany(ctx context.Context, company *models.Company) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
 	defer cancel()
 
 	query := `
@@ -52,10 +83,10 @@ func (r *companyRepository) UpdateCompany(company *models.Company) error {
 		SET name = COALESCE($2, name), logo = COALESCE($3, logo), description = COALESCE($4, description)
 		WHERE public_id = $1`
 
-	_, err := r.db.Exec(ctx, query, company.PublicID, company.Name, company.Logo, company.Description)
+	_, err := conn(ctx, r.db).Exec(ctx, query, company.PublicID, company.Name, company.Logo, company.Description)
 	if err != nil {
 
-		r.logger.Errorf("Error occurred while updating company: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while updating company: %v", err)
 		return err
 	}
 
@@ -63,24 +94,24 @@ func (r *companyRepository) UpdateCompany(company *models.Company) error {
 }
 
 // GetCompany retrieves a company from the database by its public ID
-func (r *companyRepository) GetCompany(publicID string) (*models.Company, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.TimeOut)
+func (r *companyRepository) GetCompany(ctx context.Context, publicID string) (*models.Company, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
 	defer cancel()
 
 	query := `
-		SELECT id, public_id, name, logo, description
+		SELECT id, public_id, name, logo, description, ` + companyVerified + `
 		FROM companies
 		WHERE public_id = $1`
 
-	row := r.db.QueryRow(ctx, query, publicID)
+	row := conn(ctx, r.db).QueryRow(ctx, query, publicID)
 
 	company := &models.Company{}
-	err := row.Scan(&company.ID, &company.PublicID, &company.Name, &company.Logo, &company.Description)
+	err := row.Scan(&company.ID, &company.PublicID, &company.Name, &company.Logo, &company.Description, &company.Verified)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil // Company not found
 		}
-		r.logger.Errorf("Error occurred while retrieving company: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving company: %v", err)
 		return nil, err
 	}
 
@@ -89,12 +120,12 @@ func (r *companyRepository) GetCompany(publicID string) (*models.Company, error)
 
 // GetCompanies retrieves a list of companies from the database based on search parameters
 // along with the total count of companies that match the search criteria
-func (r *companyRepository) GetCompanies(args *models.SearchArgs) ([]*models.Company, int, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.TimeOut)
+func (r *companyRepository) GetCompanies(ctx context.Context, args *models.SearchArgs) ([]*models.Company, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
 	defer cancel()
 
 	query := `
-		SELECT id, public_id, name, logo, description
+		SELECT id, public_id, name, logo, description, ` + companyVerified + `
 		FROM companies
 		WHERE name ILIKE $1
 		ORDER BY id
@@ -108,9 +139,9 @@ func (r *companyRepository) GetCompanies(args *models.SearchArgs) ([]*models.Com
 	searchPattern := "%" + args.Search + "%"
 	offset := (args.PageNum - 1) * args.PageSize
 
-	rows, err := r.db.Query(ctx, query, searchPattern, args.PageSize, offset)
+	rows, err := conn(ctx, r.db).Query(ctx, query, searchPattern, args.PageSize, offset)
 	if err != nil {
-		r.logger.Errorf("Error occurred while retrieving companies: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving companies: %v", err)
 		return nil, 0, err
 	}
 	defer rows.Close()
@@ -118,9 +149,9 @@ func (r *companyRepository) GetCompanies(args *models.SearchArgs) ([]*models.Com
 	companies := []*models.Company{}
 	for rows.Next() {
 		company := &models.Company{}
-		err := rows.Scan(&company.ID, &company.PublicID, &company.Name, &company.Logo, &company.Description)
+		err := rows.Scan(&company.ID, &company.PublicID, &company.Name, &company.Logo, &company.Description, &company.Verified)
 		if err != nil {
-			r.logger.Errorf("Error occurred while scanning company: %v", err)
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning company: %v", err)
 			return nil, 0, err
 		}
 		companies = append(companies, company)
@@ -128,9 +159,9 @@ func (r *companyRepository) GetCompanies(args *models.SearchArgs) ([]*models.Com
 
 	// Get the total count of companies
 	var totalCount int
-	err = r.db.QueryRow(ctx, countQuery, searchPattern).Scan(&totalCount)
+	err = conn(ctx, r.db).QueryRow(ctx, countQuery, searchPattern).Scan(&totalCount)
 	if err != nil {
-		r.logger.Errorf("Error occurred while retrieving total count of companies: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving total count of companies: %v", err)
 		return nil, 0, err
 	}
 
@@ -138,8 +169,8 @@ func (r *companyRepository) GetCompanies(args *models.SearchArgs) ([]*models.Com
 }
 
 // Exists checks if a company with the given public ID exists in the database
-func (r *companyRepository) Exists(publicID string) (bool, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.TimeOut)
+func (r *companyRepository) Exists(ctx context.Context, publicID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
 	defer cancel()
 
 	query := `
@@ -150,9 +181,9 @@ func (r *companyRepository) Exists(publicID string) (bool, error) {
 		)`
 
 	var exists bool
-	err := r.db.QueryRow(ctx, query, publicID).Scan(&exists)
+	err := conn(ctx, r.db).QueryRow(ctx, query, publicID).Scan(&exists)
 	if err != nil {
-		r.logger.Errorf("Error occurred while checking company existence: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while checking company existence: %v", err)
 		return false, err
 	}
 
@@ -0,0 +1,797 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/repository"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/repository/connection"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
+)
+
+// The conformance suite checks that a storage behaves as the repository interfaces promise, so the
+// memory repositories used for tests and the demo mode cannot drift from the Postgres ones.
+// It runs against the memory storage, and against Postgres when the database URL is set in
+// postgresURLEnv, in a schema created for the run and dropped after it.
+var postgresURLEnv = config.EnvPrefix + "_TEST_DATABASE_URL"
+
+// storage is the storage under test
+type storage struct {
+	repos    *repository.Repository
+	fixtures fixtures
+}
+
+// fixtures creates the records the repositories only read, which other services write in production:
+// users come from the auth service, positions and interviews from the interview service.
+type fixtures interface {
+	CreateCandidate(ctx context.Context, candidate *models.Candidate) (string, error)
+	CreateRecruiter(ctx context.Context, recruiter *models.Recruiter) (string, error)
+	CreatePosition(ctx context.Context, recruiterPublicID string, position *models.Position) (string, error)
+	CreateInterview(ctx context.Context, candidatePublicID, positionPublicID string, results []byte) (string, error)
+}
+
+type testCase struct {
+	name string
+	run  func(ctx context.Context, s *storage, t *expect) error
+}
+
+var cases = []testCase{
+	{"company/create and get", companyCreateAndGet},
+	{"company/update", companyUpdate},
+	{"company/search and pages", companySearch},
+	{"candidate/get", candidateGet},
+	{"candidate/search and pages", candidateSearch},
+	{"candidate/search filters", candidateSearchFilters},
+	{"candidate/update", candidateUpdate},
+	{"candidate/skills", candidateSkills},
+	{"candidate/replace skills", candidateReplaceSkills},
+	{"candidate/interviews", candidateInterviews},
+	{"candidate/delete", candidateDelete},
+	{"recruiter/get", recruiterGet},
+	{"recruiter/filter by company", recruiterFilterByCompany},
+	{"recruiter/interviews", recruiterInterviews},
+}
+
+func TestConformanceMemory(t *testing.T) {
+	store := repository.NewMemoryStore()
+	runConformance(t, &storage{repos: repository.NewMemory(store), fixtures: store})
+}
+
+func TestConformancePostgres(t *testing.T) {
+	url := os.Getenv(postgresURLEnv)
+	if url == "" {
+		t.Skipf("%s is not set", postgresURLEnv)
+	}
+	cfg, err := config.New("../../config/config.yaml", config.ProfileTest, map[string]interface{}{"app.storage": config.StoragePostgres})
+	if err != nil {
+		t.Fatalf("loading config: %v", err)
+	}
+	// the storage itself is checked, without the read cache in front of it
+	cfg.Cache = nil
+
+	// every run creates the schema in a schema of its own, dropped at the end, so the database can be reused
+	poolCfg, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("parsing %s: %v", postgresURLEnv, err)
+	}
+	schemaName := "conformance_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	// public stays on the path for the extensions installed there
+	poolCfg.ConnConfig.RuntimeParams["search_path"] = schemaName + ", public"
+
+	ctx := context.Background()
+	db, err := pgxpool.ConnectConfig(ctx, poolCfg)
+	if err != nil {
+		t.Fatalf("connecting to %s: %v", postgresURLEnv, err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(ctx, "CREATE SCHEMA "+schemaName); err != nil {
+		t.Fatalf("creating schema %s: %v", schemaName, err)
+	}
+	defer func() {
+		if _, err := db.Exec(ctx, "DROP SCHEMA "+schemaName+" CASCADE"); err != nil {
+			t.Errorf("dropping schema %s: %v", schemaName, err)
+		}
+	}()
+	schema, err := os.ReadFile("../../scripts/init.sql")
+	if err != nil {
+		t.Fatalf("reading schema: %v", err)
+	}
+	if _, err := db.Exec(ctx, string(schema)); err != nil {
+		t.Fatalf("creating schema: %v", err)
+	}
+	if _, err := connection.Migrate(db, "../../scripts/migrations", cfg.DB.TimeOut); err != nil {
+		t.Fatalf("applying migrations: %v", err)
+	}
+
+	runConformance(t, &storage{repos: repository.New(db, nil, cfg, zap.NewNop().Sugar()), fixtures: newPostgresFixtures(db, cfg.DB)})
+}
+
+// runConformance runs every case as a subtest. The records are created with unique names and are
+// left behind, so a database shared with other data is not disturbed by them.
+func runConformance(t *testing.T, s *storage) {
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.run(context.Background(), s, &expect{t}); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// expect reports the failed expectations of a case without stopping it, so they are all reported at once
+type expect struct {
+	*testing.T
+}
+
+func (t *expect) that(ok bool, format string, args ...interface{}) {
+	t.Helper()
+	if !ok {
+		t.Errorf(format, args...)
+	}
+}
+
+func (t *expect) equal(what string, got, want interface{}) {
+	t.Helper()
+	t.that(reflect.DeepEqual(got, want), "%s: got %v, want %v", what, show(got), show(want))
+}
+
+func show(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *string:
+		if v == nil {
+			return "<nil>"
+		}
+		return *v
+	case []*string:
+		res := make([]interface{}, len(v))
+		for i := range v {
+			res[i] = show(v[i])
+		}
+		return res
+	}
+	return v
+}
+
+// unique returns a word no other run uses, to find the records of this run
+func unique() string {
+	return "cf" + strings.ReplaceAll(uuid.NewString(), "-", "")[:12]
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func strs(ss ...string) []*string {
+	res := make([]*string, 0, len(ss))
+	for _, s := range ss {
+		res = append(res, ptr(s))
+	}
+	return res
+}
+
+func sorted(ss ...string) []string {
+	sort.Strings(ss)
+	return ss
+}
+
+func args(search string, pageNum, pageSize int) *models.SearchArgs {
+	return &models.SearchArgs{Search: search, PageNum: pageNum, PageSize: pageSize}
+}
+
+func companyCreateAndGet(ctx context.Context, s *storage, t *expect) error {
+	event := &models.DomainEvent{Type: "company.created"}
+	company := &models.Company{Name: "Company " + unique(), Logo: "https://example.com/logo.png", Description: "Makes things"}
+	publicID, err := s.repos.CompanyRepository.CreateCompany(ctx, company, event)
+	if err != nil {
+		return err
+	}
+	t.that(publicID != "", "CreateCompany returned an empty public ID")
+	t.equal("event aggregate", event.AggregatePublicID, publicID)
+
+	exists, err := s.repos.CompanyRepository.Exists(ctx, publicID)
+	if err != nil {
+		return err
+	}
+	t.that(exists, "created company does not exist")
+
+	got, err := s.repos.CompanyRepository.GetCompany(ctx, publicID)
+	if err != nil {
+		return err
+	}
+	if got == nil {
+		return errors.New("GetCompany returned no company")
+	}
+	t.that(got.ID > 0, "company ID is %d, want a positive ID", got.ID)
+	t.equal("company", models.Company{PublicID: got.PublicID, Name: got.Name, Logo: got.Logo, Description: got.Description},
+		models.Company{PublicID: publicID, Name: company.Name, Logo: company.Logo, Description: company.Description})
+
+	missing := uuid.NewString()
+	got, err = s.repos.CompanyRepository.GetCompany(ctx, missing)
+	t.that(got == nil && err == nil, "GetCompany of a missing company returned %v, %v, want nil, nil", got, err)
+	exists, err = s.repos.CompanyRepository.Exists(ctx, missing)
+	t.that(!exists && err == nil, "Exists of a missing company returned %v, %v", exists, err)
+	return nil
+}
+
+func companyUpdate(ctx context.Context, s *storage, t *expect) error {
+	publicID, err := s.repos.CompanyRepository.CreateCompany(ctx, &models.Company{Name: "Company " + unique(), Logo: "https://example.com/logo.png", Description: "Old"})
+	if err != nil {
+		return err
+	}
+	// every field is written, an empty logo removes it
+	update := &models.Company{PublicID: publicID, Name: "Renamed " + unique(), Description: "New"}
+	if err := s.repos.CompanyRepository.UpdateCompany(ctx, update); err != nil {
+		return err
+	}
+	got, err := s.repos.CompanyRepository.GetCompany(ctx, publicID)
+	if err != nil {
+		return err
+	}
+	if got == nil {
+		return errors.New("GetCompany returned no company")
+	}
+	t.equal("updated company", []string{got.Name, got.Logo, got.Description}, []string{update.Name, "", "New"})
+
+	err = s.repos.CompanyRepository.UpdateCompany(ctx, &models.Company{PublicID: uuid.NewString(), Name: "Nobody"})
+	t.that(err == nil, "UpdateCompany of a missing company returned %v, want nil", err)
+	return nil
+}
+
+func companySearch(ctx context.Context, s *storage, t *expect) error {
+	word := unique()
+	var ids []string
+	for _, name := range []string{"Alpha " + word, "beta " + strings.ToUpper(word), "Gamma " + word + " inc"} {
+		publicID, err := s.repos.CompanyRepository.CreateCompany(ctx, &models.Company{Name: name})
+		if err != nil {
+			return err
+		}
+		ids = append(ids, publicID)
+	}
+
+	var got []string
+	for pageNum := 1; pageNum <= 3; pageNum++ {
+		companies, total, err := s.repos.CompanyRepository.GetCompanies(ctx, args(word, pageNum, 2))
+		if err != nil {
+			return err
+		}
+		t.equal(fmt.Sprintf("total of page %d", pageNum), total, 3)
+		t.that(len(companies) <= 2, "page %d has %d companies, the page size is 2", pageNum, len(companies))
+		for _, c := range companies {
+			got = append(got, c.PublicID)
+		}
+	}
+	t.equal("companies in creation order, case insensitive", got, ids)
+
+	// _ matches any character, like in SQL
+	companies, total, err := s.repos.CompanyRepository.GetCompanies(ctx, args(word[:3]+"_"+word[4:], 1, 10))
+	if err != nil {
+		return err
+	}
+	t.that(total == 3 && len(companies) == 3, "wildcard search found %d companies, total %d, want 3", len(companies), total)
+
+	companies, total, err = s.repos.CompanyRepository.GetCompanies(ctx, args(unique(), 1, 10))
+	if err != nil {
+		return err
+	}
+	t.that(companies != nil && len(companies) == 0 && total == 0, "search without matches returned %v, total %d, want an empty list", companies, total)
+	return nil
+}
+
+func newCandidate(ctx context.Context, s *storage, firstName, lastName string) (string, error) {
+	return s.fixtures.CreateCandidate(ctx, &models.Candidate{
+		FirstName:       ptr(firstName),
+		LastName:        ptr(lastName),
+		CurrentPosition: ptr("Developer"),
+		Education:       ptr("University"),
+		Resume:          ptr("https://example.com/cv.pdf"),
+		Bio:             ptr("Bio"),
+		Photo:           ptr("https://example.com/photo.png"),
+	})
+}
+
+func candidateGet(ctx context.Context, s *storage, t *expect) error {
+	lastName := unique()
+	publicID, err := newCandidate(ctx, s, "Ada", lastName)
+	if err != nil {
+		return err
+	}
+	exists, err := s.repos.CandidateRepository.Exists(ctx, publicID)
+	if err != nil {
+		return err
+	}
+	t.that(exists, "created candidate does not exist")
+
+	got, err := s.repos.CandidateRepository.GetCandidateByPublicID(ctx, publicID)
+	if err != nil {
+		return err
+	}
+	t.equal("candidate", got, &models.Candidate{
+		PublicID:        ptr(publicID),
+		FirstName:       ptr("Ada"),
+		LastName:        ptr(lastName),
+		CurrentPosition: ptr("Developer"),
+		Resume:          ptr("https://example.com/cv.pdf"),
+		Bio:             ptr("Bio"),
+		Skills:          []*string{},
+		Photo:           ptr("https://example.com/photo.png"),
+		Education:       ptr("University"),
+	})
+
+	missing := uuid.NewString()
+	_, err = s.repos.CandidateRepository.GetCandidateByPublicID(ctx, missing)
+	t.that(errors.Is(err, models.ErrUserNotFound), "GetCandidateByPublicID of a missing candidate returned %v, want %v", err, models.ErrUserNotFound)
+	exists, err = s.repos.CandidateRepository.Exists(ctx, missing)
+	t.that(!exists && err == nil, "Exists of a missing candidate returned %v, %v", exists, err)
+	return nil
+}
+
+func candidateSearch(ctx context.Context, s *storage, t *expect) error {
+	word := unique()
+	var ids []string
+	for _, name := range [][2]string{{"Ann", word}, {word, "Lee"}, {"Bob", "Mc" + strings.ToUpper(word)}} {
+		publicID, err := newCandidate(ctx, s, name[0], name[1])
+		if err != nil {
+			return err
+		}
+		ids = append(ids, publicID)
+	}
+	if _, err := newCandidate(ctx, s, "Someone", "Else"); err != nil {
+		return err
+	}
+	if _, err := s.repos.CandidateRepository.AddSkillsToCandidate(ctx, ids[0], []string{"SQL", "Go"}); err != nil {
+		return err
+	}
+
+	var got []string
+	for pageNum := 1; pageNum <= 3; pageNum++ {
+		candidates, total, err := s.repos.CandidateRepository.GetCandidatesBySearch(ctx, args(word, pageNum, 2))
+		if err != nil {
+			return err
+		}
+		t.equal(fmt.Sprintf("total of page %d", pageNum), total, 3)
+		t.that(len(candidates) <= 2, "page %d has %d candidates, the page size is 2", pageNum, len(candidates))
+		for _, c := range candidates {
+			if c.PublicID == nil {
+				return errors.New("candidate without public ID")
+			}
+			got = append(got, *c.PublicID)
+			switch *c.PublicID {
+			case ids[0]:
+				t.equal("skills of a candidate in the search", c.Skills, strs("Go", "SQL"))
+			default:
+				t.equal("skills of a candidate without skills in the search", c.Skills, []*string{})
+			}
+		}
+	}
+	t.equal("candidates in creation order, by first or last name, case insensitive", got, ids)
+
+	candidates, total, err := s.repos.CandidateRepository.GetCandidatesBySearch(ctx, args(unique(), 1, 10))
+	if err != nil {
+		return err
+	}
+	t.that(candidates != nil && len(candidates) == 0 && total == 0, "search without matches returned %v, total %d, want an empty list", candidates, total)
+	return nil
+}
+
+func candidateSearchFilters(ctx context.Context, s *storage, t *expect) error {
+	word := unique()
+	var ids []string
+	for _, firstName := range []string{"Ann", "Bob", "Cid"} {
+		publicID, err := newCandidate(ctx, s, firstName, word)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, publicID)
+	}
+	if _, err := s.repos.CandidateRepository.AddSkillsToCandidate(ctx, ids[0], []string{"Go", "SQL"}); err != nil {
+		return err
+	}
+	if _, err := s.repos.CandidateRepository.AddSkillsToCandidate(ctx, ids[1], []string{"Go"}); err != nil {
+		return err
+	}
+	if err := s.repos.CandidateRepository.UpdateCandidateByID(ctx, ids[2], &models.Candidate{CurrentPosition: ptr("Senior Go Engineer"), Education: ptr("MIT")}); err != nil {
+		return err
+	}
+
+	for _, c := range []struct {
+		what    string
+		filters *models.CandidateFilters
+		want    []string
+	}{
+		{"no filters", nil, ids},
+		{"every skill, case insensitive", &models.CandidateFilters{Skills: []string{"go", "sql"}}, ids[:1]},
+		{"a skill", &models.CandidateFilters{Skills: []string{"GO"}}, ids[:2]},
+		{"current position contained, case insensitive", &models.CandidateFilters{CurrentPosition: "go eng"}, ids[2:]},
+		{"education and current position", &models.CandidateFilters{CurrentPosition: "developer", Education: "univ"}, ids[:2]},
+		{"no match", &models.CandidateFilters{Skills: []string{"Go"}, Education: "MIT"}, nil},
+	} {
+		searchArgs := args(word, 1, 10)
+		searchArgs.Filters = c.filters
+		candidates, total, err := s.repos.CandidateRepository.GetCandidatesBySearch(ctx, searchArgs)
+		if err != nil {
+			return err
+		}
+		var got []string
+		for _, candidate := range candidates {
+			got = append(got, *candidate.PublicID)
+		}
+		t.equal(c.what, got, c.want)
+		t.equal(c.what+", total", total, len(c.want))
+	}
+	return nil
+}
+
+func candidateUpdate(ctx context.Context, s *storage, t *expect) error {
+	publicID, err := newCandidate(ctx, s, "Grace", unique())
+	if err != nil {
+		return err
+	}
+	lastName := unique()
+	// the fields left nil are kept
+	if err := s.repos.CandidateRepository.UpdateCandidateByID(ctx, publicID, &models.Candidate{LastName: ptr(lastName), Bio: ptr(""), Education: ptr("PhD")}); err != nil {
+		return err
+	}
+	got, err := s.repos.CandidateRepository.GetCandidateByPublicID(ctx, publicID)
+	if err != nil {
+		return err
+	}
+	t.equal("updated candidate", []*string{got.FirstName, got.LastName, got.CurrentPosition, got.Education, got.Resume, got.Bio, got.Photo},
+		strs("Grace", lastName, "Developer", "PhD", "https://example.com/cv.pdf", "", "https://example.com/photo.png"))
+
+	candidates, total, err := s.repos.CandidateRepository.GetCandidatesBySearch(ctx, args(lastName, 1, 10))
+	if err != nil {
+		return err
+	}
+	t.that(total == 1 && len(candidates) == 1, "search by the new last name found %d candidates, total %d, want 1", len(candidates), total)
+
+	err = s.repos.CandidateRepository.UpdateCandidateByID(ctx, uuid.NewString(), &models.Candidate{Bio: ptr("Nobody")})
+	t.that(err == nil, "UpdateCandidateByID of a missing candidate returned %v, want nil", err)
+	return nil
+}
+
+func candidateSkills(ctx context.Context, s *storage, t *expect) error {
+	publicID, err := newCandidate(ctx, s, "Linus", unique())
+	if err != nil {
+		return err
+	}
+	skill, other := unique(), unique()
+	// names are trimmed and matched regardless of case, the first spelling is kept and blank names are dropped
+	change, err := s.repos.CandidateRepository.AddSkillsToCandidate(ctx, publicID, []string{" " + skill + " ", strings.ToUpper(skill), other, "  "})
+	if err != nil {
+		return err
+	}
+	t.equal("skills after adding", change.Skills, sorted(skill, other))
+	t.equal("added skills", change.Added, sorted(skill, other))
+	t.equal("skills removed by adding", change.Removed, []string{})
+
+	// adding a skill the candidate has changes nothing
+	change, err = s.repos.CandidateRepository.AddSkillsToCandidate(ctx, publicID, []string{strings.ToUpper(other)})
+	if err != nil {
+		return err
+	}
+	t.equal("skills added twice", change.Added, []string{})
+	got, err := s.repos.CandidateRepository.GetCandidateByPublicID(ctx, publicID)
+	if err != nil {
+		return err
+	}
+	t.equal("skills of the candidate", got.Skills, strs(sorted(skill, other)...))
+
+	// unknown skills are ignored
+	change, err = s.repos.CandidateRepository.DeleteSkillsFromCandidate(ctx, publicID, []string{strings.ToUpper(skill), unique()})
+	if err != nil {
+		return err
+	}
+	t.equal("removed skills", change.Removed, []string{skill})
+	t.equal("skills left", change.Skills, []string{other})
+	got, err = s.repos.CandidateRepository.GetCandidateByPublicID(ctx, publicID)
+	if err != nil {
+		return err
+	}
+	t.equal("skills of the candidate after removing", got.Skills, strs(other))
+
+	_, err = s.repos.CandidateRepository.DeleteSkillsFromCandidate(ctx, uuid.NewString(), []string{other})
+	t.that(err == nil, "DeleteSkillsFromCandidate of a missing candidate returned %v, want nil", err)
+	_, err = s.repos.CandidateRepository.AddSkillsToCandidate(ctx, uuid.NewString(), []string{other})
+	t.that(errors.Is(err, models.ErrUserNotFound), "AddSkillsToCandidate of a missing candidate returned %v, want %v", err, models.ErrUserNotFound)
+	return nil
+}
+
+func candidateReplaceSkills(ctx context.Context, s *storage, t *expect) error {
+	publicID, err := newCandidate(ctx, s, "Grace", unique())
+	if err != nil {
+		return err
+	}
+	kept, dropped, added := unique(), unique(), unique()
+	if _, err := s.repos.CandidateRepository.AddSkillsToCandidate(ctx, publicID, []string{kept, dropped}); err != nil {
+		return err
+	}
+
+	change, err := s.repos.CandidateRepository.ReplaceCandidateSkills(ctx, publicID, []string{strings.ToUpper(kept), added, added})
+	if err != nil {
+		return err
+	}
+	t.equal("skills after replacing", change.Skills, sorted(kept, added))
+	t.equal("skills added by replacing", change.Added, []string{added})
+	t.equal("skills removed by replacing", change.Removed, []string{dropped})
+	got, err := s.repos.CandidateRepository.GetCandidateByPublicID(ctx, publicID)
+	if err != nil {
+		return err
+	}
+	t.equal("skills of the candidate", got.Skills, strs(sorted(kept, added)...))
+
+	// replacing with the same skills changes nothing
+	change, err = s.repos.CandidateRepository.ReplaceCandidateSkills(ctx, publicID, []string{added, kept})
+	if err != nil {
+		return err
+	}
+	t.that(!change.Changed(), "replacing with the same skills added %v and removed %v", change.Added, change.Removed)
+
+	// an empty list removes every skill
+	change, err = s.repos.CandidateRepository.ReplaceCandidateSkills(ctx, publicID, nil)
+	if err != nil {
+		return err
+	}
+	t.equal("skills after clearing", change.Skills, []string{})
+	t.equal("skills removed by clearing", change.Removed, sorted(kept, added))
+
+	_, err = s.repos.CandidateRepository.ReplaceCandidateSkills(ctx, uuid.NewString(), []string{kept})
+	t.that(errors.Is(err, models.ErrUserNotFound), "ReplaceCandidateSkills of a missing candidate returned %v, want %v", err, models.ErrUserNotFound)
+	return nil
+}
+
+// newInterviews creates a company, a recruiter with a position and the interviews of the candidates for it
+func newInterviews(ctx context.Context, s *storage, candidates ...string) (recruiterID, positionID string, interviewIDs []string, err error) {
+	companyID, err := s.repos.CompanyRepository.CreateCompany(ctx, &models.Company{Name: "Company " + unique()})
+	if err != nil {
+		return "", "", nil, err
+	}
+	recruiterID, err = s.fixtures.CreateRecruiter(ctx, &models.Recruiter{CompanyPublicID: companyID, FirstName: "Rita", LastName: unique()})
+	if err != nil {
+		return "", "", nil, err
+	}
+	positionID, err = s.fixtures.CreatePosition(ctx, recruiterID, &models.Position{Name: "Developer", Status: 1})
+	if err != nil {
+		return "", "", nil, err
+	}
+	for i, candidate := range candidates {
+		interviewID, err := s.fixtures.CreateInterview(ctx, candidate, positionID, []byte(fmt.Sprintf(`{"questions":[],"score":%d}`, 50+i)))
+		if err != nil {
+			return "", "", nil, err
+		}
+		interviewIDs = append(interviewIDs, interviewID)
+	}
+	return recruiterID, positionID, interviewIDs, nil
+}
+
+// checkInterviews pages through the interviews two at a time
+func checkInterviews(t *expect, what string, wantIDs []string, positionID string, get func(searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error)) error {
+	var got []string
+	for pageNum := 1; pageNum <= (len(wantIDs)+1)/2+1; pageNum++ {
+		res, total, err := get(args("", pageNum, 2))
+		if err != nil {
+			return err
+		}
+		t.equal(fmt.Sprintf("%s total of page %d", what, pageNum), total, len(wantIDs))
+		for _, r := range res {
+			got = append(got, r.PublicID)
+			t.equal(what+" position", r.PositionPublicID, positionID)
+			var result models.Result
+			t.that(json.Unmarshal(r.RawResult, &result) == nil, "%s results of %s are not valid JSON: %s", what, r.PublicID, r.RawResult)
+		}
+	}
+	t.equal(what+" in creation order", got, wantIDs)
+	return nil
+}
+
+func candidateInterviews(ctx context.Context, s *storage, t *expect) error {
+	publicID, err := newCandidate(ctx, s, "Alan", unique())
+	if err != nil {
+		return err
+	}
+	other, err := newCandidate(ctx, s, "Other", unique())
+	if err != nil {
+		return err
+	}
+	_, positionID, interviewIDs, err := newInterviews(ctx, s, publicID, other, publicID, publicID)
+	if err != nil {
+		return err
+	}
+	want := []string{interviewIDs[0], interviewIDs[2], interviewIDs[3]}
+	err = checkInterviews(t, "candidate interviews", want, positionID, func(searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error) {
+		return s.repos.CandidateRepository.GetInterviewsByPublicID(ctx, publicID, searchArgs)
+	})
+	if err != nil {
+		return err
+	}
+
+	got, err := s.repos.CandidateRepository.GetCandidateByPublicID(ctx, publicID)
+	if err != nil {
+		return err
+	}
+	var gotIDs []string
+	for _, i := range got.Interviews {
+		gotIDs = append(gotIDs, i.PublicID)
+	}
+	sortedIDs := append([]string(nil), want...)
+	sort.Strings(sortedIDs)
+	t.equal("interviews of the candidate, sorted", gotIDs, sortedIDs)
+	return nil
+}
+
+func candidateDelete(ctx context.Context, s *storage, t *expect) error {
+	lastName := unique()
+	publicID, err := newCandidate(ctx, s, "Dennis", lastName)
+	if err != nil {
+		return err
+	}
+	if _, err := s.repos.CandidateRepository.AddSkillsToCandidate(ctx, publicID, []string{"C"}); err != nil {
+		return err
+	}
+	if _, _, _, err := newInterviews(ctx, s, publicID); err != nil {
+		return err
+	}
+	if err := s.repos.CandidateRepository.DeleteCandidateByID(ctx, publicID); err != nil {
+		return err
+	}
+
+	exists, err := s.repos.CandidateRepository.Exists(ctx, publicID)
+	if err != nil {
+		return err
+	}
+	t.that(!exists, "deleted candidate still exists")
+	_, err = s.repos.CandidateRepository.GetCandidateByPublicID(ctx, publicID)
+	t.that(errors.Is(err, models.ErrUserNotFound), "GetCandidateByPublicID of a deleted candidate returned %v, want %v", err, models.ErrUserNotFound)
+	_, total, err := s.repos.CandidateRepository.GetCandidatesBySearch(ctx, args(lastName, 1, 10))
+	if err != nil {
+		return err
+	}
+	t.equal("candidates found after the delete", total, 0)
+	_, total, err = s.repos.CandidateRepository.GetInterviewsByPublicID(ctx, publicID, args("", 1, 10))
+	if err != nil {
+		return err
+	}
+	t.equal("interviews left after the delete", total, 0)
+
+	err = s.repos.CandidateRepository.DeleteCandidateByID(ctx, uuid.NewString())
+	t.that(err == nil, "DeleteCandidateByID of a missing candidate returned %v, want nil", err)
+	return nil
+}
+
+func recruiterGet(ctx context.Context, s *storage, t *expect) error {
+	company := &models.Company{Name: "Company " + unique(), Logo: "https://example.com/logo.png", Description: "Hires"}
+	companyID, err := s.repos.CompanyRepository.CreateCompany(ctx, company)
+	if err != nil {
+		return err
+	}
+	lastName := unique()
+	publicID, err := s.fixtures.CreateRecruiter(ctx, &models.Recruiter{CompanyPublicID: companyID, FirstName: "Rita", LastName: lastName, Photo: "https://example.com/rita.png"})
+	if err != nil {
+		return err
+	}
+	var positions []models.Position
+	for i, name := range []string{"Backend", "Frontend"} {
+		positionID, err := s.fixtures.CreatePosition(ctx, publicID, &models.Position{Name: name, Status: i})
+		if err != nil {
+			return err
+		}
+		positions = append(positions, models.Position{PublicID: positionID, Name: name, Status: i})
+	}
+
+	exists, err := s.repos.RecruiterRepository.Exists(ctx, publicID)
+	if err != nil {
+		return err
+	}
+	t.that(exists, "created recruiter does not exist")
+
+	got, err := s.repos.RecruiterRepository.GetRecruiter(ctx, publicID)
+	if err != nil {
+		return err
+	}
+	// the company only has the fields shown with the recruiter
+	t.equal("recruiter", got, &models.Recruiter{
+		PublicID:        publicID,
+		CompanyPublicID: companyID,
+		Role:            models.RoleRecruiter,
+		FirstName:       "Rita",
+		LastName:        lastName,
+		Photo:           "https://example.com/rita.png",
+		Company:         &models.Company{PublicID: companyID, Name: company.Name, Description: company.Description},
+		Positions:       positions,
+	})
+
+	companyPublicID, err := s.repos.RecruiterRepository.GetCompanyPublicID(ctx, publicID)
+	if err != nil {
+		return err
+	}
+	t.equal("company public ID", companyPublicID, companyID)
+
+	membership, err := s.repos.RecruiterRepository.GetMembership(ctx, publicID)
+	if err != nil {
+		return err
+	}
+	t.equal("membership", membership, &models.Membership{CompanyPublicID: companyID, Role: models.RoleRecruiter})
+
+	// a recruiter removed from its company is shown without it
+	loneID, err := s.fixtures.CreateRecruiter(ctx, &models.Recruiter{FirstName: "Lone", LastName: unique()})
+	if err != nil {
+		return err
+	}
+	lone, err := s.repos.RecruiterRepository.GetRecruiter(ctx, loneID)
+	if err != nil {
+		return err
+	}
+	t.that(lone.CompanyPublicID == "" && lone.Company == nil, "recruiter without a company has company %q, %+v", lone.CompanyPublicID, lone.Company)
+	_, err = s.repos.RecruiterRepository.GetCompanyPublicID(ctx, loneID)
+	t.that(errors.Is(err, models.ErrNoCompany), "GetCompanyPublicID of a recruiter without a company returned %v, want %v", err, models.ErrNoCompany)
+
+	missing := uuid.NewString()
+	_, err = s.repos.RecruiterRepository.GetRecruiter(ctx, missing)
+	t.that(errors.Is(err, models.ErrUserNotFound), "GetRecruiter of a missing recruiter returned %v, want %v", err, models.ErrUserNotFound)
+	_, err = s.repos.RecruiterRepository.GetCompanyPublicID(ctx, missing)
+	t.that(errors.Is(err, models.ErrUserNotFound), "GetCompanyPublicID of a missing recruiter returned %v, want %v", err, models.ErrUserNotFound)
+	exists, err = s.repos.RecruiterRepository.Exists(ctx, missing)
+	t.that(!exists && err == nil, "Exists of a missing recruiter returned %v, %v", exists, err)
+	return nil
+}
+
+func recruiterFilterByCompany(ctx context.Context, s *storage, t *expect) error {
+	var companies []string
+	for i := 0; i < 2; i++ {
+		companyID, err := s.repos.CompanyRepository.CreateCompany(ctx, &models.Company{Name: "Company " + unique()})
+		if err != nil {
+			return err
+		}
+		companies = append(companies, companyID)
+	}
+	var recruiters []string
+	for _, companyID := range []string{companies[0], companies[1], companies[0]} {
+		publicID, err := s.fixtures.CreateRecruiter(ctx, &models.Recruiter{CompanyPublicID: companyID, FirstName: "Rita", LastName: unique()})
+		if err != nil {
+			return err
+		}
+		recruiters = append(recruiters, publicID)
+	}
+
+	got, err := s.repos.RecruiterRepository.FilterByCompany(ctx, companies[0], []string{recruiters[2], uuid.NewString(), recruiters[1], recruiters[0], recruiters[2]})
+	if err != nil {
+		return err
+	}
+	t.equal("recruiters of the company, once each in creation order", got, []string{recruiters[0], recruiters[2]})
+
+	got, err = s.repos.RecruiterRepository.FilterByCompany(ctx, companies[1], []string{recruiters[0]})
+	if err != nil {
+		return err
+	}
+	t.that(got != nil && len(got) == 0, "FilterByCompany without matches returned %v, want an empty list", got)
+	return nil
+}
+
+func recruiterInterviews(ctx context.Context, s *storage, t *expect) error {
+	var candidates []string
+	for i := 0; i < 3; i++ {
+		publicID, err := newCandidate(ctx, s, "Candidate", unique())
+		if err != nil {
+			return err
+		}
+		candidates = append(candidates, publicID)
+	}
+	recruiterID, positionID, interviewIDs, err := newInterviews(ctx, s, candidates...)
+	if err != nil {
+		return err
+	}
+	return checkInterviews(t, "recruiter interviews", interviewIDs, positionID, func(searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error) {
+		return s.repos.RecruiterRepository.GetInterviewsByPublicID(ctx, recruiterID, searchArgs)
+	})
+}
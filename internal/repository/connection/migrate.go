@@ -0,0 +1,85 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// MigrationsDir holds the scripts that bring databases created from an older scripts/init.sql up to date.
+const MigrationsDir = "./scripts/migrations"
+
+// migrationsLock is the advisory lock key that keeps replicas starting together from applying the same script twice.
+const migrationsLock = 7163904215
+
+// Migrate applies the .sql scripts of dir that have not been applied yet, in the order of their names,
+// each in its own transaction, and records them in schema_migrations. init.sql creates the latest schema
+// on a fresh database, so every script must also be a no-op there.
+func Migrate(db *pgxpool.Pool, dir string, timeout time.Duration) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	_, err = db.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		name VARCHAR(255) PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []string
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".sql")
+		ok, err := migrate(db, name, file, timeout)
+		if err != nil {
+			return applied, fmt.Errorf("migration %s: %w", name, err)
+		}
+		if ok {
+			applied = append(applied, name)
+		}
+	}
+	return applied, nil
+}
+
+// migrate applies one script unless it was applied before, and reports whether it did.
+func migrate(db *pgxpool.Pool, name, file string, timeout time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	applied := false
+	err := db.BeginFunc(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationsLock); err != nil {
+			return err
+		}
+		var done bool
+		err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE name = $1)`, name).Scan(&done)
+		if err != nil || done {
+			return err
+		}
+		script, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec(ctx, string(script)); err != nil {
+			return err
+		}
+		if _, err = tx.Exec(ctx, `INSERT INTO schema_migrations (name) VALUES ($1)`, name); err != nil {
+			return err
+		}
+		applied = true
+		return nil
+	})
+	return applied, err
+}
@@ -4,25 +4,32 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"os"
 
 	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/tracing"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
 )
 
-func NewPostgresDB(cfg *config.DBConf) (*pgxpool.Pool, error) {
+// NewPostgresDB connects to the database in the DATABASE_URL environment variable, or the one configured,
+// and applies the schema script and the pending migrations. The connection URL holds the password, so it is never logged.
+func NewPostgresDB(cfg *config.DBConf, log *zap.SugaredLogger) (*pgxpool.Pool, error) {
 	dbURI, ok := os.LookupEnv("DATABASE_URL")
 	if !ok {
-		log.Println("Couldn't get database url. Continuing with config")
+		log.Info("Couldn't get database url. Continuing with config")
 		dbURI = fmt.Sprintf("postgresql://%s:%s@%s:%v/%s?sslmode=%s", cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.DBName, cfg.SSLMode)
-	} else {
-		log.Println("Database url: ", dbURI)
 	}
-	log.Println(dbURI)
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.TimeOut)
 	defer cancel()
-	pool, err := pgxpool.Connect(ctx, dbURI)
+	poolConfig, err := pgxpool.ParseConfig(dbURI)
+	if err != nil {
+		return nil, err
+	}
+	poolConfig.ConnConfig.Logger = tracing.NewQueryLogger()
+	poolConfig.ConnConfig.LogLevel = pgx.LogLevelInfo
+	pool, err := pgxpool.ConnectConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -34,13 +41,21 @@ func NewPostgresDB(cfg *config.DBConf) (*pgxpool.Pool, error) {
 
 	c, ioErr := ioutil.ReadFile("./scripts/init.sql")
 	if ioErr != nil {
-		log.Println(ioErr)
+		log.Warnf("Couldn't read schema script: %v", ioErr)
 	} else {
 		sql := string(c)
 		_, err = pool.Exec(ctx, sql)
 		if err != nil {
-			log.Println(err)
+			log.Warnf("Couldn't apply schema script: %v", err)
 		}
 	}
+
+	applied, err := Migrate(pool, MigrationsDir, cfg.TimeOut)
+	if err != nil {
+		log.Warnf("Couldn't apply migrations: %v", err)
+	}
+	for _, name := range applied {
+		log.Infof("Applied migration %s", name)
+	}
 	return pool, nil
 }
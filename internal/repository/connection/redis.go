@@ -0,0 +1,23 @@
+package connection
+
+import (
+	"fmt"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/go-redis/redis/v7"
+	"go.uber.org/zap"
+)
+
+// NewRedisClient creates a Redis client. Redis is optional for this service,
+// so a failed ping is only logged and the client keeps reconnecting on use.
+func NewRedisClient(cfg *config.RedisConf, log *zap.SugaredLogger) *redis.Client {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+	if err := client.Ping().Err(); err != nil {
+		log.Warnf("Couldn't connect to redis: %v", err)
+	}
+	return client
+}
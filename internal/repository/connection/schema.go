@@ -0,0 +1,36 @@
+package connection
+
+// SchemaTables are the tables created by scripts/init.sql. The service is not ready until all of them exist,
+// so a table added to the script must be listed here as well, and created by a migration for existing databases.
+var SchemaTables = []string{
+	"users",
+	"candidates",
+	"recruiters",
+	"companies",
+	"positions",
+	"skills",
+	"areas",
+	"interviews",
+	"videos",
+	"auth",
+	"position_skills",
+	"candidate_skills",
+	"user_interviews",
+	"shortlists",
+	"shortlist_candidates",
+	"recruiter_notes",
+	"recruiter_note_revisions",
+	"recruiter_note_mentions",
+	"saved_searches",
+	"saved_search_matches",
+	"saved_search_alerts",
+	"notification_outbox",
+	"notification_deliveries",
+	"notification_preferences",
+	"domain_event_outbox",
+	"company_webhooks",
+	"webhook_deliveries",
+	"company_invitations",
+	"company_join_requests",
+	"company_domains",
+}
@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
+)
+
+// domainColumns are the columns of models.CompanyDomain, its status is derived from verified_at
+const domainColumns = `public_id, company_public_id, domain, method,
+	CASE WHEN verified_at IS NOT NULL THEN 'verified' ELSE 'pending' END,
+	COALESCE(dns_token, ''), COALESCE(email, ''), created_by, created_at, verified_at`
+
+// domainRepository represents the repository for the email domains of companies.
+type domainRepository struct {
+	db     *pgxpool.Pool
+	cfg    *config.DBConf
+	logger *zap.SugaredLogger
+}
+
+// NewDomainRepository creates a new instance of domainRepository.
+func NewDomainRepository(db *pgxpool.Pool, cfg *config.DBConf, logger *zap.SugaredLogger) DomainRepository {
+	return &domainRepository{
+		db:     db,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// CreateDomain claims the domain for the company, or restarts the verification of a domain the company
+// claimed already and has not verified yet. A domain it verified already is returned as it is. The email
+// with the code is queued with the payload for domains verified by email.
+func (r *domainRepository) CreateDomain(ctx context.Context, domain *models.CompanyDomain, codeHash string, codeExpiresAt *time.Time, payload map[string]interface{}) (*models.CompanyDomain, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	var res *models.CompanyDomain
+	err := inTx(ctx, r.db, r.cfg, r.logger, false, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+		var taken bool
+		query := `
+			SELECT EXISTS (
+				SELECT 1 FROM company_domains
+				WHERE domain = $2 AND company_public_id <> $1 AND verified_at IS NOT NULL
+			)`
+		if err := tx.QueryRow(ctx, query, domain.CompanyPublicID, domain.Domain).Scan(&taken); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while checking verified domains: %v", err)
+			return err
+		}
+		if taken {
+			return models.ErrDomainTaken
+		}
+
+		query = `
+			INSERT INTO company_domains (company_public_id, domain, method, dns_token, email, code_hash, code_expires_at, created_by)
+			VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7, $8)
+			ON CONFLICT (company_public_id, domain) DO UPDATE
+			SET method = EXCLUDED.method, dns_token = EXCLUDED.dns_token, email = EXCLUDED.email,
+				code_hash = EXCLUDED.code_hash, code_expires_at = EXCLUDED.code_expires_at,
+				created_by = EXCLUDED.created_by, created_at = NOW()
+			WHERE company_domains.verified_at IS NULL
+			RETURNING ` + domainColumns
+		var err error
+		res, err = scanDomain(tx.QueryRow(ctx, query,
+			domain.CompanyPublicID,
+			domain.Domain,
+			domain.Method,
+			domain.DNSToken,
+			domain.Email,
+			codeHash,
+			codeExpiresAt,
+			domain.CreatedBy,
+		))
+		if errors.Is(err, pgx.ErrNoRows) {
+			query = `SELECT ` + domainColumns + ` FROM company_domains WHERE company_public_id = $1 AND domain = $2`
+			res, err = scanDomain(tx.QueryRow(ctx, query, domain.CompanyPublicID, domain.Domain))
+			if err != nil {
+				logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving domain: %v", err)
+			}
+			return err
+		}
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while creating domain: %v", err)
+			return err
+		}
+
+		if domain.Method != models.DomainMethodEmail {
+			return nil
+		}
+		if err := enqueueEmailNotification(ctx, tx, domain.Email, models.NotificationDomainVerification, payload); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while queueing domain verification email: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetDomains retrieves the domains of the company, in the order they were claimed
+func (r *domainRepository) GetDomains(ctx context.Context, companyPublicID string) ([]*models.CompanyDomain, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `SELECT ` + domainColumns + ` FROM company_domains WHERE company_public_id = $1 ORDER BY id`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, companyPublicID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving domains: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]*models.CompanyDomain, 0)
+	for rows.Next() {
+		domain, err := scanDomain(rows)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning domain: %v", err)
+			return nil, err
+		}
+		res = append(res, domain)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over domain rows: %v", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetDomain retrieves the domain of the company
+func (r *domainRepository) GetDomain(ctx context.Context, companyPublicID, domainPublicID string) (*models.CompanyDomain, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	return r.getDomain(ctx, conn(ctx, r.db), companyPublicID, domainPublicID)
+}
+
+// VerifyDomain marks the domain of the company as verified and writes the events. Domains verified by
+// email must be sent the hash of their code before it expires. A domain verified already is returned
+// as it is, without writing the events.
+func (r *domainRepository) VerifyDomain(ctx context.Context, companyPublicID, domainPublicID, codeHash string, events ...*models.DomainEvent) (*models.CompanyDomain, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	var res *models.CompanyDomain
+	err := inTx(ctx, r.db, r.cfg, r.logger, false, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+		query := `
+			UPDATE company_domains
+			SET verified_at = NOW(), code_hash = NULL
+			WHERE company_public_id = $1 AND public_id = $2 AND verified_at IS NULL
+				AND (method = 'dns' OR (code_hash = $3 AND code_expires_at > NOW()))
+			RETURNING ` + domainColumns
+		var err error
+		res, err = scanDomain(tx.QueryRow(ctx, query, companyPublicID, domainPublicID, codeHash))
+		if errors.Is(err, pgx.ErrNoRows) {
+			res, err = r.getDomain(ctx, tx, companyPublicID, domainPublicID)
+			if err != nil {
+				return err
+			}
+			if res.Status != models.DomainVerified {
+				return models.ErrDomainNotVerified
+			}
+			return nil
+		}
+		if err != nil {
+			if isUniqueViolation(err) {
+				return models.ErrDomainTaken
+			}
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while verifying domain: %v", err)
+			return err
+		}
+
+		if err := insertEvents(ctx, tx, events); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while writing domain events: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteDomain removes the domain from the company. A company without another verified domain is no
+// longer verified.
+func (r *domainRepository) DeleteDomain(ctx context.Context, companyPublicID, domainPublicID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `DELETE FROM company_domains WHERE company_public_id = $1 AND public_id = $2`
+
+	tag, err := conn(ctx, r.db).Exec(ctx, query, companyPublicID, domainPublicID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while deleting domain: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrDomainNotFound
+	}
+	return nil
+}
+
+// GetVerifiedCompany retrieves the public ID of the company that verified the domain, or an empty
+// string if no company did
+func (r *domainRepository) GetVerifiedCompany(ctx context.Context, domain string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `SELECT company_public_id FROM company_domains WHERE domain = $1 AND verified_at IS NOT NULL`
+
+	var companyPublicID string
+	err := conn(ctx, r.db).QueryRow(ctx, query, domain).Scan(&companyPublicID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving verified domain: %v", err)
+		return "", err
+	}
+	return companyPublicID, nil
+}
+
+// HasVerifiedEmail checks whether the email address of the user, which the auth service verified, is on
+// a domain the company verified
+func (r *domainRepository) HasVerifiedEmail(ctx context.Context, companyPublicID, userPublicID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM users u
+			JOIN company_domains d ON d.domain = lower(split_part(u.email, '@', 2))
+			WHERE u.public_id = $2 AND u.email_verified AND d.company_public_id = $1 AND d.verified_at IS NOT NULL
+		)`
+
+	var exists bool
+	if err := conn(ctx, r.db).QueryRow(ctx, query, companyPublicID, userPublicID).Scan(&exists); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while checking verified email: %v", err)
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (r *domainRepository) getDomain(ctx context.Context, tx dbtx, companyPublicID, domainPublicID string) (*models.CompanyDomain, error) {
+	query := `SELECT ` + domainColumns + ` FROM company_domains WHERE company_public_id = $1 AND public_id = $2`
+
+	domain, err := scanDomain(tx.QueryRow(ctx, query, companyPublicID, domainPublicID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrDomainNotFound
+		}
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving domain: %v", err)
+		return nil, err
+	}
+	return domain, nil
+}
+
+func scanDomain(row pgx.Row) (*models.CompanyDomain, error) {
+	domain := &models.CompanyDomain{}
+	err := row.Scan(
+		&domain.PublicID,
+		&domain.CompanyPublicID,
+		&domain.Domain,
+		&domain.Method,
+		&domain.Status,
+		&domain.DNSToken,
+		&domain.Email,
+		&domain.CreatedBy,
+		&domain.CreatedAt,
+		&domain.VerifiedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return domain, nil
+}
+
+// isUniqueViolation tells whether the statement failed on a unique constraint
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
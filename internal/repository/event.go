@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
+)
+
+// eventRepository represents the repository for the domain event outbox.
+type eventRepository struct {
+	db     *pgxpool.Pool
+	cfg    *config.DBConf
+	logger *zap.SugaredLogger
+}
+
+// NewEventRepository creates a new instance of eventRepository.
+func NewEventRepository(db *pgxpool.Pool, cfg *config.DBConf, logger *zap.SugaredLogger) EventRepository {
+	return &eventRepository{
+		db:     db,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// insertEvents writes domain events to the outbox as part of the caller's transaction,
+// so the events are only published if the change they describe is committed.
+func insertEvents(ctx context.Context, tx dbtx, events []*models.DomainEvent) error {
+	query := `
+		INSERT INTO domain_event_outbox (event_type, aggregate_type, aggregate_public_id, payload)
+		VALUES ($1, $2, $3, $4)`
+
+	for _, event := range events {
+		if event == nil {
+			continue
+		}
+		payload := event.Payload
+		if payload == nil {
+			payload = map[string]interface{}{}
+		}
+		if _, err := tx.Exec(ctx, query, event.Type, event.AggregateType, event.AggregatePublicID, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClaimUnpublishedEvents leases up to limit events that are due for publishing, oldest first.
+// Leased events are hidden from other relays until the lease expires.
+func (r *eventRepository) ClaimUnpublishedEvents(ctx context.Context, limit int, lease time.Duration) ([]*models.DomainEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		WITH claimed AS (
+			UPDATE domain_event_outbox
+			SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond'
+			WHERE id IN (
+				SELECT id
+				FROM domain_event_outbox
+				WHERE published_at IS NULL AND next_attempt_at <= NOW()
+				ORDER BY id
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, public_id, event_type, aggregate_type, aggregate_public_id, payload, attempts, occurred_at
+		)
+		SELECT public_id, event_type, aggregate_type, aggregate_public_id, payload, attempts, occurred_at
+		FROM claimed
+		ORDER BY id`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while claiming unpublished events: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]*models.DomainEvent, 0)
+	for rows.Next() {
+		event := &models.DomainEvent{}
+		err := rows.Scan(
+			&event.PublicID,
+			&event.Type,
+			&event.AggregateType,
+			&event.AggregatePublicID,
+			&event.Payload,
+			&event.Attempts,
+			&event.OccurredAt,
+		)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning event: %v", err)
+			return nil, err
+		}
+		res = append(res, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over event rows: %v", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// MarkEventPublished marks the event as published so it is not sent again
+func (r *eventRepository) MarkEventPublished(ctx context.Context, publicID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		UPDATE domain_event_outbox
+		SET published_at = NOW(), attempts = attempts + 1, last_error = NULL
+		WHERE public_id = $1`
+
+	if _, err := conn(ctx, r.db).Exec(ctx, query, publicID); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while marking event as published: %v", err)
+		return err
+	}
+	return nil
+}
+
+// MarkEventFailed records a failed publish attempt and schedules the event to be retried at nextAttemptAt
+func (r *eventRepository) MarkEventFailed(ctx context.Context, publicID, publishErr string, nextAttemptAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		UPDATE domain_event_outbox
+		SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+		WHERE public_id = $1`
+
+	if _, err := conn(ctx, r.db).Exec(ctx, query, publicID, publishErr, nextAttemptAt); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while marking event as failed: %v", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package repository_test
+
+import (
+	"context"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type postgresFixtures struct {
+	db  *pgxpool.Pool
+	cfg *config.DBConf
+}
+
+// newPostgresFixtures creates fixtures written to the database. They are never removed,
+// so they should only be used with a disposable database or schema.
+func newPostgresFixtures(db *pgxpool.Pool, cfg *config.DBConf) fixtures {
+	return &postgresFixtures{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+func (f *postgresFixtures) CreateCandidate(ctx context.Context, candidate *models.Candidate) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.cfg.TimeOut)
+	defer cancel()
+
+	var publicID string
+	err := f.inTx(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `INSERT INTO users (first_name, last_name, photo) VALUES ($1, $2, $3) RETURNING public_id`,
+			deref(candidate.FirstName), deref(candidate.LastName), deref(candidate.Photo)).Scan(&publicID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `INSERT INTO candidates (public_id, current_position, education, resume, bio) VALUES ($1, $2, $3, $4, $5)`,
+			publicID, deref(candidate.CurrentPosition), deref(candidate.Education), deref(candidate.Resume), deref(candidate.Bio))
+		return err
+	})
+	return publicID, err
+}
+
+func (f *postgresFixtures) CreateRecruiter(ctx context.Context, recruiter *models.Recruiter) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.cfg.TimeOut)
+	defer cancel()
+
+	var publicID string
+	err := f.inTx(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `INSERT INTO users (first_name, last_name, photo) VALUES ($1, $2, $3) RETURNING public_id`,
+			recruiter.FirstName, recruiter.LastName, recruiter.Photo).Scan(&publicID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `INSERT INTO recruiters (public_id, company_public_id, role) VALUES ($1, NULLIF($2, '')::uuid, COALESCE(NULLIF($3, ''), 'recruiter'))`,
+			publicID, recruiter.CompanyPublicID, recruiter.Role)
+		return err
+	})
+	return publicID, err
+}
+
+func (f *postgresFixtures) CreatePosition(ctx context.Context, recruiterPublicID string, position *models.Position) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.cfg.TimeOut)
+	defer cancel()
+
+	var publicID string
+	err := f.db.QueryRow(ctx, `INSERT INTO positions (name, status, recruiter_public_id) VALUES ($1, $2, $3) RETURNING public_id`,
+		position.Name, position.Status, recruiterPublicID).Scan(&publicID)
+	return publicID, err
+}
+
+func (f *postgresFixtures) CreateInterview(ctx context.Context, candidatePublicID, positionPublicID string, results []byte) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.cfg.TimeOut)
+	defer cancel()
+
+	var publicID string
+	err := f.inTx(ctx, func(tx pgx.Tx) error {
+		var interviewID int
+		err := tx.QueryRow(ctx, `INSERT INTO interviews (results) VALUES ($1) RETURNING id, public_id`, results).Scan(&interviewID, &publicID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+		INSERT INTO user_interviews (candidate_id, position_id, interview_id) VALUES (
+			(SELECT id FROM candidates WHERE public_id = $1),
+			(SELECT id FROM positions WHERE public_id = $2),
+			$3
+		)`, candidatePublicID, positionPublicID, interviewID)
+		return err
+	})
+	return publicID, err
+}
+
+func (f *postgresFixtures) inTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := f.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
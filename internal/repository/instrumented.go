@@ -0,0 +1,822 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/metrics"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/tracing"
+)
+
+// instrument times and traces every call to the database repositories. It runs before the read
+// cache is added, so cache hits are not counted as queries.
+func (r *Repository) instrument() {
+	r.RecruiterRepository = &instrumentedRecruiterRepository{next: r.RecruiterRepository}
+	r.CandidateRepository = &instrumentedCandidateRepository{next: r.CandidateRepository}
+	r.CompanyRepository = &instrumentedCompanyRepository{next: r.CompanyRepository}
+	r.ShortlistRepository = &instrumentedShortlistRepository{next: r.ShortlistRepository}
+	r.NoteRepository = &instrumentedNoteRepository{next: r.NoteRepository}
+	r.SavedSearchRepository = &instrumentedSavedSearchRepository{next: r.SavedSearchRepository}
+	r.NotificationRepository = &instrumentedNotificationRepository{next: r.NotificationRepository}
+	r.EventRepository = &instrumentedEventRepository{next: r.EventRepository}
+	r.WebhookRepository = &instrumentedWebhookRepository{next: r.WebhookRepository}
+	r.ProvisioningRepository = &instrumentedProvisioningRepository{next: r.ProvisioningRepository}
+	r.MembershipRepository = &instrumentedMembershipRepository{next: r.MembershipRepository}
+	r.DomainRepository = &instrumentedDomainRepository{next: r.DomainRepository}
+}
+
+type instrumentedCompanyRepository struct {
+	next CompanyRepository
+}
+
+func (r *instrumentedCompanyRepository) CreateCompany(ctx context.Context, company *models.Company, events ...*models.DomainEvent) (string, error) {
+	ctx, span := tracing.Start(ctx, "CompanyRepository.CreateCompany")
+	start := time.Now()
+	res, err := r.next.CreateCompany(ctx, company, events...)
+	metrics.ObserveQuery("company", "CreateCompany", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedCompanyRepository) UpdateCompany(ctx context.Context, company *models.Company) error {
+	ctx, span := tracing.Start(ctx, "CompanyRepository.UpdateCompany")
+	start := time.Now()
+	err := r.next.UpdateCompany(ctx, company)
+	metrics.ObserveQuery("company", "UpdateCompany", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *instrumentedCompanyRepository) GetCompany(ctx context.Context, publicID string) (*models.Company, error) {
+	ctx, span := tracing.Start(ctx, "CompanyRepository.GetCompany")
+	start := time.Now()
+	res, err := r.next.GetCompany(ctx, publicID)
+	metrics.ObserveQuery("company", "GetCompany", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedCompanyRepository) GetCompanies(ctx context.Context, args *models.SearchArgs) ([]*models.Company, int, error) {
+	ctx, span := tracing.Start(ctx, "CompanyRepository.GetCompanies")
+	start := time.Now()
+	res, count, err := r.next.GetCompanies(ctx, args)
+	metrics.ObserveQuery("company", "GetCompanies", start, err)
+	tracing.End(span, err)
+	return res, count, err
+}
+
+func (r *instrumentedCompanyRepository) Exists(ctx context.Context, publicID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "CompanyRepository.Exists")
+	start := time.Now()
+	res, err := r.next.Exists(ctx, publicID)
+	metrics.ObserveQuery("company", "Exists", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+type instrumentedRecruiterRepository struct {
+	next RecruiterRepository
+}
+
+func (r *instrumentedRecruiterRepository) Exists(ctx context.Context, publicID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "RecruiterRepository.Exists")
+	start := time.Now()
+	res, err := r.next.Exists(ctx, publicID)
+	metrics.ObserveQuery("recruiter", "Exists", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedRecruiterRepository) GetRecruiter(ctx context.Context, publicID string) (*models.Recruiter, error) {
+	ctx, span := tracing.Start(ctx, "RecruiterRepository.GetRecruiter")
+	start := time.Now()
+	res, err := r.next.GetRecruiter(ctx, publicID)
+	metrics.ObserveQuery("recruiter", "GetRecruiter", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedRecruiterRepository) GetCompanyPublicID(ctx context.Context, publicID string) (string, error) {
+	ctx, span := tracing.Start(ctx, "RecruiterRepository.GetCompanyPublicID")
+	start := time.Now()
+	res, err := r.next.GetCompanyPublicID(ctx, publicID)
+	metrics.ObserveQuery("recruiter", "GetCompanyPublicID", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedRecruiterRepository) GetMembership(ctx context.Context, publicID string) (*models.Membership, error) {
+	ctx, span := tracing.Start(ctx, "RecruiterRepository.GetMembership")
+	start := time.Now()
+	res, err := r.next.GetMembership(ctx, publicID)
+	metrics.ObserveQuery("recruiter", "GetMembership", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedRecruiterRepository) FilterByCompany(ctx context.Context, companyPublicID string, publicIDs []string) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "RecruiterRepository.FilterByCompany")
+	start := time.Now()
+	res, err := r.next.FilterByCompany(ctx, companyPublicID, publicIDs)
+	metrics.ObserveQuery("recruiter", "FilterByCompany", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedRecruiterRepository) GetInterviewsByPublicID(ctx context.Context, publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error) {
+	ctx, span := tracing.Start(ctx, "RecruiterRepository.GetInterviewsByPublicID")
+	start := time.Now()
+	res, count, err := r.next.GetInterviewsByPublicID(ctx, publicID, searchArgs)
+	metrics.ObserveQuery("recruiter", "GetInterviewsByPublicID", start, err)
+	tracing.End(span, err)
+	return res, count, err
+}
+
+type instrumentedCandidateRepository struct {
+	next CandidateRepository
+}
+
+func (r *instrumentedCandidateRepository) GetCandidatesBySearch(ctx context.Context, searchArgs *models.SearchArgs) ([]*models.Candidate, int, error) {
+	ctx, span := tracing.Start(ctx, "CandidateRepository.GetCandidatesBySearch")
+	start := time.Now()
+	res, count, err := r.next.GetCandidatesBySearch(ctx, searchArgs)
+	metrics.ObserveQuery("candidate", "GetCandidatesBySearch", start, err)
+	tracing.End(span, err)
+	return res, count, err
+}
+
+func (r *instrumentedCandidateRepository) GetCandidateByPublicID(ctx context.Context, publicID string) (*models.Candidate, error) {
+	ctx, span := tracing.Start(ctx, "CandidateRepository.GetCandidateByPublicID")
+	start := time.Now()
+	res, err := r.next.GetCandidateByPublicID(ctx, publicID)
+	metrics.ObserveQuery("candidate", "GetCandidateByPublicID", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedCandidateRepository) Exists(ctx context.Context, publicID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "CandidateRepository.Exists")
+	start := time.Now()
+	res, err := r.next.Exists(ctx, publicID)
+	metrics.ObserveQuery("candidate", "Exists", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedCandidateRepository) AddSkillsToCandidate(ctx context.Context, candidateID string, skills []string, events ...*models.DomainEvent) (*models.SkillsChange, error) {
+	ctx, span := tracing.Start(ctx, "CandidateRepository.AddSkillsToCandidate")
+	start := time.Now()
+	res, err := r.next.AddSkillsToCandidate(ctx, candidateID, skills, events...)
+	metrics.ObserveQuery("candidate", "AddSkillsToCandidate", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedCandidateRepository) UpdateCandidateByID(ctx context.Context, candidateID string, updateData *models.Candidate, events ...*models.DomainEvent) error {
+	ctx, span := tracing.Start(ctx, "CandidateRepository.UpdateCandidateByID")
+	start := time.Now()
+	err := r.next.UpdateCandidateByID(ctx, candidateID, updateData, events...)
+	metrics.ObserveQuery("candidate", "UpdateCandidateByID", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *instrumentedCandidateRepository) DeleteCandidateByID(ctx context.Context, candidateID string, events ...*models.DomainEvent) error {
+	ctx, span := tracing.Start(ctx, "CandidateRepository.DeleteCandidateByID")
+	start := time.Now()
+	err := r.next.DeleteCandidateByID(ctx, candidateID, events...)
+	metrics.ObserveQuery("candidate", "DeleteCandidateByID", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *instrumentedCandidateRepository) DeleteSkillsFromCandidate(ctx context.Context, candidateID string, skills []string, events ...*models.DomainEvent) (*models.SkillsChange, error) {
+	ctx, span := tracing.Start(ctx, "CandidateRepository.DeleteSkillsFromCandidate")
+	start := time.Now()
+	res, err := r.next.DeleteSkillsFromCandidate(ctx, candidateID, skills, events...)
+	metrics.ObserveQuery("candidate", "DeleteSkillsFromCandidate", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedCandidateRepository) ReplaceCandidateSkills(ctx context.Context, candidateID string, skills []string, events ...*models.DomainEvent) (*models.SkillsChange, error) {
+	ctx, span := tracing.Start(ctx, "CandidateRepository.ReplaceCandidateSkills")
+	start := time.Now()
+	res, err := r.next.ReplaceCandidateSkills(ctx, candidateID, skills, events...)
+	metrics.ObserveQuery("candidate", "ReplaceCandidateSkills", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedCandidateRepository) GetInterviewsByPublicID(ctx context.Context, publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error) {
+	ctx, span := tracing.Start(ctx, "CandidateRepository.GetInterviewsByPublicID")
+	start := time.Now()
+	res, count, err := r.next.GetInterviewsByPublicID(ctx, publicID, searchArgs)
+	metrics.ObserveQuery("candidate", "GetInterviewsByPublicID", start, err)
+	tracing.End(span, err)
+	return res, count, err
+}
+
+type instrumentedShortlistRepository struct {
+	next ShortlistRepository
+}
+
+func (r *instrumentedShortlistRepository) CreateShortlist(ctx context.Context, shortlist *models.Shortlist) (string, error) {
+	ctx, span := tracing.Start(ctx, "ShortlistRepository.CreateShortlist")
+	start := time.Now()
+	res, err := r.next.CreateShortlist(ctx, shortlist)
+	metrics.ObserveQuery("shortlist", "CreateShortlist", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedShortlistRepository) GetShortlist(ctx context.Context, publicID string) (*models.Shortlist, error) {
+	ctx, span := tracing.Start(ctx, "ShortlistRepository.GetShortlist")
+	start := time.Now()
+	res, err := r.next.GetShortlist(ctx, publicID)
+	metrics.ObserveQuery("shortlist", "GetShortlist", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedShortlistRepository) GetShortlists(ctx context.Context, recruiterPublicID, companyPublicID string, args *models.SearchArgs) ([]*models.Shortlist, int, error) {
+	ctx, span := tracing.Start(ctx, "ShortlistRepository.GetShortlists")
+	start := time.Now()
+	res, count, err := r.next.GetShortlists(ctx, recruiterPublicID, companyPublicID, args)
+	metrics.ObserveQuery("shortlist", "GetShortlists", start, err)
+	tracing.End(span, err)
+	return res, count, err
+}
+
+func (r *instrumentedShortlistRepository) UpdateShortlist(ctx context.Context, shortlist *models.Shortlist) error {
+	ctx, span := tracing.Start(ctx, "ShortlistRepository.UpdateShortlist")
+	start := time.Now()
+	err := r.next.UpdateShortlist(ctx, shortlist)
+	metrics.ObserveQuery("shortlist", "UpdateShortlist", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *instrumentedShortlistRepository) DeleteShortlist(ctx context.Context, publicID string) error {
+	ctx, span := tracing.Start(ctx, "ShortlistRepository.DeleteShortlist")
+	start := time.Now()
+	err := r.next.DeleteShortlist(ctx, publicID)
+	metrics.ObserveQuery("shortlist", "DeleteShortlist", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *instrumentedShortlistRepository) AddCandidate(ctx context.Context, shortlistPublicID, candidatePublicID, recruiterPublicID, note string) error {
+	ctx, span := tracing.Start(ctx, "ShortlistRepository.AddCandidate")
+	start := time.Now()
+	err := r.next.AddCandidate(ctx, shortlistPublicID, candidatePublicID, recruiterPublicID, note)
+	metrics.ObserveQuery("shortlist", "AddCandidate", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *instrumentedShortlistRepository) RemoveCandidate(ctx context.Context, shortlistPublicID, candidatePublicID string) error {
+	ctx, span := tracing.Start(ctx, "ShortlistRepository.RemoveCandidate")
+	start := time.Now()
+	err := r.next.RemoveCandidate(ctx, shortlistPublicID, candidatePublicID)
+	metrics.ObserveQuery("shortlist", "RemoveCandidate", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *instrumentedShortlistRepository) GetCandidates(ctx context.Context, shortlistPublicID string, args *models.SearchArgs) ([]*models.ShortlistCandidate, int, error) {
+	ctx, span := tracing.Start(ctx, "ShortlistRepository.GetCandidates")
+	start := time.Now()
+	res, count, err := r.next.GetCandidates(ctx, shortlistPublicID, args)
+	metrics.ObserveQuery("shortlist", "GetCandidates", start, err)
+	tracing.End(span, err)
+	return res, count, err
+}
+
+type instrumentedNoteRepository struct {
+	next NoteRepository
+}
+
+func (r *instrumentedNoteRepository) SubjectExists(ctx context.Context, subjectType, subjectPublicID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "NoteRepository.SubjectExists")
+	start := time.Now()
+	res, err := r.next.SubjectExists(ctx, subjectType, subjectPublicID)
+	metrics.ObserveQuery("note", "SubjectExists", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedNoteRepository) CreateNote(ctx context.Context, note *models.Note) (string, error) {
+	ctx, span := tracing.Start(ctx, "NoteRepository.CreateNote")
+	start := time.Now()
+	res, err := r.next.CreateNote(ctx, note)
+	metrics.ObserveQuery("note", "CreateNote", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedNoteRepository) GetNote(ctx context.Context, publicID string) (*models.Note, error) {
+	ctx, span := tracing.Start(ctx, "NoteRepository.GetNote")
+	start := time.Now()
+	res, err := r.next.GetNote(ctx, publicID)
+	metrics.ObserveQuery("note", "GetNote", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedNoteRepository) GetNotes(ctx context.Context, companyPublicID, subjectType, subjectPublicID string, args *models.SearchArgs) ([]*models.Note, int, error) {
+	ctx, span := tracing.Start(ctx, "NoteRepository.GetNotes")
+	start := time.Now()
+	res, count, err := r.next.GetNotes(ctx, companyPublicID, subjectType, subjectPublicID, args)
+	metrics.ObserveQuery("note", "GetNotes", start, err)
+	tracing.End(span, err)
+	return res, count, err
+}
+
+func (r *instrumentedNoteRepository) GetMentionedNotes(ctx context.Context, recruiterPublicID, companyPublicID string, args *models.SearchArgs) ([]*models.Note, int, error) {
+	ctx, span := tracing.Start(ctx, "NoteRepository.GetMentionedNotes")
+	start := time.Now()
+	res, count, err := r.next.GetMentionedNotes(ctx, recruiterPublicID, companyPublicID, args)
+	metrics.ObserveQuery("note", "GetMentionedNotes", start, err)
+	tracing.End(span, err)
+	return res, count, err
+}
+
+func (r *instrumentedNoteRepository) UpdateNote(ctx context.Context, note *models.Note, editorPublicID string) error {
+	ctx, span := tracing.Start(ctx, "NoteRepository.UpdateNote")
+	start := time.Now()
+	err := r.next.UpdateNote(ctx, note, editorPublicID)
+	metrics.ObserveQuery("note", "UpdateNote", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *instrumentedNoteRepository) DeleteNote(ctx context.Context, publicID string) error {
+	ctx, span := tracing.Start(ctx, "NoteRepository.DeleteNote")
+	start := time.Now()
+	err := r.next.DeleteNote(ctx, publicID)
+	metrics.ObserveQuery("note", "DeleteNote", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *instrumentedNoteRepository) GetNoteRevisions(ctx context.Context, publicID string) ([]*models.NoteRevision, error) {
+	ctx, span := tracing.Start(ctx, "NoteRepository.GetNoteRevisions")
+	start := time.Now()
+	res, err := r.next.GetNoteRevisions(ctx, publicID)
+	metrics.ObserveQuery("note", "GetNoteRevisions", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+type instrumentedSavedSearchRepository struct {
+	next SavedSearchRepository
+}
+
+func (r *instrumentedSavedSearchRepository) CreateSavedSearch(ctx context.Context, savedSearch *models.SavedSearch) (string, error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchRepository.CreateSavedSearch")
+	start := time.Now()
+	res, err := r.next.CreateSavedSearch(ctx, savedSearch)
+	metrics.ObserveQuery("saved_search", "CreateSavedSearch", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedSavedSearchRepository) GetSavedSearch(ctx context.Context, publicID string) (*models.SavedSearch, error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchRepository.GetSavedSearch")
+	start := time.Now()
+	res, err := r.next.GetSavedSearch(ctx, publicID)
+	metrics.ObserveQuery("saved_search", "GetSavedSearch", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedSavedSearchRepository) GetSavedSearches(ctx context.Context, recruiterPublicID string, args *models.SearchArgs) ([]*models.SavedSearch, int, error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchRepository.GetSavedSearches")
+	start := time.Now()
+	res, count, err := r.next.GetSavedSearches(ctx, recruiterPublicID, args)
+	metrics.ObserveQuery("saved_search", "GetSavedSearches", start, err)
+	tracing.End(span, err)
+	return res, count, err
+}
+
+func (r *instrumentedSavedSearchRepository) GetSavedSearchIDs(ctx context.Context) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchRepository.GetSavedSearchIDs")
+	start := time.Now()
+	res, err := r.next.GetSavedSearchIDs(ctx)
+	metrics.ObserveQuery("saved_search", "GetSavedSearchIDs", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedSavedSearchRepository) DeleteSavedSearch(ctx context.Context, publicID string) error {
+	ctx, span := tracing.Start(ctx, "SavedSearchRepository.DeleteSavedSearch")
+	start := time.Now()
+	err := r.next.DeleteSavedSearch(ctx, publicID)
+	metrics.ObserveQuery("saved_search", "DeleteSavedSearch", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *instrumentedSavedSearchRepository) RecordNewMatches(ctx context.Context, publicID string) (int, error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchRepository.RecordNewMatches")
+	start := time.Now()
+	res, err := r.next.RecordNewMatches(ctx, publicID)
+	metrics.ObserveQuery("saved_search", "RecordNewMatches", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedSavedSearchRepository) GetAlerts(ctx context.Context, recruiterPublicID string, unseenOnly bool, args *models.SearchArgs) ([]*models.SavedSearchAlert, int, error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchRepository.GetAlerts")
+	start := time.Now()
+	res, count, err := r.next.GetAlerts(ctx, recruiterPublicID, unseenOnly, args)
+	metrics.ObserveQuery("saved_search", "GetAlerts", start, err)
+	tracing.End(span, err)
+	return res, count, err
+}
+
+func (r *instrumentedSavedSearchRepository) MarkAlertsSeen(ctx context.Context, recruiterPublicID string, alertPublicIDs []string) error {
+	ctx, span := tracing.Start(ctx, "SavedSearchRepository.MarkAlertsSeen")
+	start := time.Now()
+	err := r.next.MarkAlertsSeen(ctx, recruiterPublicID, alertPublicIDs)
+	metrics.ObserveQuery("saved_search", "MarkAlertsSeen", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+type instrumentedNotificationRepository struct {
+	next NotificationRepository
+}
+
+func (r *instrumentedNotificationRepository) ClaimPendingNotifications(ctx context.Context, limit int, lease time.Duration) ([]*models.Notification, error) {
+	ctx, span := tracing.Start(ctx, "NotificationRepository.ClaimPendingNotifications")
+	start := time.Now()
+	res, err := r.next.ClaimPendingNotifications(ctx, limit, lease)
+	metrics.ObserveQuery("notification", "ClaimPendingNotifications", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedNotificationRepository) GetRecipient(ctx context.Context, userPublicID, eventType string) (*models.NotificationRecipient, error) {
+	ctx, span := tracing.Start(ctx, "NotificationRepository.GetRecipient")
+	start := time.Now()
+	res, err := r.next.GetRecipient(ctx, userPublicID, eventType)
+	metrics.ObserveQuery("notification", "GetRecipient", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedNotificationRepository) RecordDelivery(ctx context.Context, publicID, status, recipient, deliveryErr string, nextAttemptAt *time.Time) error {
+	ctx, span := tracing.Start(ctx, "NotificationRepository.RecordDelivery")
+	start := time.Now()
+	err := r.next.RecordDelivery(ctx, publicID, status, recipient, deliveryErr, nextAttemptAt)
+	metrics.ObserveQuery("notification", "RecordDelivery", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *instrumentedNotificationRepository) GetNotifications(ctx context.Context, userPublicID string, args *models.SearchArgs) ([]*models.Notification, int, error) {
+	ctx, span := tracing.Start(ctx, "NotificationRepository.GetNotifications")
+	start := time.Now()
+	res, count, err := r.next.GetNotifications(ctx, userPublicID, args)
+	metrics.ObserveQuery("notification", "GetNotifications", start, err)
+	tracing.End(span, err)
+	return res, count, err
+}
+
+func (r *instrumentedNotificationRepository) GetPreferences(ctx context.Context, userPublicID string) ([]*models.NotificationPreference, error) {
+	ctx, span := tracing.Start(ctx, "NotificationRepository.GetPreferences")
+	start := time.Now()
+	res, err := r.next.GetPreferences(ctx, userPublicID)
+	metrics.ObserveQuery("notification", "GetPreferences", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedNotificationRepository) SetPreferences(ctx context.Context, userPublicID string, preferences []*models.NotificationPreference) error {
+	ctx, span := tracing.Start(ctx, "NotificationRepository.SetPreferences")
+	start := time.Now()
+	err := r.next.SetPreferences(ctx, userPublicID, preferences)
+	metrics.ObserveQuery("notification", "SetPreferences", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+type instrumentedWebhookRepository struct {
+	next WebhookRepository
+}
+
+func (r *instrumentedWebhookRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) (string, error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.CreateWebhook")
+	start := time.Now()
+	res, err := r.next.CreateWebhook(ctx, webhook)
+	metrics.ObserveQuery("webhook", "CreateWebhook", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedWebhookRepository) GetWebhook(ctx context.Context, publicID string) (*models.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.GetWebhook")
+	start := time.Now()
+	res, err := r.next.GetWebhook(ctx, publicID)
+	metrics.ObserveQuery("webhook", "GetWebhook", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedWebhookRepository) GetWebhooks(ctx context.Context, companyPublicID string) ([]*models.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.GetWebhooks")
+	start := time.Now()
+	res, err := r.next.GetWebhooks(ctx, companyPublicID)
+	metrics.ObserveQuery("webhook", "GetWebhooks", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedWebhookRepository) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.UpdateWebhook")
+	start := time.Now()
+	err := r.next.UpdateWebhook(ctx, webhook)
+	metrics.ObserveQuery("webhook", "UpdateWebhook", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *instrumentedWebhookRepository) DeleteWebhook(ctx context.Context, publicID string) error {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.DeleteWebhook")
+	start := time.Now()
+	err := r.next.DeleteWebhook(ctx, publicID)
+	metrics.ObserveQuery("webhook", "DeleteWebhook", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *instrumentedWebhookRepository) GetDeliveries(ctx context.Context, webhookPublicID, status string, args *models.SearchArgs) ([]*models.WebhookDelivery, int, error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.GetDeliveries")
+	start := time.Now()
+	res, count, err := r.next.GetDeliveries(ctx, webhookPublicID, status, args)
+	metrics.ObserveQuery("webhook", "GetDeliveries", start, err)
+	tracing.End(span, err)
+	return res, count, err
+}
+
+func (r *instrumentedWebhookRepository) GetDelivery(ctx context.Context, publicID string) (*models.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.GetDelivery")
+	start := time.Now()
+	res, err := r.next.GetDelivery(ctx, publicID)
+	metrics.ObserveQuery("webhook", "GetDelivery", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedWebhookRepository) ReplayDelivery(ctx context.Context, publicID string) error {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.ReplayDelivery")
+	start := time.Now()
+	err := r.next.ReplayDelivery(ctx, publicID)
+	metrics.ObserveQuery("webhook", "ReplayDelivery", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *instrumentedWebhookRepository) ClaimPendingDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.ClaimPendingDeliveries")
+	start := time.Now()
+	res, err := r.next.ClaimPendingDeliveries(ctx, limit, lease)
+	metrics.ObserveQuery("webhook", "ClaimPendingDeliveries", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedWebhookRepository) RecordDeliveryAttempt(ctx context.Context, publicID string, delivered bool, statusCode int, attemptErr string, nextAttemptAt *time.Time) error {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.RecordDeliveryAttempt")
+	start := time.Now()
+	err := r.next.RecordDeliveryAttempt(ctx, publicID, delivered, statusCode, attemptErr, nextAttemptAt)
+	metrics.ObserveQuery("webhook", "RecordDeliveryAttempt", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+type instrumentedEventRepository struct {
+	next EventRepository
+}
+
+func (r *instrumentedEventRepository) ClaimUnpublishedEvents(ctx context.Context, limit int, lease time.Duration) ([]*models.DomainEvent, error) {
+	ctx, span := tracing.Start(ctx, "EventRepository.ClaimUnpublishedEvents")
+	start := time.Now()
+	res, err := r.next.ClaimUnpublishedEvents(ctx, limit, lease)
+	metrics.ObserveQuery("event", "ClaimUnpublishedEvents", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedEventRepository) MarkEventPublished(ctx context.Context, publicID string) error {
+	ctx, span := tracing.Start(ctx, "EventRepository.MarkEventPublished")
+	start := time.Now()
+	err := r.next.MarkEventPublished(ctx, publicID)
+	metrics.ObserveQuery("event", "MarkEventPublished", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *instrumentedEventRepository) MarkEventFailed(ctx context.Context, publicID, publishErr string, nextAttemptAt time.Time) error {
+	ctx, span := tracing.Start(ctx, "EventRepository.MarkEventFailed")
+	start := time.Now()
+	err := r.next.MarkEventFailed(ctx, publicID, publishErr, nextAttemptAt)
+	metrics.ObserveQuery("event", "MarkEventFailed", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+type instrumentedProvisioningRepository struct {
+	next ProvisioningRepository
+}
+
+func (r *instrumentedProvisioningRepository) CreateCandidate(ctx context.Context, candidate *models.CandidateProvision, events ...*models.DomainEvent) (*models.ProvisionedUser, error) {
+	ctx, span := tracing.Start(ctx, "ProvisioningRepository.CreateCandidate")
+	start := time.Now()
+	res, err := r.next.CreateCandidate(ctx, candidate, events...)
+	metrics.ObserveQuery("provisioning", "CreateCandidate", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedProvisioningRepository) CreateRecruiter(ctx context.Context, recruiter *models.RecruiterProvision, role string, events ...*models.DomainEvent) (*models.ProvisionedUser, error) {
+	ctx, span := tracing.Start(ctx, "ProvisioningRepository.CreateRecruiter")
+	start := time.Now()
+	res, err := r.next.CreateRecruiter(ctx, recruiter, role, events...)
+	metrics.ObserveQuery("provisioning", "CreateRecruiter", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedProvisioningRepository) FindOrCreateCompany(ctx context.Context, company *models.Company, events ...*models.DomainEvent) (*models.Company, bool, error) {
+	ctx, span := tracing.Start(ctx, "ProvisioningRepository.FindOrCreateCompany")
+	start := time.Now()
+	res, created, err := r.next.FindOrCreateCompany(ctx, company, events...)
+	metrics.ObserveQuery("provisioning", "FindOrCreateCompany", start, err)
+	tracing.End(span, err)
+	return res, created, err
+}
+
+type instrumentedMembershipRepository struct {
+	next MembershipRepository
+}
+
+func (r *instrumentedMembershipRepository) GetMembers(ctx context.Context, companyPublicID string) ([]*models.CompanyMember, error) {
+	ctx, span := tracing.Start(ctx, "MembershipRepository.GetMembers")
+	start := time.Now()
+	res, err := r.next.GetMembers(ctx, companyPublicID)
+	metrics.ObserveQuery("membership", "GetMembers", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedMembershipRepository) HasOwner(ctx context.Context, companyPublicID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "MembershipRepository.HasOwner")
+	start := time.Now()
+	res, err := r.next.HasOwner(ctx, companyPublicID)
+	metrics.ObserveQuery("membership", "HasOwner", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedMembershipRepository) SetMembership(ctx context.Context, recruiterPublicID, companyPublicID, role string, events ...*models.DomainEvent) error {
+	ctx, span := tracing.Start(ctx, "MembershipRepository.SetMembership")
+	start := time.Now()
+	err := r.next.SetMembership(ctx, recruiterPublicID, companyPublicID, role, events...)
+	metrics.ObserveQuery("membership", "SetMembership", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *instrumentedMembershipRepository) CreateInvitation(ctx context.Context, invitation *models.Invitation, tokenHash string, payload map[string]interface{}) (*models.Invitation, error) {
+	ctx, span := tracing.Start(ctx, "MembershipRepository.CreateInvitation")
+	start := time.Now()
+	res, err := r.next.CreateInvitation(ctx, invitation, tokenHash, payload)
+	metrics.ObserveQuery("membership", "CreateInvitation", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedMembershipRepository) GetInvitations(ctx context.Context, companyPublicID string, args *models.SearchArgs) ([]*models.Invitation, int, error) {
+	ctx, span := tracing.Start(ctx, "MembershipRepository.GetInvitations")
+	start := time.Now()
+	res, count, err := r.next.GetInvitations(ctx, companyPublicID, args)
+	metrics.ObserveQuery("membership", "GetInvitations", start, err)
+	tracing.End(span, err)
+	return res, count, err
+}
+
+func (r *instrumentedMembershipRepository) RevokeInvitation(ctx context.Context, companyPublicID, invitationPublicID string) error {
+	ctx, span := tracing.Start(ctx, "MembershipRepository.RevokeInvitation")
+	start := time.Now()
+	err := r.next.RevokeInvitation(ctx, companyPublicID, invitationPublicID)
+	metrics.ObserveQuery("membership", "RevokeInvitation", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *instrumentedMembershipRepository) AcceptInvitation(ctx context.Context, tokenHash, recruiterPublicID string) (*models.Invitation, error) {
+	ctx, span := tracing.Start(ctx, "MembershipRepository.AcceptInvitation")
+	start := time.Now()
+	res, err := r.next.AcceptInvitation(ctx, tokenHash, recruiterPublicID)
+	metrics.ObserveQuery("membership", "AcceptInvitation", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedMembershipRepository) CreateJoinRequest(ctx context.Context, joinRequest *models.JoinRequest) (*models.JoinRequest, error) {
+	ctx, span := tracing.Start(ctx, "MembershipRepository.CreateJoinRequest")
+	start := time.Now()
+	res, err := r.next.CreateJoinRequest(ctx, joinRequest)
+	metrics.ObserveQuery("membership", "CreateJoinRequest", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedMembershipRepository) GetJoinRequests(ctx context.Context, companyPublicID, status string, args *models.SearchArgs) ([]*models.JoinRequest, int, error) {
+	ctx, span := tracing.Start(ctx, "MembershipRepository.GetJoinRequests")
+	start := time.Now()
+	res, count, err := r.next.GetJoinRequests(ctx, companyPublicID, status, args)
+	metrics.ObserveQuery("membership", "GetJoinRequests", start, err)
+	tracing.End(span, err)
+	return res, count, err
+}
+
+func (r *instrumentedMembershipRepository) DecideJoinRequest(ctx context.Context, companyPublicID, joinRequestPublicID, status, deciderPublicID string) (*models.JoinRequest, error) {
+	ctx, span := tracing.Start(ctx, "MembershipRepository.DecideJoinRequest")
+	start := time.Now()
+	res, err := r.next.DecideJoinRequest(ctx, companyPublicID, joinRequestPublicID, status, deciderPublicID)
+	metrics.ObserveQuery("membership", "DecideJoinRequest", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+type instrumentedDomainRepository struct {
+	next DomainRepository
+}
+
+func (r *instrumentedDomainRepository) CreateDomain(ctx context.Context, domain *models.CompanyDomain, codeHash string, codeExpiresAt *time.Time, payload map[string]interface{}) (*models.CompanyDomain, error) {
+	ctx, span := tracing.Start(ctx, "DomainRepository.CreateDomain")
+	start := time.Now()
+	res, err := r.next.CreateDomain(ctx, domain, codeHash, codeExpiresAt, payload)
+	metrics.ObserveQuery("domain", "CreateDomain", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedDomainRepository) GetDomains(ctx context.Context, companyPublicID string) ([]*models.CompanyDomain, error) {
+	ctx, span := tracing.Start(ctx, "DomainRepository.GetDomains")
+	start := time.Now()
+	res, err := r.next.GetDomains(ctx, companyPublicID)
+	metrics.ObserveQuery("domain", "GetDomains", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedDomainRepository) GetDomain(ctx context.Context, companyPublicID, domainPublicID string) (*models.CompanyDomain, error) {
+	ctx, span := tracing.Start(ctx, "DomainRepository.GetDomain")
+	start := time.Now()
+	res, err := r.next.GetDomain(ctx, companyPublicID, domainPublicID)
+	metrics.ObserveQuery("domain", "GetDomain", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedDomainRepository) VerifyDomain(ctx context.Context, companyPublicID, domainPublicID, codeHash string, events ...*models.DomainEvent) (*models.CompanyDomain, error) {
+	ctx, span := tracing.Start(ctx, "DomainRepository.VerifyDomain")
+	start := time.Now()
+	res, err := r.next.VerifyDomain(ctx, companyPublicID, domainPublicID, codeHash, events...)
+	metrics.ObserveQuery("domain", "VerifyDomain", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedDomainRepository) DeleteDomain(ctx context.Context, companyPublicID, domainPublicID string) error {
+	ctx, span := tracing.Start(ctx, "DomainRepository.DeleteDomain")
+	start := time.Now()
+	err := r.next.DeleteDomain(ctx, companyPublicID, domainPublicID)
+	metrics.ObserveQuery("domain", "DeleteDomain", start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *instrumentedDomainRepository) GetVerifiedCompany(ctx context.Context, domain string) (string, error) {
+	ctx, span := tracing.Start(ctx, "DomainRepository.GetVerifiedCompany")
+	start := time.Now()
+	res, err := r.next.GetVerifiedCompany(ctx, domain)
+	metrics.ObserveQuery("domain", "GetVerifiedCompany", start, err)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *instrumentedDomainRepository) HasVerifiedEmail(ctx context.Context, companyPublicID, userPublicID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "DomainRepository.HasVerifiedEmail")
+	start := time.Now()
+	res, err := r.next.HasVerifiedEmail(ctx, companyPublicID, userPublicID)
+	metrics.ObserveQuery("domain", "HasVerifiedEmail", start, err)
+	tracing.End(span, err)
+	return res, err
+}
@@ -0,0 +1,495 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
+)
+
+// invitationColumns are the columns of models.Invitation, its status is derived from the timestamps
+const invitationColumns = `public_id, company_public_id, email, role,
+	CASE
+		WHEN accepted_at IS NOT NULL THEN 'accepted'
+		WHEN revoked_at IS NOT NULL THEN 'revoked'
+		WHEN expires_at <= NOW() THEN 'expired'
+		ELSE 'pending'
+	END,
+	invited_by, accepted_by::text, expires_at, created_at, accepted_at`
+
+// joinRequestColumns are the columns of models.JoinRequest, selected from company_join_requests j joined with users u
+const joinRequestColumns = `j.public_id, j.company_public_id, j.recruiter_public_id, u.first_name, u.last_name,
+	j.message, j.status, j.decided_by::text, j.created_at, j.decided_at`
+
+// membershipRepository represents the repository for the members of companies, their invitations and join requests.
+type membershipRepository struct {
+	db     *pgxpool.Pool
+	cfg    *config.DBConf
+	logger *zap.SugaredLogger
+}
+
+// NewMembershipRepository creates a new instance of membershipRepository.
+func NewMembershipRepository(db *pgxpool.Pool, cfg *config.DBConf, logger *zap.SugaredLogger) MembershipRepository {
+	return &membershipRepository{
+		db:     db,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// GetMembers retrieves the recruiters of the company, the most privileged first
+func (r *membershipRepository) GetMembers(ctx context.Context, companyPublicID string) ([]*models.CompanyMember, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		SELECT r.public_id, u.first_name, u.last_name, u.photo, r.role
+		FROM recruiters r
+		JOIN users u ON u.public_id = r.public_id
+		WHERE r.company_public_id = $1
+		ORDER BY array_position(ARRAY['owner', 'admin', 'recruiter', 'viewer']::varchar[], r.role), r.id`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, companyPublicID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving company members: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]*models.CompanyMember, 0)
+	for rows.Next() {
+		member := &models.CompanyMember{}
+		err := rows.Scan(&member.PublicID, &member.FirstName, &member.LastName, &member.Photo, &member.Role)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning company member: %v", err)
+			return nil, err
+		}
+		res = append(res, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over company member rows: %v", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// HasOwner checks whether any recruiter owns the company. The company row is updated to itself first,
+// so transactions checking the same company wait for each other, and the later one sees the owner the
+// earlier one added, or fails to serialize and is retried.
+func (r *membershipRepository) HasOwner(ctx context.Context, companyPublicID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	var exists bool
+	err := inTx(ctx, r.db, r.cfg, r.logger, false, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+		tag, err := tx.Exec(ctx, `UPDATE companies SET id = id WHERE public_id = $1`, companyPublicID)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while locking company: %v", err)
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return models.ErrCompanyNotFound
+		}
+
+		query := `SELECT EXISTS (SELECT 1 FROM recruiters WHERE company_public_id = $1 AND role = 'owner')`
+		if err := tx.QueryRow(ctx, query, companyPublicID).Scan(&exists); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while checking company owners: %v", err)
+			return err
+		}
+		return nil
+	})
+	return exists, err
+}
+
+// SetMembership moves the recruiter to the company with the role, or removes it from its company
+// when companyPublicID is empty. The owners of the company the recruiter leaves or is demoted in
+// are locked, so concurrent changes cannot leave it without one.
+func (r *membershipRepository) SetMembership(ctx context.Context, recruiterPublicID, companyPublicID, role string, events ...*models.DomainEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	return inTx(ctx, r.db, r.cfg, r.logger, false, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+		current := &models.Membership{}
+		query := `SELECT COALESCE(company_public_id::text, ''), role FROM recruiters WHERE public_id = $1 FOR UPDATE`
+		err := tx.QueryRow(ctx, query, recruiterPublicID).Scan(&current.CompanyPublicID, &current.Role)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return models.ErrUserNotFound
+			}
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving recruiter company: %v", err)
+			return err
+		}
+
+		if current.CompanyPublicID != "" && current.Role == models.RoleOwner && (current.CompanyPublicID != companyPublicID || role != models.RoleOwner) {
+			var owners int
+			query = `SELECT COUNT(*) FROM (SELECT 1 FROM recruiters WHERE company_public_id = $1 AND role = 'owner' FOR UPDATE) o`
+			if err := tx.QueryRow(ctx, query, current.CompanyPublicID).Scan(&owners); err != nil {
+				logging.FromContext(ctx, r.logger).Errorf("Error occurred while counting company owners: %v", err)
+				return err
+			}
+			if owners <= 1 {
+				return models.ErrLastOwner
+			}
+		}
+
+		query = `UPDATE recruiters SET company_public_id = NULLIF($2, '')::uuid, role = $3 WHERE public_id = $1`
+		if _, err := tx.Exec(ctx, query, recruiterPublicID, companyPublicID, role); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while updating recruiter company: %v", err)
+			return err
+		}
+
+		if err := insertEvents(ctx, tx, events); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while writing membership events: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+// CreateInvitation stores the invitation with the hash of its token and queues the invitation
+// email with the payload. The pending invitations of the same email to the company are revoked,
+// so only the last one sent can be accepted.
+func (r *membershipRepository) CreateInvitation(ctx context.Context, invitation *models.Invitation, tokenHash string, payload map[string]interface{}) (*models.Invitation, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	var res *models.Invitation
+	err := inTx(ctx, r.db, r.cfg, r.logger, false, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+		query := `
+			UPDATE company_invitations
+			SET revoked_at = NOW()
+			WHERE company_public_id = $1 AND lower(email) = lower($2) AND accepted_at IS NULL AND revoked_at IS NULL`
+		if _, err := tx.Exec(ctx, query, invitation.CompanyPublicID, invitation.Email); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while revoking previous invitations: %v", err)
+			return err
+		}
+
+		query = `
+			INSERT INTO company_invitations (company_public_id, email, role, token_hash, invited_by, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING ` + invitationColumns
+		var err error
+		res, err = scanInvitation(tx.QueryRow(ctx, query,
+			invitation.CompanyPublicID,
+			invitation.Email,
+			invitation.Role,
+			tokenHash,
+			invitation.InvitedBy,
+			invitation.ExpiresAt,
+		))
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while creating invitation: %v", err)
+			return err
+		}
+
+		if err := enqueueEmailNotification(ctx, tx, invitation.Email, models.NotificationCompanyInvitation, payload); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while queueing invitation email: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetInvitations retrieves the invitations of the company, the latest first
+func (r *membershipRepository) GetInvitations(ctx context.Context, companyPublicID string, args *models.SearchArgs) ([]*models.Invitation, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		SELECT ` + invitationColumns + `
+		FROM company_invitations
+		WHERE company_public_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`
+
+	offset := (args.PageNum - 1) * args.PageSize
+	rows, err := conn(ctx, r.db).Query(ctx, query, companyPublicID, args.PageSize, offset)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving invitations: %v", err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	res := make([]*models.Invitation, 0)
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning invitation: %v", err)
+			return nil, 0, err
+		}
+		res = append(res, invitation)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over invitation rows: %v", err)
+		return nil, 0, err
+	}
+
+	var totalCount int
+	query = `SELECT COUNT(*) FROM company_invitations WHERE company_public_id = $1`
+	if err := conn(ctx, r.db).QueryRow(ctx, query, companyPublicID).Scan(&totalCount); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving invitations count: %v", err)
+		return nil, 0, err
+	}
+
+	return res, totalCount, nil
+}
+
+// RevokeInvitation revokes the invitation of the company. An accepted invitation can no longer be revoked.
+func (r *membershipRepository) RevokeInvitation(ctx context.Context, companyPublicID, invitationPublicID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		UPDATE company_invitations
+		SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE company_public_id = $1 AND public_id = $2 AND accepted_at IS NULL`
+
+	tag, err := conn(ctx, r.db).Exec(ctx, query, companyPublicID, invitationPublicID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while revoking invitation: %v", err)
+		return err
+	}
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+
+	var exists bool
+	query = `SELECT EXISTS (SELECT 1 FROM company_invitations WHERE company_public_id = $1 AND public_id = $2)`
+	if err := conn(ctx, r.db).QueryRow(ctx, query, companyPublicID, invitationPublicID).Scan(&exists); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while checking invitation existence: %v", err)
+		return err
+	}
+	if !exists {
+		return models.ErrInvitationNotFound
+	}
+	return models.ErrInvitationInvalid
+}
+
+// AcceptInvitation marks the pending invitation with the token hash as accepted by the recruiter.
+// An invitation the recruiter accepted already is returned again, so accepting can be retried.
+func (r *membershipRepository) AcceptInvitation(ctx context.Context, tokenHash, recruiterPublicID string) (*models.Invitation, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		UPDATE company_invitations
+		SET accepted_at = NOW(), accepted_by = $2
+		WHERE token_hash = $1 AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()
+		RETURNING ` + invitationColumns
+
+	invitation, err := scanInvitation(conn(ctx, r.db).QueryRow(ctx, query, tokenHash, recruiterPublicID))
+	if err == nil {
+		return invitation, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while accepting invitation: %v", err)
+		return nil, err
+	}
+
+	query = `SELECT ` + invitationColumns + ` FROM company_invitations WHERE token_hash = $1`
+	invitation, err = scanInvitation(conn(ctx, r.db).QueryRow(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrInvitationNotFound
+		}
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving invitation: %v", err)
+		return nil, err
+	}
+	if invitation.AcceptedBy != nil && *invitation.AcceptedBy == recruiterPublicID {
+		return invitation, nil
+	}
+	return nil, models.ErrInvitationInvalid
+}
+
+// CreateJoinRequest creates a pending request of the recruiter to join the company. If the recruiter
+// has a pending request to the company already, it is returned instead.
+func (r *membershipRepository) CreateJoinRequest(ctx context.Context, joinRequest *models.JoinRequest) (*models.JoinRequest, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	var res *models.JoinRequest
+	err := inTx(ctx, r.db, r.cfg, r.logger, false, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+		var publicID string
+		query := `
+			INSERT INTO company_join_requests (company_public_id, recruiter_public_id, message)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (company_public_id, recruiter_public_id) WHERE status = 'pending' DO NOTHING
+			RETURNING public_id`
+		err := tx.QueryRow(ctx, query, joinRequest.CompanyPublicID, joinRequest.RecruiterPublicID, joinRequest.Message).Scan(&publicID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			query = `
+				SELECT public_id
+				FROM company_join_requests
+				WHERE company_public_id = $1 AND recruiter_public_id = $2 AND status = 'pending'`
+			err = tx.QueryRow(ctx, query, joinRequest.CompanyPublicID, joinRequest.RecruiterPublicID).Scan(&publicID)
+		}
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while creating join request: %v", err)
+			return err
+		}
+
+		res, err = r.getJoinRequest(ctx, tx, publicID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetJoinRequests retrieves the join requests of the company with the status, or with any status
+// if it is empty, the latest first
+func (r *membershipRepository) GetJoinRequests(ctx context.Context, companyPublicID, status string, args *models.SearchArgs) ([]*models.JoinRequest, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		SELECT ` + joinRequestColumns + `
+		FROM company_join_requests j
+		JOIN users u ON u.public_id = j.recruiter_public_id
+		WHERE j.company_public_id = $1 AND ($2 = '' OR j.status = $2)
+		ORDER BY j.created_at DESC, j.id DESC
+		LIMIT $3 OFFSET $4`
+
+	offset := (args.PageNum - 1) * args.PageSize
+	rows, err := conn(ctx, r.db).Query(ctx, query, companyPublicID, status, args.PageSize, offset)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving join requests: %v", err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	res := make([]*models.JoinRequest, 0)
+	for rows.Next() {
+		joinRequest, err := scanJoinRequest(rows)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning join request: %v", err)
+			return nil, 0, err
+		}
+		res = append(res, joinRequest)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over join request rows: %v", err)
+		return nil, 0, err
+	}
+
+	var totalCount int
+	query = `SELECT COUNT(*) FROM company_join_requests WHERE company_public_id = $1 AND ($2 = '' OR status = $2)`
+	if err := conn(ctx, r.db).QueryRow(ctx, query, companyPublicID, status).Scan(&totalCount); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving join requests count: %v", err)
+		return nil, 0, err
+	}
+
+	return res, totalCount, nil
+}
+
+// DecideJoinRequest approves or rejects the pending join request of the company, as given by status
+func (r *membershipRepository) DecideJoinRequest(ctx context.Context, companyPublicID, joinRequestPublicID, status, deciderPublicID string) (*models.JoinRequest, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	var res *models.JoinRequest
+	err := inTx(ctx, r.db, r.cfg, r.logger, false, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+		query := `
+			UPDATE company_join_requests
+			SET status = $3, decided_by = $4, decided_at = NOW()
+			WHERE company_public_id = $1 AND public_id = $2 AND status = 'pending'`
+		tag, err := tx.Exec(ctx, query, companyPublicID, joinRequestPublicID, status, deciderPublicID)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while deciding join request: %v", err)
+			return err
+		}
+
+		res, err = r.getJoinRequest(ctx, tx, joinRequestPublicID)
+		if err != nil {
+			return err
+		}
+		if res.CompanyPublicID != companyPublicID {
+			return models.ErrJoinRequestNotFound
+		}
+		if tag.RowsAffected() == 0 {
+			return models.ErrJoinRequestDecided
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (r *membershipRepository) getJoinRequest(ctx context.Context, tx dbtx, publicID string) (*models.JoinRequest, error) {
+	query := `
+		SELECT ` + joinRequestColumns + `
+		FROM company_join_requests j
+		JOIN users u ON u.public_id = j.recruiter_public_id
+		WHERE j.public_id = $1`
+
+	joinRequest, err := scanJoinRequest(tx.QueryRow(ctx, query, publicID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrJoinRequestNotFound
+		}
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving join request: %v", err)
+		return nil, err
+	}
+	return joinRequest, nil
+}
+
+func scanInvitation(row pgx.Row) (*models.Invitation, error) {
+	invitation := &models.Invitation{}
+	err := row.Scan(
+		&invitation.PublicID,
+		&invitation.CompanyPublicID,
+		&invitation.Email,
+		&invitation.Role,
+		&invitation.Status,
+		&invitation.InvitedBy,
+		&invitation.AcceptedBy,
+		&invitation.ExpiresAt,
+		&invitation.CreatedAt,
+		&invitation.AcceptedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func scanJoinRequest(row pgx.Row) (*models.JoinRequest, error) {
+	joinRequest := &models.JoinRequest{}
+	err := row.Scan(
+		&joinRequest.PublicID,
+		&joinRequest.CompanyPublicID,
+		&joinRequest.RecruiterPublicID,
+		&joinRequest.FirstName,
+		&joinRequest.LastName,
+		&joinRequest.Message,
+		&joinRequest.Status,
+		&joinRequest.DecidedBy,
+		&joinRequest.CreatedAt,
+		&joinRequest.DecidedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return joinRequest, nil
+}
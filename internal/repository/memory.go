@@ -0,0 +1,342 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/google/uuid"
+)
+
+// MemoryStore keeps candidates, recruiters and companies in memory, for tests and the demo mode.
+// The memory repositories behave like the Postgres ones, which the conformance suite checks.
+// Nothing is persisted, the data is lost when the process stops.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	nextID int
+	users  map[string]*memoryUser
+	// skills are the names of the skills by their key, the first spelling is kept like in Postgres
+	skills map[string]string
+	// the slices are kept in insertion order, which is the order of the serial IDs in Postgres
+	candidates []*memoryCandidate
+	recruiters []*memoryRecruiter
+	companies  []*models.Company
+	positions  []*memoryPosition
+	interviews []*memoryInterview
+}
+
+type memoryUser struct {
+	firstName string
+	lastName  string
+	photo     string
+}
+
+type memoryCandidate struct {
+	publicID        string
+	currentPosition string
+	education       string
+	resume          string
+	bio             string
+	// skills are keyed by skillKey
+	skills map[string]struct{}
+}
+
+type memoryRecruiter struct {
+	publicID        string
+	companyPublicID string
+	role            string
+}
+
+type memoryPosition struct {
+	models.Position
+	recruiterPublicID string
+}
+
+type memoryInterview struct {
+	publicID          string
+	candidatePublicID string
+	positionPublicID  string
+	results           []byte
+}
+
+// NewMemoryStore creates an empty store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		users:  make(map[string]*memoryUser),
+		skills: make(map[string]string),
+	}
+}
+
+// NewMemory creates repositories backed by the store. Only candidates, recruiters and companies
+// are kept in memory, the other repositories return models.ErrNotSupported.
+func NewMemory(store *MemoryStore) *Repository {
+	return &Repository{
+		RecruiterRepository:    NewMemoryRecruiterRepository(store),
+		CandidateRepository:    NewMemoryCandidateRepository(store),
+		CompanyRepository:      NewMemoryCompanyRepository(store),
+		ShortlistRepository:    unsupportedShortlistRepository{},
+		NoteRepository:         unsupportedNoteRepository{},
+		SavedSearchRepository:  unsupportedSavedSearchRepository{},
+		NotificationRepository: unsupportedNotificationRepository{},
+		EventRepository:        unsupportedEventRepository{},
+		WebhookRepository:      unsupportedWebhookRepository{},
+		ProvisioningRepository: unsupportedProvisioningRepository{},
+		MembershipRepository:   unsupportedMembershipRepository{},
+		DomainRepository:       unsupportedDomainRepository{},
+		UnitOfWork:             memoryUnitOfWork{},
+	}
+}
+
+func (s *MemoryStore) CreateCandidate(ctx context.Context, candidate *models.Candidate) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	publicID := uuid.NewString()
+	s.users[publicID] = &memoryUser{
+		firstName: value(candidate.FirstName),
+		lastName:  value(candidate.LastName),
+		photo:     value(candidate.Photo),
+	}
+	s.candidates = append(s.candidates, &memoryCandidate{
+		publicID:        publicID,
+		currentPosition: value(candidate.CurrentPosition),
+		education:       value(candidate.Education),
+		resume:          value(candidate.Resume),
+		bio:             value(candidate.Bio),
+		skills:          make(map[string]struct{}),
+	})
+	return publicID, nil
+}
+
+func (s *MemoryStore) CreateRecruiter(ctx context.Context, recruiter *models.Recruiter) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	publicID := uuid.NewString()
+	s.users[publicID] = &memoryUser{
+		firstName: recruiter.FirstName,
+		lastName:  recruiter.LastName,
+		photo:     recruiter.Photo,
+	}
+	role := recruiter.Role
+	if role == "" {
+		role = models.RoleRecruiter
+	}
+	s.recruiters = append(s.recruiters, &memoryRecruiter{
+		publicID:        publicID,
+		companyPublicID: recruiter.CompanyPublicID,
+		role:            role,
+	})
+	return publicID, nil
+}
+
+func (s *MemoryStore) CreatePosition(ctx context.Context, recruiterPublicID string, position *models.Position) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	publicID := uuid.NewString()
+	s.positions = append(s.positions, &memoryPosition{
+		Position: models.Position{
+			PublicID: publicID,
+			Name:     position.Name,
+			Status:   position.Status,
+		},
+		recruiterPublicID: recruiterPublicID,
+	})
+	return publicID, nil
+}
+
+func (s *MemoryStore) CreateInterview(ctx context.Context, candidatePublicID, positionPublicID string, results []byte) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.candidate(candidatePublicID) == nil {
+		return "", fmt.Errorf("candidate %s does not exist", candidatePublicID)
+	}
+	if s.position(positionPublicID) == nil {
+		return "", fmt.Errorf("position %s does not exist", positionPublicID)
+	}
+	publicID := uuid.NewString()
+	s.interviews = append(s.interviews, &memoryInterview{
+		publicID:          publicID,
+		candidatePublicID: candidatePublicID,
+		positionPublicID:  positionPublicID,
+		results:           append([]byte(nil), results...),
+	})
+	return publicID, nil
+}
+
+// candidate returns the candidate with the public ID, or nil. The caller must hold the lock.
+func (s *MemoryStore) candidate(publicID string) *memoryCandidate {
+	for _, c := range s.candidates {
+		if c.publicID == publicID {
+			return c
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) recruiter(publicID string) *memoryRecruiter {
+	for _, r := range s.recruiters {
+		if r.publicID == publicID {
+			return r
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) company(publicID string) *models.Company {
+	for _, c := range s.companies {
+		if c.PublicID == publicID {
+			return c
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) position(publicID string) *memoryPosition {
+	for _, p := range s.positions {
+		if p.PublicID == publicID {
+			return p
+		}
+	}
+	return nil
+}
+
+// interviewResults pages through the interviews accepted by the filter
+func (s *MemoryStore) interviewResults(searchArgs *models.SearchArgs, filter func(i *memoryInterview) bool) ([]*models.InterviewResults, int) {
+	var matched []*memoryInterview
+	for _, i := range s.interviews {
+		if filter(i) {
+			matched = append(matched, i)
+		}
+	}
+	start, end := page(len(matched), searchArgs)
+	res := make([]*models.InterviewResults, 0, end-start)
+	for _, i := range matched[start:end] {
+		res = append(res, &models.InterviewResults{
+			PublicID:         i.publicID,
+			PositionPublicID: i.positionPublicID,
+			RawResult:        append([]byte(nil), i.results...),
+		})
+	}
+	return res, len(matched)
+}
+
+// page returns the bounds of the requested page in a list of n items, like OFFSET and LIMIT
+func page(n int, args *models.SearchArgs) (int, int) {
+	start := (args.PageNum - 1) * args.PageSize
+	if start < 0 {
+		start = 0
+	}
+	if start > n {
+		start = n
+	}
+	end := start + args.PageSize
+	if args.PageSize < 0 || end > n {
+		end = n
+	}
+	return start, end
+}
+
+// contains reports whether s contains search ignoring case, with the wildcards of the
+// LIKE patterns built by the Postgres repositories: % matches any text, _ any character
+// and a backslash escapes the next character.
+func contains(s, search string) bool {
+	var re strings.Builder
+	re.WriteString("(?is)")
+	escaped := false
+	for _, r := range search {
+		switch {
+		case escaped:
+			re.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			re.WriteString(".*")
+		case r == '_':
+			re.WriteString(".")
+		default:
+			re.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	matched, err := regexp.MatchString(re.String(), s)
+	return err == nil && matched
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+// Demo lists the public IDs of the demo records, to sign access tokens for them
+type Demo struct {
+	CompanyPublicID    string
+	RecruiterPublicID  string
+	CandidatePublicIDs []string
+}
+
+// SeedDemo fills the store with a company, a recruiter with a position and candidates
+// with skills and interview results, so the demo mode has data to show.
+func (s *MemoryStore) SeedDemo(ctx context.Context) (*Demo, error) {
+	demo := &Demo{}
+	var err error
+	demo.CompanyPublicID, err = NewMemoryCompanyRepository(s).CreateCompany(ctx, &models.Company{
+		Name:        "Acme",
+		Description: "Demo company",
+	})
+	if err != nil {
+		return nil, err
+	}
+	demo.RecruiterPublicID, err = s.CreateRecruiter(ctx, &models.Recruiter{
+		CompanyPublicID: demo.CompanyPublicID,
+		Role:            models.RoleOwner,
+		FirstName:       "Rita",
+		LastName:        "Recruiter",
+	})
+	if err != nil {
+		return nil, err
+	}
+	positionID, err := s.CreatePosition(ctx, demo.RecruiterPublicID, &models.Position{Name: "Backend developer", Status: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := NewMemoryCandidateRepository(s)
+	for _, c := range []struct {
+		firstName, lastName, position string
+		skills                        []string
+		score                         int
+	}{
+		{"John", "Doe", "Go developer", []string{"Go", "PostgreSQL"}, 82},
+		{"Jane", "Smith", "Python developer", []string{"Python", "Django"}, 74},
+	} {
+		publicID, err := s.CreateCandidate(ctx, &models.Candidate{
+			FirstName:       ptr(c.firstName),
+			LastName:        ptr(c.lastName),
+			CurrentPosition: ptr(c.position),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := candidates.AddSkillsToCandidate(ctx, publicID, c.skills); err != nil {
+			return nil, err
+		}
+		results := fmt.Sprintf(`{"questions": [], "score": %d}`, c.score)
+		if _, err := s.CreateInterview(ctx, publicID, positionID, []byte(results)); err != nil {
+			return nil, err
+		}
+		demo.CandidatePublicIDs = append(demo.CandidatePublicIDs, publicID)
+	}
+	return demo, nil
+}
+
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
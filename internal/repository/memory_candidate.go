@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+)
+
+// memoryCandidateRepository keeps candidates in a MemoryStore.
+// The events are dropped, there is no outbox to relay them from in memory.
+type memoryCandidateRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryCandidateRepository creates a candidate repository backed by the store.
+func NewMemoryCandidateRepository(store *MemoryStore) CandidateRepository {
+	return &memoryCandidateRepository{
+		store: store,
+	}
+}
+
+func (r *memoryCandidateRepository) GetCandidatesBySearch(ctx context.Context, searchArgs *models.SearchArgs) ([]*models.Candidate, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var matched []*memoryCandidate
+	for _, c := range r.store.candidates {
+		user := r.store.users[c.publicID]
+		if user != nil && (contains(user.firstName, searchArgs.Search) || contains(user.lastName, searchArgs.Search)) && matchesFilters(c, searchArgs.Filters) {
+			matched = append(matched, c)
+		}
+	}
+
+	start, end := page(len(matched), searchArgs)
+	candidates := make([]*models.Candidate, 0, end-start)
+	for _, c := range matched[start:end] {
+		candidates = append(candidates, r.toModel(c))
+	}
+	return candidates, len(matched), nil
+}
+
+// matchesFilters mirrors the filters of candidateSearchCondition
+func matchesFilters(c *memoryCandidate, filters *models.CandidateFilters) bool {
+	if filters == nil {
+		return true
+	}
+	if filters.CurrentPosition != "" && !contains(c.currentPosition, "%"+filters.CurrentPosition+"%") {
+		return false
+	}
+	if filters.Education != "" && !contains(c.education, "%"+filters.Education+"%") {
+		return false
+	}
+	for _, skill := range filters.Skills {
+		if _, ok := c.skills[skillKey(skill)]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *memoryCandidateRepository) GetCandidateByPublicID(ctx context.Context, publicID string) (*models.Candidate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	c := r.store.candidate(publicID)
+	if c == nil || r.store.users[publicID] == nil {
+		return nil, models.ErrUserNotFound
+	}
+	result := r.toModel(c)
+
+	var interviewIDs []string
+	for _, i := range r.store.interviews {
+		if i.candidatePublicID == publicID {
+			interviewIDs = append(interviewIDs, i.publicID)
+		}
+	}
+	sort.Strings(interviewIDs)
+	for _, id := range interviewIDs {
+		result.Interviews = append(result.Interviews, models.Interview{
+			PublicID: id,
+		})
+	}
+	return result, nil
+}
+
+func (r *memoryCandidateRepository) Exists(ctx context.Context, publicID string) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.candidate(publicID) != nil, nil
+}
+
+func (r *memoryCandidateRepository) AddSkillsToCandidate(ctx context.Context, candidateID string, skills []string, events ...*models.DomainEvent) (*models.SkillsChange, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	c := r.store.candidate(candidateID)
+	if c == nil {
+		return nil, models.ErrUserNotFound
+	}
+	change := newSkillsChange()
+	change.Added = r.addSkills(c, normalizeSkills(skills))
+	return r.finishSkillsChange(c, change), nil
+}
+
+func (r *memoryCandidateRepository) UpdateCandidateByID(ctx context.Context, candidateID string, updateData *models.Candidate, events ...*models.DomainEvent) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if c := r.store.candidate(candidateID); c != nil {
+		set(&c.currentPosition, updateData.CurrentPosition)
+		set(&c.education, updateData.Education)
+		set(&c.resume, updateData.Resume)
+		set(&c.bio, updateData.Bio)
+	}
+	if user := r.store.users[candidateID]; user != nil {
+		set(&user.firstName, updateData.FirstName)
+		set(&user.lastName, updateData.LastName)
+		set(&user.photo, updateData.Photo)
+	}
+	return nil
+}
+
+// DeleteCandidateByID removes the candidate with its skills and interviews, but keeps the user
+// like the Postgres repository does.
+func (r *memoryCandidateRepository) DeleteCandidateByID(ctx context.Context, candidateID string, events ...*models.DomainEvent) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	candidates := r.store.candidates[:0]
+	for _, c := range r.store.candidates {
+		if c.publicID != candidateID {
+			candidates = append(candidates, c)
+		}
+	}
+	r.store.candidates = candidates
+
+	interviews := r.store.interviews[:0]
+	for _, i := range r.store.interviews {
+		if i.candidatePublicID != candidateID {
+			interviews = append(interviews, i)
+		}
+	}
+	r.store.interviews = interviews
+	return nil
+}
+
+func (r *memoryCandidateRepository) DeleteSkillsFromCandidate(ctx context.Context, candidateID string, skills []string, events ...*models.DomainEvent) (*models.SkillsChange, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	c := r.store.candidate(candidateID)
+	if c == nil {
+		return newSkillsChange(), nil
+	}
+	change := newSkillsChange()
+	change.Removed = r.removeSkills(c, normalizeSkills(skills), false)
+	return r.finishSkillsChange(c, change), nil
+}
+
+func (r *memoryCandidateRepository) ReplaceCandidateSkills(ctx context.Context, candidateID string, skills []string, events ...*models.DomainEvent) (*models.SkillsChange, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	c := r.store.candidate(candidateID)
+	if c == nil {
+		return nil, models.ErrUserNotFound
+	}
+	skills = normalizeSkills(skills)
+	change := newSkillsChange()
+	change.Added = r.addSkills(c, skills)
+	change.Removed = r.removeSkills(c, skills, true)
+	return r.finishSkillsChange(c, change), nil
+}
+
+// addSkills adds the skills to the candidate and returns the names of the ones it did not have.
+// The caller must hold the lock.
+func (r *memoryCandidateRepository) addSkills(c *memoryCandidate, skills []string) []string {
+	added := []string{}
+	for _, skill := range skills {
+		key := skillKey(skill)
+		if _, ok := r.store.skills[key]; !ok {
+			r.store.skills[key] = skill
+		}
+		if _, ok := c.skills[key]; !ok {
+			c.skills[key] = struct{}{}
+			added = append(added, r.store.skills[key])
+		}
+	}
+	return added
+}
+
+// removeSkills removes the given skills from the candidate, or with except every other skill, and
+// returns the names of the ones removed. The caller must hold the lock.
+func (r *memoryCandidateRepository) removeSkills(c *memoryCandidate, skills []string, except bool) []string {
+	given := make(map[string]bool, len(skills))
+	for _, skill := range skills {
+		given[skillKey(skill)] = true
+	}
+	removed := []string{}
+	for key := range c.skills {
+		if given[key] != except {
+			delete(c.skills, key)
+			removed = append(removed, r.store.skills[key])
+		}
+	}
+	return removed
+}
+
+// finishSkillsChange sorts the names of the change and fills the skills the candidate has.
+// The caller must hold the lock.
+func (r *memoryCandidateRepository) finishSkillsChange(c *memoryCandidate, change *models.SkillsChange) *models.SkillsChange {
+	sort.Strings(change.Added)
+	sort.Strings(change.Removed)
+	change.Skills = r.skillNames(c)
+	return change
+}
+
+// skillNames returns the sorted names of the skills of the candidate. The caller must hold the lock.
+func (r *memoryCandidateRepository) skillNames(c *memoryCandidate) []string {
+	names := make([]string, 0, len(c.skills))
+	for key := range c.skills {
+		names = append(names, r.store.skills[key])
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *memoryCandidateRepository) GetInterviewsByPublicID(ctx context.Context, publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	res, count := r.store.interviewResults(searchArgs, func(i *memoryInterview) bool {
+		return i.candidatePublicID == publicID
+	})
+	return res, count, nil
+}
+
+// toModel copies the candidate, so callers cannot change the store. The caller must hold the lock.
+func (r *memoryCandidateRepository) toModel(c *memoryCandidate) *models.Candidate {
+	user := r.store.users[c.publicID]
+	skills := r.skillNames(c)
+
+	candidate := &models.Candidate{
+		PublicID:        ptr(c.publicID),
+		FirstName:       ptr(user.firstName),
+		LastName:        ptr(user.lastName),
+		CurrentPosition: ptr(c.currentPosition),
+		Resume:          ptr(c.resume),
+		Bio:             ptr(c.bio),
+		Skills:          make([]*string, 0, len(skills)),
+		Photo:           ptr(user.photo),
+		Education:       ptr(c.education),
+	}
+	for _, skill := range skills {
+		candidate.Skills = append(candidate.Skills, ptr(skill))
+	}
+	return candidate
+}
+
+func set(field *string, value *string) {
+	if value != nil {
+		*field = *value
+	}
+}
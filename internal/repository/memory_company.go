@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/google/uuid"
+)
+
+type memoryCompanyRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryCompanyRepository creates a company repository backed by the store.
+func NewMemoryCompanyRepository(store *MemoryStore) CompanyRepository {
+	return &memoryCompanyRepository{
+		store: store,
+	}
+}
+
+// CreateCompany stores a copy of the company. The events are stamped with its public ID like
+// the Postgres repository does, then dropped.
+func (r *memoryCompanyRepository) CreateCompany(ctx context.Context, company *models.Company, events ...*models.DomainEvent) (string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.nextID++
+	publicID := uuid.NewString()
+	r.store.companies = append(r.store.companies, &models.Company{
+		ID:          r.store.nextID,
+		PublicID:    publicID,
+		Name:        company.Name,
+		Logo:        company.Logo,
+		Description: company.Description,
+	})
+	for _, event := range events {
+		if event != nil {
+			event.AggregatePublicID = publicID
+		}
+	}
+	return publicID, nil
+}
+
+// UpdateCompany overwrites every field of the company, it does nothing if the company does not exist
+func (r *memoryCompanyRepository) UpdateCompany(ctx context.Context, company *models.Company) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if stored := r.store.company(company.PublicID); stored != nil {
+		stored.Name = company.Name
+		stored.Logo = company.Logo
+		stored.Description = company.Description
+	}
+	return nil
+}
+
+// GetCompany returns a copy of the company, or nil if it does not exist
+func (r *memoryCompanyRepository) GetCompany(ctx context.Context, publicID string) (*models.Company, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	company := r.store.company(publicID)
+	if company == nil {
+		return nil, nil
+	}
+	res := *company
+	return &res, nil
+}
+
+func (r *memoryCompanyRepository) GetCompanies(ctx context.Context, args *models.SearchArgs) ([]*models.Company, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var matched []*models.Company
+	for _, company := range r.store.companies {
+		if contains(company.Name, args.Search) {
+			matched = append(matched, company)
+		}
+	}
+	start, end := page(len(matched), args)
+	companies := make([]*models.Company, 0, end-start)
+	for _, company := range matched[start:end] {
+		res := *company
+		companies = append(companies, &res)
+	}
+	return companies, len(matched), nil
+}
+
+func (r *memoryCompanyRepository) Exists(ctx context.Context, publicID string) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.company(publicID) != nil, nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+)
+
+type memoryRecruiterRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryRecruiterRepository creates a recruiter repository backed by the store.
+func NewMemoryRecruiterRepository(store *MemoryStore) RecruiterRepository {
+	return &memoryRecruiterRepository{
+		store: store,
+	}
+}
+
+func (r *memoryRecruiterRepository) Exists(ctx context.Context, publicID string) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.recruiter(publicID) != nil, nil
+}
+
+func (r *memoryRecruiterRepository) GetRecruiter(ctx context.Context, publicID string) (*models.Recruiter, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rec := r.store.recruiter(publicID)
+	user := r.store.users[publicID]
+	if rec == nil || user == nil {
+		return nil, models.ErrUserNotFound
+	}
+
+	recruiter := &models.Recruiter{
+		PublicID:        rec.publicID,
+		CompanyPublicID: rec.companyPublicID,
+		Role:            rec.role,
+		FirstName:       user.firstName,
+		LastName:        user.lastName,
+		Photo:           user.photo,
+		Positions:       make([]models.Position, 0),
+	}
+	if company := r.store.company(rec.companyPublicID); company != nil {
+		recruiter.Company = &models.Company{
+			PublicID:    company.PublicID,
+			Name:        company.Name,
+			Description: company.Description,
+		}
+	}
+	for _, p := range r.store.positions {
+		if p.recruiterPublicID == publicID {
+			recruiter.Positions = append(recruiter.Positions, p.Position)
+		}
+	}
+	return recruiter, nil
+}
+
+func (r *memoryRecruiterRepository) GetCompanyPublicID(ctx context.Context, publicID string) (string, error) {
+	membership, err := r.GetMembership(ctx, publicID)
+	if err != nil {
+		return "", err
+	}
+	if membership.CompanyPublicID == "" {
+		return "", models.ErrNoCompany
+	}
+	return membership.CompanyPublicID, nil
+}
+
+func (r *memoryRecruiterRepository) GetMembership(ctx context.Context, publicID string) (*models.Membership, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rec := r.store.recruiter(publicID)
+	if rec == nil {
+		return nil, models.ErrUserNotFound
+	}
+	return &models.Membership{CompanyPublicID: rec.companyPublicID, Role: rec.role}, nil
+}
+
+func (r *memoryRecruiterRepository) FilterByCompany(ctx context.Context, companyPublicID string, publicIDs []string) ([]string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(publicIDs))
+	for _, id := range publicIDs {
+		wanted[id] = true
+	}
+	res := make([]string, 0, len(publicIDs))
+	for _, rec := range r.store.recruiters {
+		if rec.companyPublicID == companyPublicID && wanted[rec.publicID] {
+			res = append(res, rec.publicID)
+		}
+	}
+	return res, nil
+}
+
+func (r *memoryRecruiterRepository) GetInterviewsByPublicID(ctx context.Context, publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	res, count := r.store.interviewResults(searchArgs, func(i *memoryInterview) bool {
+		p := r.store.position(i.positionPublicID)
+		return p != nil && p.recruiterPublicID == publicID
+	})
+	return res, count, nil
+}
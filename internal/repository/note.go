@@ -0,0 +1,420 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
+)
+
+// noteRepository represents the repository for managing private recruiter notes in the database.
+type noteRepository struct {
+	db     *pgxpool.Pool
+	cfg    *config.DBConf
+	logger *zap.SugaredLogger
+}
+
+// NewNoteRepository creates a new instance of noteRepository.
+func NewNoteRepository(db *pgxpool.Pool, cfg *config.DBConf, logger *zap.SugaredLogger) NoteRepository {
+	return &noteRepository{
+		db:     db,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+const noteColumns = `
+	n.public_id,
+	n.company_public_id,
+	n.author_public_id,
+	n.subject_type,
+	n.subject_public_id,
+	n.body,
+	n.rating,
+	COALESCE((SELECT array_agg(m.recruiter_public_id::text) FROM recruiter_note_mentions m WHERE m.note_id = n.id), '{}'),
+	n.version,
+	n.created_at,
+	n.updated_at`
+
+func scanNote(row pgx.Row) (*models.Note, error) {
+	note := &models.Note{}
+	err := row.Scan(
+		&note.PublicID,
+		&note.CompanyPublicID,
+		&note.AuthorPublicID,
+		&note.SubjectType,
+		&note.SubjectPublicID,
+		&note.Body,
+		&note.Rating,
+		&note.Mentions,
+		&note.Version,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+	)
+	return note, err
+}
+
+// SubjectExists checks if the candidate or interview a note refers to exists
+func (r *noteRepository) SubjectExists(ctx context.Context, subjectType, subjectPublicID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	var query string
+	switch subjectType {
+	case models.NoteSubjectCandidate:
+		query = `SELECT EXISTS(SELECT 1 FROM candidates WHERE public_id = $1)`
+	case models.NoteSubjectInterview:
+		query = `SELECT EXISTS(SELECT 1 FROM interviews WHERE public_id = $1)`
+	default:
+		return false, models.ErrInvalidInput
+	}
+
+	var exists bool
+	err := conn(ctx, r.db).QueryRow(ctx, query, subjectPublicID).Scan(&exists)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while checking note subject existence: %v", err)
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// CreateNote creates a note together with its first revision and mentions, returning the note's public ID
+func (r *noteRepository) CreateNote(ctx context.Context, note *models.Note) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error beginning transaction: %v", err)
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO recruiter_notes (company_public_id, author_public_id, subject_type, subject_public_id, body, rating)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, public_id`
+
+	var (
+		noteID   int
+		publicID string
+	)
+	err = tx.QueryRow(ctx, query,
+		note.CompanyPublicID,
+		note.AuthorPublicID,
+		note.SubjectType,
+		note.SubjectPublicID,
+		note.Body,
+		note.Rating,
+	).Scan(&noteID, &publicID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while creating note: %v", err)
+		return "", err
+	}
+
+	if err = insertNoteRevision(ctx, tx, noteID, note.AuthorPublicID); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while creating note revision: %v", err)
+		return "", err
+	}
+	if err = replaceNoteMentions(ctx, tx, noteID, note.Mentions); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while creating note mentions: %v", err)
+		return "", err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error committing transaction: %v", err)
+		return "", err
+	}
+
+	return publicID, nil
+}
+
+// GetNote retrieves a note by its public ID
+func (r *noteRepository) GetNote(ctx context.Context, publicID string) (*models.Note, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `SELECT ` + noteColumns + `
+		FROM recruiter_notes n
+		WHERE n.public_id = $1`
+
+	note, err := scanNote(conn(ctx, r.db).QueryRow(ctx, query, publicID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrNoteNotFound
+		}
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving note: %v", err)
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// GetNotes retrieves the notes left by the company's recruiters on a candidate or interview, newest first
+func (r *noteRepository) GetNotes(ctx context.Context, companyPublicID, subjectType, subjectPublicID string, args *models.SearchArgs) ([]*models.Note, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `SELECT ` + noteColumns + `
+		FROM recruiter_notes n
+		WHERE n.company_public_id = $1 AND n.subject_type = $2 AND n.subject_public_id = $3
+		ORDER BY n.created_at DESC, n.id DESC
+		LIMIT $4 OFFSET $5`
+
+	countQuery := `
+		SELECT COUNT(*)
+		FROM recruiter_notes n
+		WHERE n.company_public_id = $1 AND n.subject_type = $2 AND n.subject_public_id = $3`
+
+	offset := (args.PageNum - 1) * args.PageSize
+	notes, err := r.queryNotes(ctx, query, companyPublicID, subjectType, subjectPublicID, args.PageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var totalCount int
+	err = conn(ctx, r.db).QueryRow(ctx, countQuery, companyPublicID, subjectType, subjectPublicID).Scan(&totalCount)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving notes count: %v", err)
+		return nil, 0, err
+	}
+
+	return notes, totalCount, nil
+}
+
+// GetMentionedNotes retrieves the company's notes that mention the recruiter, newest first
+func (r *noteRepository) GetMentionedNotes(ctx context.Context, recruiterPublicID, companyPublicID string, args *models.SearchArgs) ([]*models.Note, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `SELECT ` + noteColumns + `
+		FROM recruiter_notes n
+		JOIN recruiter_note_mentions rm ON rm.note_id = n.id
+		WHERE rm.recruiter_public_id = $1 AND n.company_public_id = $2
+		ORDER BY n.updated_at DESC, n.id DESC
+		LIMIT $3 OFFSET $4`
+
+	countQuery := `
+		SELECT COUNT(*)
+		FROM recruiter_notes n
+		JOIN recruiter_note_mentions rm ON rm.note_id = n.id
+		WHERE rm.recruiter_public_id = $1 AND n.company_public_id = $2`
+
+	offset := (args.PageNum - 1) * args.PageSize
+	notes, err := r.queryNotes(ctx, query, recruiterPublicID, companyPublicID, args.PageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var totalCount int
+	err = conn(ctx, r.db).QueryRow(ctx, countQuery, recruiterPublicID, companyPublicID).Scan(&totalCount)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving mentioned notes count: %v", err)
+		return nil, 0, err
+	}
+
+	return notes, totalCount, nil
+}
+
+// UpdateNote updates the body and rating of a note, bumping its version and recording the new revision
+func (r *noteRepository) UpdateNote(ctx context.Context, note *models.Note, editorPublicID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error beginning transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE recruiter_notes
+		SET
+			body = COALESCE($2, body),
+			rating = COALESCE($3, rating),
+			version = version + 1,
+			updated_at = NOW()
+		WHERE public_id = $1
+		RETURNING id`
+
+	var noteID int
+	err = tx.QueryRow(ctx, query, note.PublicID, note.Body, note.Rating).Scan(&noteID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrNoteNotFound
+		}
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while updating note: %v", err)
+		return err
+	}
+
+	if err = insertNoteRevision(ctx, tx, noteID, editorPublicID); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while creating note revision: %v", err)
+		return err
+	}
+	if note.Mentions != nil {
+		if err = replaceNoteMentions(ctx, tx, noteID, note.Mentions); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while updating note mentions: %v", err)
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error committing transaction: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteNote deletes a note together with its history and mentions
+func (r *noteRepository) DeleteNote(ctx context.Context, publicID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `DELETE FROM recruiter_notes WHERE public_id = $1`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, publicID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while deleting note: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetNoteRevisions retrieves every revision of a note, oldest first
+func (r *noteRepository) GetNoteRevisions(ctx context.Context, publicID string) ([]*models.NoteRevision, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		SELECT nr.version, nr.body, nr.rating, nr.edited_by, nr.edited_at
+		FROM recruiter_note_revisions nr
+		JOIN recruiter_notes n ON n.id = nr.note_id
+		WHERE n.public_id = $1
+		ORDER BY nr.version`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, publicID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving note revisions: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]*models.NoteRevision, 0)
+	for rows.Next() {
+		revision := &models.NoteRevision{}
+		err := rows.Scan(
+			&revision.Version,
+			&revision.Body,
+			&revision.Rating,
+			&revision.EditedBy,
+			&revision.EditedAt,
+		)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning note revision: %v", err)
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over note revision rows: %v", err)
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (r *noteRepository) queryNotes(ctx context.Context, query string, args ...interface{}) ([]*models.Note, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving notes: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	notes := make([]*models.Note, 0)
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning note: %v", err)
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over note rows: %v", err)
+		return nil, err
+	}
+
+	return notes, nil
+}
+
+// insertNoteRevision snapshots the current body and rating of the note as a new revision
+func insertNoteRevision(ctx context.Context, tx pgx.Tx, noteID int, editorPublicID string) error {
+	query := `
+		INSERT INTO recruiter_note_revisions (note_id, version, body, rating, edited_by)
+		SELECT id, version, body, rating, $2
+		FROM recruiter_notes
+		WHERE id = $1`
+
+	_, err := tx.Exec(ctx, query, noteID, editorPublicID)
+	return err
+}
+
+// replaceNoteMentions stores the recruiters mentioned in the note and queues a notification
+// for every recruiter that was not mentioned before, except the author.
+func replaceNoteMentions(ctx context.Context, tx pgx.Tx, noteID int, mentions []string) error {
+	query := `
+		DELETE FROM recruiter_note_mentions
+		WHERE note_id = $1 AND NOT (recruiter_public_id::text = ANY($2))`
+	if _, err := tx.Exec(ctx, query, noteID, mentions); err != nil {
+		return err
+	}
+
+	added := make([]string, 0, len(mentions))
+	for _, recruiterPublicID := range mentions {
+		query := `
+			INSERT INTO recruiter_note_mentions (note_id, recruiter_public_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING`
+		tag, err := tx.Exec(ctx, query, noteID, recruiterPublicID)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() > 0 {
+			added = append(added, recruiterPublicID)
+		}
+	}
+
+	var note models.Note
+	query = `SELECT public_id, author_public_id, subject_type, subject_public_id FROM recruiter_notes WHERE id = $1`
+	err := tx.QueryRow(ctx, query, noteID).Scan(&note.PublicID, &note.AuthorPublicID, &note.SubjectType, &note.SubjectPublicID)
+	if err != nil {
+		return err
+	}
+	for _, recruiterPublicID := range added {
+		if recruiterPublicID == note.AuthorPublicID {
+			continue
+		}
+		err := enqueueNotification(ctx, tx, recruiterPublicID, models.NotificationNoteMention, map[string]interface{}{
+			"note_public_id":    note.PublicID,
+			"author_public_id":  note.AuthorPublicID,
+			"subject_type":      note.SubjectType,
+			"subject_public_id": note.SubjectPublicID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,315 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
+)
+
+// notificationRepository represents the repository for the notification outbox, delivery log and preferences.
+type notificationRepository struct {
+	db     *pgxpool.Pool
+	cfg    *config.DBConf
+	logger *zap.SugaredLogger
+}
+
+// NewNotificationRepository creates a new instance of notificationRepository.
+func NewNotificationRepository(db *pgxpool.Pool, cfg *config.DBConf, logger *zap.SugaredLogger) NotificationRepository {
+	return &notificationRepository{
+		db:     db,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// enqueueNotification writes a notification to the outbox as part of the caller's transaction,
+// so the notification is only sent if the triggering change is committed.
+func enqueueNotification(ctx context.Context, tx pgx.Tx, userPublicID, eventType string, payload map[string]interface{}) error {
+	query := `
+		INSERT INTO notification_outbox (user_public_id, event_type, payload)
+		VALUES ($1, $2, $3)`
+
+	_, err := tx.Exec(ctx, query, userPublicID, eventType, payload)
+	return err
+}
+
+// enqueueEmailNotification writes a notification sent to the email address rather than to a user,
+// as part of the caller's transaction
+func enqueueEmailNotification(ctx context.Context, tx dbtx, email, eventType string, payload map[string]interface{}) error {
+	query := `
+		INSERT INTO notification_outbox (recipient_email, event_type, payload)
+		VALUES ($1, $2, $3)`
+
+	_, err := tx.Exec(ctx, query, email, eventType, payload)
+	return err
+}
+
+// ClaimPendingNotifications leases up to limit notifications that are due for delivery.
+// Leased notifications are hidden from other workers until the lease expires.
+func (r *notificationRepository) ClaimPendingNotifications(ctx context.Context, limit int, lease time.Duration) ([]*models.Notification, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		UPDATE notification_outbox
+		SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT id
+			FROM notification_outbox
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING public_id, COALESCE(user_public_id::text, ''), COALESCE(recipient_email, ''), event_type, payload, status, attempts, created_at, sent_at`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while claiming pending notifications: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]*models.Notification, 0)
+	for rows.Next() {
+		notification := &models.Notification{}
+		err := rows.Scan(
+			&notification.PublicID,
+			&notification.UserPublicID,
+			&notification.RecipientEmail,
+			&notification.EventType,
+			&notification.Payload,
+			&notification.Status,
+			&notification.Attempts,
+			&notification.CreatedAt,
+			&notification.SentAt,
+		)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning notification: %v", err)
+			return nil, err
+		}
+		res = append(res, notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over notification rows: %v", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetRecipient retrieves the email address and preference of the user for the event type.
+// Email notifications are enabled unless the user turned them off.
+func (r *notificationRepository) GetRecipient(ctx context.Context, userPublicID, eventType string) (*models.NotificationRecipient, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		SELECT u.email, u.first_name, u.last_name, COALESCE(np.email_enabled, TRUE)
+		FROM users u
+		LEFT JOIN notification_preferences np ON np.user_public_id = u.public_id AND np.event_type = $2
+		WHERE u.public_id = $1`
+
+	recipient := &models.NotificationRecipient{}
+	err := conn(ctx, r.db).QueryRow(ctx, query, userPublicID, eventType).Scan(
+		&recipient.Email,
+		&recipient.FirstName,
+		&recipient.LastName,
+		&recipient.EmailEnabled,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrUserNotFound
+		}
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving notification recipient: %v", err)
+		return nil, err
+	}
+
+	return recipient, nil
+}
+
+// RecordDelivery stores the outcome of a delivery attempt in the delivery log and updates the notification.
+// For failed attempts nextAttemptAt schedules a retry; nil marks the notification as failed for good.
+func (r *notificationRepository) RecordDelivery(ctx context.Context, publicID, status, recipient, deliveryErr string, nextAttemptAt *time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error beginning transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	outboxStatus := status
+	if status == models.NotificationStatusFailed && nextAttemptAt != nil {
+		outboxStatus = models.NotificationStatusPending
+	}
+
+	query := `
+		UPDATE notification_outbox
+		SET
+			status = $2,
+			attempts = attempts + 1,
+			last_error = NULLIF($3, ''),
+			next_attempt_at = COALESCE($4, next_attempt_at),
+			sent_at = CASE WHEN $2 = 'sent' THEN NOW() ELSE sent_at END
+		WHERE public_id = $1
+		RETURNING id, attempts`
+
+	var (
+		id      int
+		attempt int
+	)
+	err = tx.QueryRow(ctx, query, publicID, outboxStatus, deliveryErr, nextAttemptAt).Scan(&id, &attempt)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while updating notification: %v", err)
+		return err
+	}
+
+	query = `
+		INSERT INTO notification_deliveries (notification_id, attempt, status, recipient, error)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))`
+
+	if _, err = tx.Exec(ctx, query, id, attempt, status, recipient, deliveryErr); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while logging notification delivery: %v", err)
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error committing transaction: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetNotifications retrieves the notifications of the user, newest first
+func (r *notificationRepository) GetNotifications(ctx context.Context, userPublicID string, args *models.SearchArgs) ([]*models.Notification, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		SELECT public_id, user_public_id, event_type, payload, status, attempts, created_at, sent_at
+		FROM notification_outbox
+		WHERE user_public_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`
+
+	offset := (args.PageNum - 1) * args.PageSize
+	rows, err := conn(ctx, r.db).Query(ctx, query, userPublicID, args.PageSize, offset)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving notifications: %v", err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	res := make([]*models.Notification, 0)
+	for rows.Next() {
+		notification := &models.Notification{}
+		err := rows.Scan(
+			&notification.PublicID,
+			&notification.UserPublicID,
+			&notification.EventType,
+			&notification.Payload,
+			&notification.Status,
+			&notification.Attempts,
+			&notification.CreatedAt,
+			&notification.SentAt,
+		)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning notification: %v", err)
+			return nil, 0, err
+		}
+		res = append(res, notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over notification rows: %v", err)
+		return nil, 0, err
+	}
+
+	var totalCount int
+	err = conn(ctx, r.db).QueryRow(ctx, `SELECT COUNT(*) FROM notification_outbox WHERE user_public_id = $1`, userPublicID).Scan(&totalCount)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving notifications count: %v", err)
+		return nil, 0, err
+	}
+
+	return res, totalCount, nil
+}
+
+// GetPreferences retrieves the preferences the user has stored
+func (r *notificationRepository) GetPreferences(ctx context.Context, userPublicID string) ([]*models.NotificationPreference, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		SELECT event_type, email_enabled
+		FROM notification_preferences
+		WHERE user_public_id = $1`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userPublicID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving notification preferences: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]*models.NotificationPreference, 0)
+	for rows.Next() {
+		preference := &models.NotificationPreference{}
+		if err := rows.Scan(&preference.EventType, &preference.EmailEnabled); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning notification preference: %v", err)
+			return nil, err
+		}
+		res = append(res, preference)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over notification preference rows: %v", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// SetPreferences stores the given preferences of the user, leaving the others untouched
+func (r *notificationRepository) SetPreferences(ctx context.Context, userPublicID string, preferences []*models.NotificationPreference) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error beginning transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO notification_preferences (user_public_id, event_type, email_enabled)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_public_id, event_type) DO UPDATE SET email_enabled = EXCLUDED.email_enabled`
+
+	for _, preference := range preferences {
+		if _, err = tx.Exec(ctx, query, userPublicID, preference.EventType, preference.EmailEnabled); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while storing notification preference: %v", err)
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error committing transaction: %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
+)
+
+// provisioningRepository creates the profiles of the users signing up with the auth service.
+type provisioningRepository struct {
+	db     *pgxpool.Pool
+	cfg    *config.DBConf
+	logger *zap.SugaredLogger
+}
+
+// NewProvisioningRepository creates a new instance of provisioningRepository.
+func NewProvisioningRepository(db *pgxpool.Pool, cfg *config.DBConf, logger *zap.SugaredLogger) ProvisioningRepository {
+	return &provisioningRepository{
+		db:     db,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// CreateCandidate creates the user and its candidate profile. If the user exists already, the
+// candidate is returned with Created false and the events are not written.
+func (r *provisioningRepository) CreateCandidate(ctx context.Context, candidate *models.CandidateProvision, events ...*models.DomainEvent) (*models.ProvisionedUser, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	var res *models.ProvisionedUser
+	err := inTx(ctx, r.db, r.cfg, r.logger, false, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+		userID, created, err := r.insertUser(ctx, tx, candidate.PublicID, candidate.FirstName, candidate.LastName, candidate.Email, false, candidate.Photo)
+		if err != nil {
+			return err
+		}
+		res = &models.ProvisionedUser{PublicID: candidate.PublicID, UserID: userID, Created: created}
+		if !created {
+			var exists bool
+			query := `SELECT EXISTS(SELECT 1 FROM candidates WHERE public_id = $1)`
+			if err := tx.QueryRow(ctx, query, candidate.PublicID).Scan(&exists); err != nil {
+				logging.FromContext(ctx, r.logger).Errorf("Error occurred while checking user existence: %v", err)
+				return err
+			}
+			if !exists {
+				return models.ErrUserExists
+			}
+			return nil
+		}
+
+		query := `
+			INSERT INTO candidates (public_id, current_position, education, resume, bio)
+			VALUES ($1, $2, $3, $4, $5)`
+		_, err = tx.Exec(ctx, query, candidate.PublicID, candidate.CurrentPosition, candidate.Education, candidate.Resume, candidate.Bio)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while creating candidate: %v", err)
+			return err
+		}
+
+		if err := insertEvents(ctx, tx, events); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while writing candidate events: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CreateRecruiter creates the user and its recruiter profile with the role in the company, which
+// must exist, or in no company if CompanyPublicID is empty. If the user exists already, the recruiter
+// is returned with Created false and the events are not written.
+func (r *provisioningRepository) CreateRecruiter(ctx context.Context, recruiter *models.RecruiterProvision, role string, events ...*models.DomainEvent) (*models.ProvisionedUser, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	var res *models.ProvisionedUser
+	err := inTx(ctx, r.db, r.cfg, r.logger, false, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+		userID, created, err := r.insertUser(ctx, tx, recruiter.PublicID, recruiter.FirstName, recruiter.LastName, recruiter.Email, recruiter.EmailVerified, recruiter.Photo)
+		if err != nil {
+			return err
+		}
+		res = &models.ProvisionedUser{PublicID: recruiter.PublicID, UserID: userID, Created: created}
+		if !created {
+			query := `SELECT COALESCE(company_public_id::text, ''), role FROM recruiters WHERE public_id = $1`
+			err := tx.QueryRow(ctx, query, recruiter.PublicID).Scan(&res.CompanyPublicID, &res.Role)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return models.ErrUserExists
+			}
+			if err != nil {
+				logging.FromContext(ctx, r.logger).Errorf("Error occurred while getting recruiter: %v", err)
+			}
+			return err
+		}
+
+		if recruiter.CompanyPublicID != "" {
+			var exists bool
+			query := `SELECT EXISTS(SELECT 1 FROM companies WHERE public_id = $1)`
+			if err := tx.QueryRow(ctx, query, recruiter.CompanyPublicID).Scan(&exists); err != nil {
+				logging.FromContext(ctx, r.logger).Errorf("Error occurred while checking company existence: %v", err)
+				return err
+			}
+			if !exists {
+				return models.ErrCompanyNotFound
+			}
+		}
+
+		query := `INSERT INTO recruiters (public_id, company_public_id, role) VALUES ($1, NULLIF($2, '')::uuid, $3)`
+		if _, err := tx.Exec(ctx, query, recruiter.PublicID, recruiter.CompanyPublicID, role); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while creating recruiter: %v", err)
+			return err
+		}
+		res.CompanyPublicID = recruiter.CompanyPublicID
+		res.Role = role
+
+		if err := insertEvents(ctx, tx, events); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while writing recruiter events: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// FindOrCreateCompany returns the company with the name regardless of case, the provisioned one
+// or else the oldest one, and creates it if there is none. The events are stamped with the public
+// ID of the company and only written when it is created.
+func (r *provisioningRepository) FindOrCreateCompany(ctx context.Context, company *models.Company, events ...*models.DomainEvent) (*models.Company, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	var (
+		res     *models.Company
+		created bool
+	)
+	err := inTx(ctx, r.db, r.cfg, r.logger, false, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+		res = &models.Company{}
+		created = false
+
+		query := `
+			SELECT id, public_id, name, logo, description, ` + companyVerified + `
+			FROM companies
+			WHERE lower(name) = lower($1)
+			ORDER BY provisioned DESC, id
+			LIMIT 1`
+		err := tx.QueryRow(ctx, query, company.Name).Scan(&res.ID, &res.PublicID, &res.Name, &res.Logo, &res.Description, &res.Verified)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while finding company: %v", err)
+			return err
+		}
+
+		// a company created concurrently under the same name is updated to nothing, so it is
+		// returned instead; xmax is only zero for the row inserted
+		query = `
+			INSERT INTO companies (name, logo, description, provisioned)
+			VALUES ($1, $2, $3, TRUE)
+			ON CONFLICT ((lower(name))) WHERE provisioned DO UPDATE SET name = companies.name
+			RETURNING id, public_id, name, logo, description, ` + companyVerified + `, xmax = 0`
+		err = tx.QueryRow(ctx, query, company.Name, company.Logo, company.Description).
+			Scan(&res.ID, &res.PublicID, &res.Name, &res.Logo, &res.Description, &res.Verified, &created)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while creating company: %v", err)
+			return err
+		}
+		if !created {
+			return nil
+		}
+
+		for _, event := range events {
+			if event != nil {
+				event.AggregatePublicID = res.PublicID
+			}
+		}
+		if err := insertEvents(ctx, tx, events); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while writing company events: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return res, created, nil
+}
+
+// insertUser creates the user with the public ID, and returns its ID with false if it exists already
+func (r *provisioningRepository) insertUser(ctx context.Context, tx dbtx, publicID, firstName, lastName, email string, emailVerified bool, photo string) (int, bool, error) {
+	var id int
+	query := `
+		INSERT INTO users (public_id, first_name, last_name, email, email_verified, photo)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (public_id) DO NOTHING
+		RETURNING id`
+	err := tx.QueryRow(ctx, query, publicID, firstName, lastName, email, emailVerified, photo).Scan(&id)
+	if err == nil {
+		return id, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while creating user: %v", err)
+		return 0, false, err
+	}
+
+	query = `SELECT id FROM users WHERE public_id = $1`
+	if err := tx.QueryRow(ctx, query, publicID).Scan(&id); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while getting user: %v", err)
+		return 0, false, err
+	}
+	return id, false, nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
 	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -25,55 +26,67 @@ func NewRecruiterRepository(db *pgxpool.Pool, cfg *config.DBConf, logger *zap.Su
 	}
 }
 
-func (r *recruiterRepository) GetRecruiter(publicID string) (*models.Recruiter, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.TimeOut)
+func (r *recruiterRepository) GetRecruiter(ctx context.Context, publicID string) (*models.Recruiter, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
 	defer cancel()
 
 	// Retrieve the recruiter's information
-	recruiterQuery := `SELECT r.public_id, r.company_public_id, u.first_name, u.last_name, u.photo
+	recruiterQuery := `SELECT r.public_id, COALESCE(r.company_public_id::text, ''), r.role, u.first_name, u.last_name, u.photo
 	FROM recruiters r
 	JOIN users u ON r.public_id = u.public_id
 	WHERE r.public_id = $1`
 
 	recruiter := &models.Recruiter{}
-	err := r.db.QueryRow(ctx, recruiterQuery, publicID).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, recruiterQuery, publicID).Scan(
 		&recruiter.PublicID,
 		&recruiter.CompanyPublicID,
+		&recruiter.Role,
 		&recruiter.FirstName,
 		&recruiter.LastName,
 		&recruiter.Photo,
 	)
 
 	if err != nil {
-		r.logger.Errorf("Error occurred while retrieving recruiter information: %v", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrUserNotFound
+		}
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving recruiter information: %v", err)
 		return nil, err
 	}
 
-	// Retrieve the company information
-	companyQuery := `SELECT c.public_id, c.name, c.description
-	FROM companies c
-	WHERE c.public_id = $1`
+	// Retrieve the company information, unless the recruiter belongs to no company
+	var company *models.Company
+	if recruiter.CompanyPublicID != "" {
+		companyQuery := `SELECT public_id, name, description, ` + companyVerified + `
+		FROM companies
+		WHERE public_id = $1`
 
-	company := &models.Company{}
-	err = r.db.QueryRow(ctx, companyQuery, recruiter.CompanyPublicID).Scan(
-		&company.PublicID,
-		&company.Name,
-		&company.Description,
-	)
+		company = &models.Company{}
+		err = conn(ctx, r.db).QueryRow(ctx, companyQuery, recruiter.CompanyPublicID).Scan(
+			&company.PublicID,
+			&company.Name,
+			&company.Description,
+			&company.Verified,
+		)
 
-	if err != nil {
-		r.logger.Errorf("Error occurred while retrieving company information: %v", err)
-		return nil, err
+		if errors.Is(err, pgx.ErrNoRows) {
+			// the company was removed, the recruiter is returned without it
+			company = nil
+		} else if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving company information: %v", err)
+			return nil, err
+		}
 	}
 
 	// Retrieve all positions for the recruiter
 	positionsQuery := `SELECT p.public_id, p.name, p.status
 	FROM positions p
-	WHERE p.recruiter_public_id = $1`
+	WHERE p.recruiter_public_id = $1
+	ORDER BY p.id`
 
-	rows, err := r.db.Query(ctx, positionsQuery, recruiter.PublicID)
+	rows, err := conn(ctx, r.db).Query(ctx, positionsQuery, recruiter.PublicID)
 	if err != nil {
-		r.logger.Errorf("Error occurred while retrieving positions for the recruiter: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving positions for the recruiter: %v", err)
 		return nil, err
 	}
 	defer rows.Close()
@@ -87,7 +100,7 @@ func (r *recruiterRepository) GetRecruiter(publicID string) (*models.Recruiter,
 			&position.Status,
 		)
 		if err != nil {
-			r.logger.Errorf("Error occurred while scanning position rows: %v", err)
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning position rows: %v", err)
 			return nil, err
 		}
 
@@ -95,7 +108,7 @@ func (r *recruiterRepository) GetRecruiter(publicID string) (*models.Recruiter,
 	}
 
 	if err := rows.Err(); err != nil {
-		r.logger.Errorf("Error occurred while iterating over position rows: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over position rows: %v", err)
 		return nil, err
 	}
 
@@ -105,24 +118,90 @@ func (r *recruiterRepository) GetRecruiter(publicID string) (*models.Recruiter,
 	return recruiter, nil
 }
 
-func (r *recruiterRepository) Exists(publicID string) (bool, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.TimeOut)
+// GetCompanyPublicID retrieves the public ID of the company the recruiter belongs to.
+// It fails with models.ErrNoCompany if the recruiter belongs to none.
+func (r *recruiterRepository) GetCompanyPublicID(ctx context.Context, publicID string) (string, error) {
+	membership, err := r.GetMembership(ctx, publicID)
+	if err != nil {
+		return "", err
+	}
+	if membership.CompanyPublicID == "" {
+		return "", models.ErrNoCompany
+	}
+	return membership.CompanyPublicID, nil
+}
+
+// GetMembership retrieves the company of the recruiter and its role in it. The company is empty
+// if the recruiter belongs to none.
+func (r *recruiterRepository) GetMembership(ctx context.Context, publicID string) (*models.Membership, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `SELECT COALESCE(company_public_id::text, ''), role FROM recruiters WHERE public_id = $1`
+
+	membership := &models.Membership{}
+	err := conn(ctx, r.db).QueryRow(ctx, query, publicID).Scan(&membership.CompanyPublicID, &membership.Role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrUserNotFound
+		}
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving recruiter company: %v", err)
+		return nil, err
+	}
+
+	return membership, nil
+}
+
+// FilterByCompany returns the subset of the given recruiter public IDs that belong to the company
+func (r *recruiterRepository) FilterByCompany(ctx context.Context, companyPublicID string, publicIDs []string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `SELECT public_id::text FROM recruiters WHERE company_public_id = $1 AND public_id::text = ANY($2) ORDER BY id`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, companyPublicID, publicIDs)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while filtering company recruiters: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]string, 0, len(publicIDs))
+	for rows.Next() {
+		var publicID string
+		if err := rows.Scan(&publicID); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning recruiter public id: %v", err)
+			return nil, err
+		}
+		res = append(res, publicID)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over recruiter rows: %v", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *recruiterRepository) Exists(ctx context.Context, publicID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
 	defer cancel()
 
 	query := `SELECT EXISTS (SELECT 1 FROM recruiters WHERE public_id = $1)`
 
 	var exists bool
-	err := r.db.QueryRow(ctx, query, publicID).Scan(&exists)
+	err := conn(ctx, r.db).QueryRow(ctx, query, publicID).Scan(&exists)
 	if err != nil {
-		r.logger.Errorf("Error occurred while checking recruiter existence: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while checking recruiter existence: %v", err)
 		return false, err
 	}
 
 	return exists, nil
 }
 
-func (r *recruiterRepository) GetInterviewsByPublicID(publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.TimeOut)
+func (r *recruiterRepository) GetInterviewsByPublicID(ctx context.Context, publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
 	defer cancel()
 
 	query := `
@@ -132,16 +211,17 @@ func (r *recruiterRepository) GetInterviewsByPublicID(publicID string, searchArg
 	INNER JOIN positions p ON p.id = ui.position_id
 	INNER JOIN recruiters r ON p.recruiter_public_id = r.public_id
 	WHERE r.public_id = $1
-	GROUP BY i.public_id, i.results, p.public_id
+	GROUP BY i.id, i.public_id, i.results, p.public_id
+	ORDER BY i.id
 	LIMIT $2 OFFSET $3;
 `
 	offset := (searchArgs.PageNum - 1) * searchArgs.PageSize
-	rows, err := r.db.Query(ctx, query, publicID, searchArgs.PageSize, offset)
+	rows, err := conn(ctx, r.db).Query(ctx, query, publicID, searchArgs.PageSize, offset)
 	if err != nil {
 		if errors.Is(pgx.ErrNoRows, err) {
 			return nil, 0, nil
 		}
-		r.logger.Errorf("Error occurred while retrieving interview result: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving interview result: %v", err)
 		return nil, 0, err
 	}
 	defer rows.Close()
@@ -155,7 +235,7 @@ func (r *recruiterRepository) GetInterviewsByPublicID(publicID string, searchArg
 			&result.PositionPublicID,
 		)
 		if err != nil {
-			r.logger.Errorf("Error occurred while retrieving interview result: %v", err)
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving interview result: %v", err)
 			return nil, 0, err
 		}
 		result.RawResult = resultBytes
@@ -163,7 +243,7 @@ func (r *recruiterRepository) GetInterviewsByPublicID(publicID string, searchArg
 	}
 
 	if err := rows.Err(); err != nil {
-		r.logger.Errorf("Error occurred while iterating over interview result for position rows: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over interview result for position rows: %v", err)
 		return nil, 0, err
 	}
 	query = `
@@ -175,13 +255,13 @@ func (r *recruiterRepository) GetInterviewsByPublicID(publicID string, searchArg
    	WHERE p.recruiter_public_id = $1
 `
 	var totalCount int
-	err = r.db.QueryRow(ctx, query, publicID).Scan(&totalCount)
+	err = conn(ctx, r.db).QueryRow(ctx, query, publicID).Scan(&totalCount)
 	if err != nil {
 		if errors.Is(pgx.ErrNoRows, err) {
-			r.logger.Errorf("Error occurred while retrieving position count: %v", err)
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving position count: %v", err)
 			return nil, 0, nil
 		}
-		r.logger.Errorf("Error occurred while retrieving position count: %v", err)
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving position count: %v", err)
 		return nil, 0, err
 	}
 	return res, totalCount, nil
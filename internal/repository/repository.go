@@ -1,8 +1,13 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/cache"
 	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/go-redis/redis/v7"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"go.uber.org/zap"
 )
@@ -11,34 +16,184 @@ type Repository struct {
 	RecruiterRepository
 	CandidateRepository
 	CompanyRepository
+	ShortlistRepository
+	NoteRepository
+	SavedSearchRepository
+	NotificationRepository
+	EventRepository
+	WebhookRepository
+	ProvisioningRepository
+	MembershipRepository
+	DomainRepository
+	UnitOfWork
 }
 type CompanyRepository interface {
-	CreateCompany(company *models.Company) (string, error)
-	UpdateCompany(company *models.Company) error
-	GetCompany(publicID string) (*models.Company, error)
-	GetCompanies(args *models.SearchArgs) ([]*models.Company, int, error)
-	Exists(publicID string) (bool, error)
+	CreateCompany(ctx context.Context, company *models.Company, events ...*models.DomainEvent) (string, error)
+	UpdateCompany(ctx context.Context, company *models.Company) error
+	GetCompany(ctx context.Context, publicID string) (*models.Company, error)
+	GetCompanies(ctx context.Context, args *models.SearchArgs) ([]*models.Company, int, error)
+	Exists(ctx context.Context, publicID string) (bool, error)
 }
 type RecruiterRepository interface {
-	Exists(publicID string) (bool, error)
-	GetRecruiter(publicID string) (*models.Recruiter, error)
-	GetInterviewsByPublicID(publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error)
+	Exists(ctx context.Context, publicID string) (bool, error)
+	GetRecruiter(ctx context.Context, publicID string) (*models.Recruiter, error)
+	GetCompanyPublicID(ctx context.Context, publicID string) (string, error)
+	GetMembership(ctx context.Context, publicID string) (*models.Membership, error)
+	FilterByCompany(ctx context.Context, companyPublicID string, publicIDs []string) ([]string, error)
+	GetInterviewsByPublicID(ctx context.Context, publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error)
 }
 type CandidateRepository interface {
-	GetCandidatesBySearch(searchArgs *models.SearchArgs) ([]*models.Candidate, int, error)
-	GetCandidateByPublicID(publicID string) (*models.Candidate, error)
-	Exists(publicID string) (bool, error)
-	AddSkillsToCandidate(candidateID string, skills []string) error
-	UpdateCandidateByID(candidateID string, updateData *models.Candidate) error
-	DeleteCandidateByID(candidateID string) error
-	DeleteSkillsFromCandidate(candidateID string, skills []string) error
-	GetInterviewsByPublicID(publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error)
-}
-
-func New(db *pgxpool.Pool, cfg *config.Configs, log *zap.SugaredLogger) *Repository {
-	return &Repository{
-		RecruiterRepository: NewRecruiterRepository(db, cfg.DB, log),
-		CandidateRepository: NewCandidateRepository(db, cfg.DB, log),
-		CompanyRepository:   NewCompanyRepository(db, cfg.DB, log),
+	GetCandidatesBySearch(ctx context.Context, searchArgs *models.SearchArgs) ([]*models.Candidate, int, error)
+	GetCandidateByPublicID(ctx context.Context, publicID string) (*models.Candidate, error)
+	Exists(ctx context.Context, publicID string) (bool, error)
+	// AddSkillsToCandidate, DeleteSkillsFromCandidate and ReplaceCandidateSkills match skill names
+	// regardless of case and surrounding spaces. They stamp the skills added and removed in the
+	// payload of the events, which are only written if the skills changed.
+	AddSkillsToCandidate(ctx context.Context, candidateID string, skills []string, events ...*models.DomainEvent) (*models.SkillsChange, error)
+	UpdateCandidateByID(ctx context.Context, candidateID string, updateData *models.Candidate, events ...*models.DomainEvent) error
+	DeleteCandidateByID(ctx context.Context, candidateID string, events ...*models.DomainEvent) error
+	DeleteSkillsFromCandidate(ctx context.Context, candidateID string, skills []string, events ...*models.DomainEvent) (*models.SkillsChange, error)
+	// ReplaceCandidateSkills sets the skills of the candidate to exactly the given ones
+	ReplaceCandidateSkills(ctx context.Context, candidateID string, skills []string, events ...*models.DomainEvent) (*models.SkillsChange, error)
+	GetInterviewsByPublicID(ctx context.Context, publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error)
+}
+type ShortlistRepository interface {
+	CreateShortlist(ctx context.Context, shortlist *models.Shortlist) (string, error)
+	GetShortlist(ctx context.Context, publicID string) (*models.Shortlist, error)
+	GetShortlists(ctx context.Context, recruiterPublicID, companyPublicID string, args *models.SearchArgs) ([]*models.Shortlist, int, error)
+	UpdateShortlist(ctx context.Context, shortlist *models.Shortlist) error
+	DeleteShortlist(ctx context.Context, publicID string) error
+	AddCandidate(ctx context.Context, shortlistPublicID, candidatePublicID, recruiterPublicID, note string) error
+	RemoveCandidate(ctx context.Context, shortlistPublicID, candidatePublicID string) error
+	GetCandidates(ctx context.Context, shortlistPublicID string, args *models.SearchArgs) ([]*models.ShortlistCandidate, int, error)
+}
+type NoteRepository interface {
+	SubjectExists(ctx context.Context, subjectType, subjectPublicID string) (bool, error)
+	CreateNote(ctx context.Context, note *models.Note) (string, error)
+	GetNote(ctx context.Context, publicID string) (*models.Note, error)
+	GetNotes(ctx context.Context, companyPublicID, subjectType, subjectPublicID string, args *models.SearchArgs) ([]*models.Note, int, error)
+	GetMentionedNotes(ctx context.Context, recruiterPublicID, companyPublicID string, args *models.SearchArgs) ([]*models.Note, int, error)
+	UpdateNote(ctx context.Context, note *models.Note, editorPublicID string) error
+	DeleteNote(ctx context.Context, publicID string) error
+	GetNoteRevisions(ctx context.Context, publicID string) ([]*models.NoteRevision, error)
+}
+type SavedSearchRepository interface {
+	CreateSavedSearch(ctx context.Context, savedSearch *models.SavedSearch) (string, error)
+	GetSavedSearch(ctx context.Context, publicID string) (*models.SavedSearch, error)
+	GetSavedSearches(ctx context.Context, recruiterPublicID string, args *models.SearchArgs) ([]*models.SavedSearch, int, error)
+	GetSavedSearchIDs(ctx context.Context) ([]string, error)
+	DeleteSavedSearch(ctx context.Context, publicID string) error
+	RecordNewMatches(ctx context.Context, publicID string) (int, error)
+	GetAlerts(ctx context.Context, recruiterPublicID string, unseenOnly bool, args *models.SearchArgs) ([]*models.SavedSearchAlert, int, error)
+	MarkAlertsSeen(ctx context.Context, recruiterPublicID string, alertPublicIDs []string) error
+}
+type NotificationRepository interface {
+	ClaimPendingNotifications(ctx context.Context, limit int, lease time.Duration) ([]*models.Notification, error)
+	GetRecipient(ctx context.Context, userPublicID, eventType string) (*models.NotificationRecipient, error)
+	RecordDelivery(ctx context.Context, publicID, status, recipient, deliveryErr string, nextAttemptAt *time.Time) error
+	GetNotifications(ctx context.Context, userPublicID string, args *models.SearchArgs) ([]*models.Notification, int, error)
+	GetPreferences(ctx context.Context, userPublicID string) ([]*models.NotificationPreference, error)
+	SetPreferences(ctx context.Context, userPublicID string, preferences []*models.NotificationPreference) error
+}
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook *models.Webhook) (string, error)
+	GetWebhook(ctx context.Context, publicID string) (*models.Webhook, error)
+	GetWebhooks(ctx context.Context, companyPublicID string) ([]*models.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook *models.Webhook) error
+	DeleteWebhook(ctx context.Context, publicID string) error
+	GetDeliveries(ctx context.Context, webhookPublicID, status string, args *models.SearchArgs) ([]*models.WebhookDelivery, int, error)
+	GetDelivery(ctx context.Context, publicID string) (*models.WebhookDelivery, error)
+	ReplayDelivery(ctx context.Context, publicID string) error
+	ClaimPendingDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error)
+	RecordDeliveryAttempt(ctx context.Context, publicID string, delivered bool, statusCode int, attemptErr string, nextAttemptAt *time.Time) error
+}
+
+// ProvisioningRepository creates the profiles of the users signing up with the auth service, which
+// keeps their credentials. Creating a profile that exists already returns it, so sign ups can be retried.
+type ProvisioningRepository interface {
+	CreateCandidate(ctx context.Context, candidate *models.CandidateProvision, events ...*models.DomainEvent) (*models.ProvisionedUser, error)
+	CreateRecruiter(ctx context.Context, recruiter *models.RecruiterProvision, role string, events ...*models.DomainEvent) (*models.ProvisionedUser, error)
+	FindOrCreateCompany(ctx context.Context, company *models.Company, events ...*models.DomainEvent) (*models.Company, bool, error)
+}
+
+// MembershipRepository manages the members of companies, the invitations to join them and the
+// requests of recruiters to join them.
+type MembershipRepository interface {
+	GetMembers(ctx context.Context, companyPublicID string) ([]*models.CompanyMember, error)
+	// HasOwner serializes the transactions checking the same company, so a single one of them
+	// finds it without an owner.
+	HasOwner(ctx context.Context, companyPublicID string) (bool, error)
+	// SetMembership fails with models.ErrLastOwner if the recruiter is the last owner of the
+	// company it leaves or is demoted in.
+	SetMembership(ctx context.Context, recruiterPublicID, companyPublicID, role string, events ...*models.DomainEvent) error
+	CreateInvitation(ctx context.Context, invitation *models.Invitation, tokenHash string, payload map[string]interface{}) (*models.Invitation, error)
+	GetInvitations(ctx context.Context, companyPublicID string, args *models.SearchArgs) ([]*models.Invitation, int, error)
+	RevokeInvitation(ctx context.Context, companyPublicID, invitationPublicID string) error
+	// AcceptInvitation fails with models.ErrInvitationInvalid if the invitation expired, was
+	// revoked or was accepted by another recruiter.
+	AcceptInvitation(ctx context.Context, tokenHash, recruiterPublicID string) (*models.Invitation, error)
+	CreateJoinRequest(ctx context.Context, joinRequest *models.JoinRequest) (*models.JoinRequest, error)
+	GetJoinRequests(ctx context.Context, companyPublicID, status string, args *models.SearchArgs) ([]*models.JoinRequest, int, error)
+	// DecideJoinRequest fails with models.ErrJoinRequestDecided if the request is no longer pending.
+	DecideJoinRequest(ctx context.Context, companyPublicID, joinRequestPublicID, status, deciderPublicID string) (*models.JoinRequest, error)
+}
+
+// DomainRepository manages the email domains companies claim and the proof they own them.
+type DomainRepository interface {
+	// CreateDomain fails with models.ErrDomainTaken if another company verified the domain.
+	CreateDomain(ctx context.Context, domain *models.CompanyDomain, codeHash string, codeExpiresAt *time.Time, payload map[string]interface{}) (*models.CompanyDomain, error)
+	GetDomains(ctx context.Context, companyPublicID string) ([]*models.CompanyDomain, error)
+	GetDomain(ctx context.Context, companyPublicID, domainPublicID string) (*models.CompanyDomain, error)
+	// VerifyDomain fails with models.ErrDomainNotVerified if the code is wrong or expired, and with
+	// models.ErrDomainTaken if another company verified the domain first.
+	VerifyDomain(ctx context.Context, companyPublicID, domainPublicID, codeHash string, events ...*models.DomainEvent) (*models.CompanyDomain, error)
+	DeleteDomain(ctx context.Context, companyPublicID, domainPublicID string) error
+	GetVerifiedCompany(ctx context.Context, domain string) (string, error)
+	HasVerifiedEmail(ctx context.Context, companyPublicID, userPublicID string) (bool, error)
+}
+
+// EventRepository is used by the relay that publishes the domain event outbox.
+// Events are written to the outbox by the repository methods that make the change.
+type EventRepository interface {
+	ClaimUnpublishedEvents(ctx context.Context, limit int, lease time.Duration) ([]*models.DomainEvent, error)
+	MarkEventPublished(ctx context.Context, publicID string) error
+	MarkEventFailed(ctx context.Context, publicID, publishErr string, nextAttemptAt time.Time) error
+}
+
+func New(db *pgxpool.Pool, rdb *redis.Client, cfg *config.Configs, log *zap.SugaredLogger) *Repository {
+	repos := &Repository{
+		RecruiterRepository:    NewRecruiterRepository(db, cfg.DB, log),
+		CandidateRepository:    NewCandidateRepository(db, cfg.DB, log),
+		CompanyRepository:      NewCompanyRepository(db, cfg.DB, log),
+		ShortlistRepository:    NewShortlistRepository(db, cfg.DB, log),
+		NoteRepository:         NewNoteRepository(db, cfg.DB, log),
+		SavedSearchRepository:  NewSavedSearchRepository(db, cfg.DB, log),
+		NotificationRepository: NewNotificationRepository(db, cfg.DB, log),
+		EventRepository:        NewEventRepository(db, cfg.DB, log),
+		WebhookRepository:      NewWebhookRepository(db, cfg.DB, log),
+		ProvisioningRepository: NewProvisioningRepository(db, cfg.DB, log),
+		MembershipRepository:   NewMembershipRepository(db, cfg.DB, log),
+		DomainRepository:       NewDomainRepository(db, cfg.DB, log),
+		UnitOfWork:             NewUnitOfWork(db, cfg.DB, log),
+	}
+	repos.instrument()
+	if cfg.Cache != nil && cfg.Cache.Enabled {
+		c := newCache(rdb, cfg.Cache, log)
+		repos.CompanyRepository = NewCachedCompanyRepository(repos.CompanyRepository, c, cfg.Cache.CompanyTTL, log)
+		repos.CandidateRepository = NewCachedCandidateRepository(repos.CandidateRepository, c, cfg.Cache.CandidateTTL, log)
+		repos.RecruiterRepository = NewCachedRecruiterRepository(repos.RecruiterRepository, repos.CompanyRepository, c, cfg.Cache.RecruiterTTL, log)
+		repos.MembershipRepository = NewCachedMembershipRepository(repos.MembershipRepository, c, log)
+		repos.DomainRepository = NewCachedDomainRepository(repos.DomainRepository, c, log)
+	}
+	return repos
+}
+
+// newCache creates the read cache. It is kept in Redis when a client is given, with an
+// in-process fallback for when Redis is unavailable.
+func newCache(rdb *redis.Client, cfg *config.CacheConf, log *zap.SugaredLogger) cache.Cache {
+	memory := cache.NewMemoryCache(cfg.FallbackMaxEntries)
+	if rdb == nil {
+		return memory
 	}
+	return cache.NewFallbackCache(cache.NewRedisCache(rdb, cfg.KeyPrefix), memory, cfg.FallbackTTL, log)
 }
@@ -0,0 +1,394 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
+)
+
+// savedSearchRepository represents the repository for managing saved candidate searches and their alerts.
+type savedSearchRepository struct {
+	db     *pgxpool.Pool
+	cfg    *config.DBConf
+	logger *zap.SugaredLogger
+}
+
+// NewSavedSearchRepository creates a new instance of savedSearchRepository.
+func NewSavedSearchRepository(db *pgxpool.Pool, cfg *config.DBConf, logger *zap.SugaredLogger) SavedSearchRepository {
+	return &savedSearchRepository{
+		db:     db,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// recordMatchesQuery records the candidates currently matching the saved search, under the condition
+// candidateSearchCondition builds from its arguments. Candidates that were not matched before are
+// returned by the new_matches CTE and raise an alert when alert is true.
+func recordMatchesQuery(id int, args *models.SearchArgs, alert bool) (string, []interface{}) {
+	where, params := candidateSearchCondition(args, []interface{}{id, alert})
+	return `
+	WITH new_matches AS (
+		INSERT INTO saved_search_matches (saved_search_id, candidate_id)
+		SELECT $1, c.id
+		FROM candidates c
+		JOIN users u ON c.public_id = u.public_id
+		WHERE ` + where + `
+		ON CONFLICT DO NOTHING
+		RETURNING saved_search_id, candidate_id
+	), alerts AS (
+		INSERT INTO saved_search_alerts (saved_search_id, candidate_id)
+		SELECT saved_search_id, candidate_id FROM new_matches WHERE $2
+		RETURNING 1
+	)
+	SELECT COUNT(*) FROM alerts`, params
+}
+
+// CreateSavedSearch saves the search and records its current matches, so that only candidates
+// matching after this point raise alerts
+func (r *savedSearchRepository) CreateSavedSearch(ctx context.Context, savedSearch *models.SavedSearch) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error beginning transaction: %v", err)
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO saved_searches (recruiter_public_id, name, params, last_run_at)
+		VALUES ($1, $2, $3, NOW())
+		RETURNING id, public_id`
+
+	var (
+		id       int
+		publicID string
+	)
+	err = tx.QueryRow(ctx, query, savedSearch.RecruiterPublicID, savedSearch.Name, savedSearch.Params).Scan(&id, &publicID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while creating saved search: %v", err)
+		return "", err
+	}
+
+	query, params := recordMatchesQuery(id, &savedSearch.Params, false)
+	var alerts int
+	if err = tx.QueryRow(ctx, query, params...).Scan(&alerts); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while recording saved search matches: %v", err)
+		return "", err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error committing transaction: %v", err)
+		return "", err
+	}
+
+	return publicID, nil
+}
+
+// GetSavedSearch retrieves a saved search by its public ID
+func (r *savedSearchRepository) GetSavedSearch(ctx context.Context, publicID string) (*models.SavedSearch, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		SELECT public_id, recruiter_public_id, name, params, last_run_at, created_at
+		FROM saved_searches
+		WHERE public_id = $1`
+
+	savedSearch := &models.SavedSearch{}
+	err := conn(ctx, r.db).QueryRow(ctx, query, publicID).Scan(
+		&savedSearch.PublicID,
+		&savedSearch.RecruiterPublicID,
+		&savedSearch.Name,
+		&savedSearch.Params,
+		&savedSearch.LastRunAt,
+		&savedSearch.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrSavedSearchNotFound
+		}
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving saved search: %v", err)
+		return nil, err
+	}
+
+	return savedSearch, nil
+}
+
+// GetSavedSearches retrieves the recruiter's saved searches along with the total count
+func (r *savedSearchRepository) GetSavedSearches(ctx context.Context, recruiterPublicID string, args *models.SearchArgs) ([]*models.SavedSearch, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		SELECT public_id, recruiter_public_id, name, params, last_run_at, created_at
+		FROM saved_searches
+		WHERE recruiter_public_id = $1 AND name ILIKE $2
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4`
+
+	countQuery := `
+		SELECT COUNT(*)
+		FROM saved_searches
+		WHERE recruiter_public_id = $1 AND name ILIKE $2`
+
+	searchPattern := "%" + args.Search + "%"
+	offset := (args.PageNum - 1) * args.PageSize
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, recruiterPublicID, searchPattern, args.PageSize, offset)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving saved searches: %v", err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	res := make([]*models.SavedSearch, 0)
+	for rows.Next() {
+		savedSearch := &models.SavedSearch{}
+		err := rows.Scan(
+			&savedSearch.PublicID,
+			&savedSearch.RecruiterPublicID,
+			&savedSearch.Name,
+			&savedSearch.Params,
+			&savedSearch.LastRunAt,
+			&savedSearch.CreatedAt,
+		)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning saved search: %v", err)
+			return nil, 0, err
+		}
+		res = append(res, savedSearch)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over saved search rows: %v", err)
+		return nil, 0, err
+	}
+
+	var totalCount int
+	err = conn(ctx, r.db).QueryRow(ctx, countQuery, recruiterPublicID, searchPattern).Scan(&totalCount)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving saved searches count: %v", err)
+		return nil, 0, err
+	}
+
+	return res, totalCount, nil
+}
+
+// GetSavedSearchIDs retrieves the public IDs of every saved search
+func (r *savedSearchRepository) GetSavedSearchIDs(ctx context.Context) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	rows, err := conn(ctx, r.db).Query(ctx, `SELECT public_id FROM saved_searches ORDER BY id`)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving saved search ids: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]string, 0)
+	for rows.Next() {
+		var publicID string
+		if err := rows.Scan(&publicID); err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning saved search id: %v", err)
+			return nil, err
+		}
+		res = append(res, publicID)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over saved search id rows: %v", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DeleteSavedSearch deletes a saved search together with its matches and alerts
+func (r *savedSearchRepository) DeleteSavedSearch(ctx context.Context, publicID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	_, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM saved_searches WHERE public_id = $1`, publicID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while deleting saved search: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// RecordNewMatches records an alert for every candidate that started matching the saved search
+// since its last run and returns the number of alerts raised
+func (r *savedSearchRepository) RecordNewMatches(ctx context.Context, publicID string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error beginning transaction: %v", err)
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		id   int
+		args models.SearchArgs
+	)
+	err = tx.QueryRow(ctx, `UPDATE saved_searches SET last_run_at = NOW() WHERE public_id = $1 RETURNING id, params`, publicID).Scan(&id, &args)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, models.ErrSavedSearchNotFound
+		}
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while updating saved search: %v", err)
+		return 0, err
+	}
+
+	query, params := recordMatchesQuery(id, &args, true)
+	var alerts int
+	if err = tx.QueryRow(ctx, query, params...).Scan(&alerts); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while recording saved search matches: %v", err)
+		return 0, err
+	}
+
+	if alerts > 0 {
+		var recruiterPublicID, name string
+		err = tx.QueryRow(ctx, `SELECT recruiter_public_id, name FROM saved_searches WHERE id = $1`, id).Scan(&recruiterPublicID, &name)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving saved search: %v", err)
+			return 0, err
+		}
+		err = enqueueNotification(ctx, tx, recruiterPublicID, models.NotificationSavedSearchMatch, map[string]interface{}{
+			"saved_search_public_id": publicID,
+			"saved_search_name":      name,
+			"new_matches":            alerts,
+		})
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while queueing saved search notification: %v", err)
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error committing transaction: %v", err)
+		return 0, err
+	}
+
+	return alerts, nil
+}
+
+// GetAlerts retrieves the alerts raised for the recruiter's saved searches, newest first
+func (r *savedSearchRepository) GetAlerts(ctx context.Context, recruiterPublicID string, unseenOnly bool, args *models.SearchArgs) ([]*models.SavedSearchAlert, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		SELECT
+			a.public_id,
+			ss.public_id,
+			ss.name,
+			a.seen,
+			a.created_at,
+			c.public_id,
+			c.current_position,
+			c.education,
+			c.resume,
+			c.bio,
+			u.photo,
+			u.first_name,
+			u.last_name
+		FROM saved_search_alerts a
+		JOIN saved_searches ss ON ss.id = a.saved_search_id
+		JOIN candidates c ON c.id = a.candidate_id
+		JOIN users u ON c.public_id = u.public_id
+		WHERE ss.recruiter_public_id = $1 AND (NOT $2 OR NOT a.seen)
+		ORDER BY a.created_at DESC, a.id DESC
+		LIMIT $3 OFFSET $4`
+
+	countQuery := `
+		SELECT COUNT(*)
+		FROM saved_search_alerts a
+		JOIN saved_searches ss ON ss.id = a.saved_search_id
+		WHERE ss.recruiter_public_id = $1 AND (NOT $2 OR NOT a.seen)`
+
+	offset := (args.PageNum - 1) * args.PageSize
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, recruiterPublicID, unseenOnly, args.PageSize, offset)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving saved search alerts: %v", err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	res := make([]*models.SavedSearchAlert, 0)
+	for rows.Next() {
+		candidate := &models.Candidate{}
+		alert := &models.SavedSearchAlert{Candidate: candidate}
+		err := rows.Scan(
+			&alert.PublicID,
+			&alert.SavedSearchPublicID,
+			&alert.SavedSearchName,
+			&alert.Seen,
+			&alert.CreatedAt,
+			&candidate.PublicID,
+			&candidate.CurrentPosition,
+			&candidate.Education,
+			&candidate.Resume,
+			&candidate.Bio,
+			&candidate.Photo,
+			&candidate.FirstName,
+			&candidate.LastName,
+		)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning saved search alert: %v", err)
+			return nil, 0, err
+		}
+		res = append(res, alert)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over saved search alert rows: %v", err)
+		return nil, 0, err
+	}
+
+	var totalCount int
+	err = conn(ctx, r.db).QueryRow(ctx, countQuery, recruiterPublicID, unseenOnly).Scan(&totalCount)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving saved search alerts count: %v", err)
+		return nil, 0, err
+	}
+
+	return res, totalCount, nil
+}
+
+// MarkAlertsSeen marks the given alerts of the recruiter as seen. An empty list marks every alert as seen.
+func (r *savedSearchRepository) MarkAlertsSeen(ctx context.Context, recruiterPublicID string, alertPublicIDs []string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		UPDATE saved_search_alerts a
+		SET seen = TRUE
+		FROM saved_searches ss
+		WHERE ss.id = a.saved_search_id
+			AND ss.recruiter_public_id = $1
+			AND NOT a.seen
+			AND (COALESCE(cardinality($2::text[]), 0) = 0 OR a.public_id::text = ANY($2))`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, recruiterPublicID, alertPublicIDs)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while marking saved search alerts as seen: %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,348 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
+)
+
+// shortlistRepository represents the repository for managing recruiter shortlists in the database.
+type shortlistRepository struct {
+	db     *pgxpool.Pool
+	cfg    *config.DBConf
+	logger *zap.SugaredLogger
+}
+
+// NewShortlistRepository creates a new instance of shortlistRepository.
+func NewShortlistRepository(db *pgxpool.Pool, cfg *config.DBConf, logger *zap.SugaredLogger) ShortlistRepository {
+	return &shortlistRepository{
+		db:     db,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// CreateShortlist creates a new shortlist and returns its public ID
+func (r *shortlistRepository) CreateShortlist(ctx context.Context, shortlist *models.Shortlist) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		INSERT INTO shortlists (recruiter_public_id, company_public_id, name, description, shared)
+		VALUES ($1, $2, $3, COALESCE($4, ''), COALESCE($5, FALSE))
+		RETURNING public_id`
+
+	var publicID string
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		shortlist.RecruiterPublicID,
+		shortlist.CompanyPublicID,
+		shortlist.Name,
+		shortlist.Description,
+		shortlist.Shared,
+	).Scan(&publicID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while creating shortlist: %v", err)
+		return "", err
+	}
+
+	return publicID, nil
+}
+
+// GetShortlist retrieves a shortlist by its public ID
+func (r *shortlistRepository) GetShortlist(ctx context.Context, publicID string) (*models.Shortlist, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		SELECT s.public_id, s.recruiter_public_id, s.company_public_id, s.name, s.description, s.shared, s.created_at,
+			(SELECT COUNT(*) FROM shortlist_candidates sc WHERE sc.shortlist_id = s.id)
+		FROM shortlists s
+		WHERE s.public_id = $1`
+
+	shortlist := &models.Shortlist{}
+	err := conn(ctx, r.db).QueryRow(ctx, query, publicID).Scan(
+		&shortlist.PublicID,
+		&shortlist.RecruiterPublicID,
+		&shortlist.CompanyPublicID,
+		&shortlist.Name,
+		&shortlist.Description,
+		&shortlist.Shared,
+		&shortlist.CreatedAt,
+		&shortlist.CandidatesCount,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrShortlistNotFound
+		}
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving shortlist: %v", err)
+		return nil, err
+	}
+
+	return shortlist, nil
+}
+
+// GetShortlists retrieves the shortlists owned by the recruiter together with the ones
+// shared across the recruiter's company, along with the total count
+func (r *shortlistRepository) GetShortlists(ctx context.Context, recruiterPublicID, companyPublicID string, args *models.SearchArgs) ([]*models.Shortlist, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		SELECT s.public_id, s.recruiter_public_id, s.company_public_id, s.name, s.description, s.shared, s.created_at,
+			(SELECT COUNT(*) FROM shortlist_candidates sc WHERE sc.shortlist_id = s.id)
+		FROM shortlists s
+		WHERE (s.recruiter_public_id = $1 OR (s.shared AND s.company_public_id = $2))
+			AND s.name ILIKE $3
+		ORDER BY s.created_at DESC, s.id DESC
+		LIMIT $4 OFFSET $5`
+
+	countQuery := `
+		SELECT COUNT(*)
+		FROM shortlists s
+		WHERE (s.recruiter_public_id = $1 OR (s.shared AND s.company_public_id = $2))
+			AND s.name ILIKE $3`
+
+	searchPattern := "%" + args.Search + "%"
+	offset := (args.PageNum - 1) * args.PageSize
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, recruiterPublicID, companyPublicID, searchPattern, args.PageSize, offset)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving shortlists: %v", err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	shortlists := make([]*models.Shortlist, 0)
+	for rows.Next() {
+		shortlist := &models.Shortlist{}
+		err := rows.Scan(
+			&shortlist.PublicID,
+			&shortlist.RecruiterPublicID,
+			&shortlist.CompanyPublicID,
+			&shortlist.Name,
+			&shortlist.Description,
+			&shortlist.Shared,
+			&shortlist.CreatedAt,
+			&shortlist.CandidatesCount,
+		)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning shortlist: %v", err)
+			return nil, 0, err
+		}
+		shortlists = append(shortlists, shortlist)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over shortlist rows: %v", err)
+		return nil, 0, err
+	}
+
+	var totalCount int
+	err = conn(ctx, r.db).QueryRow(ctx, countQuery, recruiterPublicID, companyPublicID, searchPattern).Scan(&totalCount)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving total count of shortlists: %v", err)
+		return nil, 0, err
+	}
+
+	return shortlists, totalCount, nil
+}
+
+// UpdateShortlist updates the name, description and sharing of a shortlist
+func (r *shortlistRepository) UpdateShortlist(ctx context.Context, shortlist *models.Shortlist) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		UPDATE shortlists
+		SET
+			name = COALESCE($2, name),
+			description = COALESCE($3, description),
+			shared = COALESCE($4, shared)
+		WHERE public_id = $1`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, shortlist.PublicID, shortlist.Name, shortlist.Description, shortlist.Shared)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while updating shortlist: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteShortlist deletes a shortlist together with its entries
+func (r *shortlistRepository) DeleteShortlist(ctx context.Context, publicID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `DELETE FROM shortlists WHERE public_id = $1`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, publicID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while deleting shortlist: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// AddCandidate adds a candidate to the shortlist. Adding a candidate that is already
+// on the shortlist replaces the note.
+func (r *shortlistRepository) AddCandidate(ctx context.Context, shortlistPublicID, candidatePublicID, recruiterPublicID, note string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		INSERT INTO shortlist_candidates (shortlist_id, candidate_id, note, added_by)
+		SELECT s.id, c.id, $3, $4
+		FROM shortlists s, candidates c
+		WHERE s.public_id = $1 AND c.public_id = $2
+		ON CONFLICT (shortlist_id, candidate_id) DO UPDATE SET note = EXCLUDED.note`
+
+	tag, err := conn(ctx, r.db).Exec(ctx, query, shortlistPublicID, candidatePublicID, note, recruiterPublicID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while adding candidate to shortlist: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// RemoveCandidate removes a candidate from the shortlist
+func (r *shortlistRepository) RemoveCandidate(ctx context.Context, shortlistPublicID, candidatePublicID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		DELETE FROM shortlist_candidates
+		WHERE shortlist_id = (SELECT id FROM shortlists WHERE public_id = $1)
+		AND candidate_id = (SELECT id FROM candidates WHERE public_id = $2)`
+
+	tag, err := conn(ctx, r.db).Exec(ctx, query, shortlistPublicID, candidatePublicID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while removing candidate from shortlist: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// GetCandidates retrieves the candidates on the shortlist, most recently added first.
+// A page size of zero returns every candidate on the shortlist.
+func (r *shortlistRepository) GetCandidates(ctx context.Context, shortlistPublicID string, args *models.SearchArgs) ([]*models.ShortlistCandidate, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		SELECT
+			c.public_id,
+			c.current_position,
+			c.education,
+			c.resume,
+			c.bio,
+			u.photo,
+			u.first_name,
+			u.last_name,
+			array_agg(s.name) AS skills,
+			sc.note,
+			sc.added_by,
+			sc.added_at
+		FROM
+			shortlist_candidates sc
+		JOIN
+			shortlists sl ON sl.id = sc.shortlist_id
+		JOIN
+			candidates c ON c.id = sc.candidate_id
+		JOIN
+			users u ON c.public_id = u.public_id
+		LEFT JOIN
+			candidate_skills cs ON c.id = cs.candidate_id
+		LEFT JOIN
+			skills s ON cs.skill_id = s.id
+		WHERE
+			sl.public_id = $1
+			AND (LOWER(u.first_name) LIKE LOWER($2) OR LOWER(u.last_name) LIKE LOWER($2))
+		GROUP BY
+			c.public_id,
+			c.current_position,
+			c.education,
+			c.resume,
+			c.bio,
+			u.photo,
+			u.first_name,
+			u.last_name,
+			sc.note,
+			sc.added_by,
+			sc.added_at
+		ORDER BY sc.added_at DESC
+		LIMIT NULLIF($3, 0) OFFSET $4`
+
+	countQuery := `
+		SELECT COUNT(*)
+		FROM shortlist_candidates sc
+		JOIN shortlists sl ON sl.id = sc.shortlist_id
+		JOIN candidates c ON c.id = sc.candidate_id
+		JOIN users u ON c.public_id = u.public_id
+		WHERE sl.public_id = $1
+			AND (LOWER(u.first_name) LIKE LOWER($2) OR LOWER(u.last_name) LIKE LOWER($2))`
+
+	searchPattern := "%" + args.Search + "%"
+	offset := (args.PageNum - 1) * args.PageSize
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, shortlistPublicID, searchPattern, args.PageSize, offset)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving shortlist candidates: %v", err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	res := make([]*models.ShortlistCandidate, 0)
+	for rows.Next() {
+		candidate := &models.Candidate{}
+		entry := &models.ShortlistCandidate{Candidate: candidate}
+		err := rows.Scan(
+			&candidate.PublicID,
+			&candidate.CurrentPosition,
+			&candidate.Education,
+			&candidate.Resume,
+			&candidate.Bio,
+			&candidate.Photo,
+			&candidate.FirstName,
+			&candidate.LastName,
+			&candidate.Skills,
+			&entry.Note,
+			&entry.AddedBy,
+			&entry.AddedAt,
+		)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning shortlist candidate: %v", err)
+			return nil, 0, err
+		}
+		res = append(res, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over shortlist candidate rows: %v", err)
+		return nil, 0, err
+	}
+
+	var totalCount int
+	err = conn(ctx, r.db).QueryRow(ctx, countQuery, shortlistPublicID, searchPattern).Scan(&totalCount)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving shortlist candidates count: %v", err)
+		return nil, 0, err
+	}
+
+	return res, totalCount, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+)
+
+// normalizeSkills trims the skill names and drops the blank ones and the repeated ones. Names
+// that only differ in case are the same skill, the first spelling is kept.
+func normalizeSkills(skills []string) []string {
+	res := make([]string, 0, len(skills))
+	seen := make(map[string]bool, len(skills))
+	for _, skill := range skills {
+		skill = strings.TrimSpace(skill)
+		if skill == "" || seen[skillKey(skill)] {
+			continue
+		}
+		seen[skillKey(skill)] = true
+		res = append(res, skill)
+	}
+	return res
+}
+
+// skillKey is the normalized name skills are unique by, like the unique index on lower(name)
+func skillKey(skill string) string {
+	return strings.ToLower(skill)
+}
+
+func newSkillsChange() *models.SkillsChange {
+	return &models.SkillsChange{
+		Skills:  []string{},
+		Added:   []string{},
+		Removed: []string{},
+	}
+}
+
+// stampSkillsChange fills the skills added and removed in the payload of the events, as they are
+// only known once the change is applied
+func stampSkillsChange(events []*models.DomainEvent, change *models.SkillsChange) {
+	for _, event := range events {
+		if event == nil {
+			continue
+		}
+		if event.Payload == nil {
+			event.Payload = map[string]interface{}{}
+		}
+		event.Payload["added"] = change.Added
+		event.Payload["removed"] = change.Removed
+	}
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/metrics"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
+)
+
+// UnitOfWork groups repository calls, so they are applied together or not at all.
+type UnitOfWork interface {
+	// Do runs fn in a transaction, which the repository calls made with the context given to fn
+	// join. The transaction is committed when fn returns nil and rolled back otherwise. It is run
+	// again when it fails on a serialization failure or a deadlock, so fn must have no other side
+	// effects. Calls nested in fn join the outer transaction.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// dbtx is what the queries are run with, the pool or the transaction of the unit of work
+type dbtx interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+type (
+	txKey      struct{}
+	txHooksKey struct{}
+)
+
+// txHooks are the functions to run once a transaction ends, see afterTx
+type txHooks struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+func (h *txHooks) run() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, fn := range h.fns {
+		fn()
+	}
+}
+
+// afterTx registers fn to run once the transaction ctx runs in ends, committed or rolled back. Outside
+// a transaction it does nothing. Each attempt of a retried transaction runs the functions it registered.
+func afterTx(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(txHooksKey{}).(*txHooks)
+	if !ok {
+		return
+	}
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.fns = append(hooks.fns, fn)
+}
+
+// conn returns the transaction ctx runs in, or the pool. Transactions begun on it from within a
+// unit of work are savepoints of the unit of work.
+func conn(ctx context.Context, db *pgxpool.Pool) dbtx {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+type unitOfWork struct {
+	db     *pgxpool.Pool
+	cfg    *config.DBConf
+	logger *zap.SugaredLogger
+}
+
+// NewUnitOfWork creates a unit of work running transactions on the database.
+func NewUnitOfWork(db *pgxpool.Pool, cfg *config.DBConf, logger *zap.SugaredLogger) UnitOfWork {
+	return &unitOfWork{
+		db:     db,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return inTx(ctx, u.db, u.cfg, u.logger, false, fn)
+}
+
+// inTx runs fn in a transaction with the configured isolation level, retrying it on serialization
+// failures and deadlocks. If ctx already runs in a transaction fn joins it, and is retried by the
+// outermost call.
+func inTx(ctx context.Context, db *pgxpool.Pool, cfg *config.DBConf, logger *zap.SugaredLogger, readOnly bool, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	opts := pgx.TxOptions{IsoLevel: isolation(cfg.TxIsolation)}
+	if readOnly {
+		opts.AccessMode = pgx.ReadOnly
+	}
+	backoff := cfg.TxRetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := runTx(ctx, db, opts, fn)
+		reason := retryReason(err)
+		if reason == "" || attempt >= cfg.TxMaxAttempts {
+			return err
+		}
+		metrics.TransactionRetries.WithLabelValues(reason).Inc()
+		logging.FromContext(ctx, logger).Warnf("Retrying transaction after %s, attempt %d: %v", reason, attempt, err)
+
+		// the jitter keeps the transactions that conflicted from conflicting again
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff)+1))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+func runTx(ctx context.Context, db *pgxpool.Pool, opts pgx.TxOptions, fn func(ctx context.Context) error) error {
+	hooks := &txHooks{}
+	// deferred first so it runs last, after the rollback
+	defer hooks.run()
+
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	ctx = context.WithValue(ctx, txHooksKey{}, hooks)
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// retryReason returns why the transaction can be run again, or an empty string if it cannot
+func retryReason(err error) string {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return ""
+	}
+	switch pgErr.Code {
+	case "40001":
+		return "serialization_failure"
+	case "40P01":
+		return "deadlock"
+	}
+	return ""
+}
+
+func isolation(level string) pgx.TxIsoLevel {
+	switch level {
+	case "read_committed":
+		return pgx.ReadCommitted
+	case "serializable":
+		return pgx.Serializable
+	}
+	return pgx.RepeatableRead
+}
+
+// memoryUnitOfWork runs fn as is. Every call to the memory repositories is atomic on its own,
+// but calls grouped in fn are not isolated from concurrent ones.
+type memoryUnitOfWork struct{}
+
+func (memoryUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+)
+
+// The unsupported repositories stand in for the repositories the memory storage does not implement.
+// Every call fails with models.ErrNotSupported, which is rendered as 501 Not Implemented.
+
+type unsupportedShortlistRepository struct{}
+
+func (unsupportedShortlistRepository) CreateShortlist(ctx context.Context, shortlist *models.Shortlist) (string, error) {
+	return "", models.ErrNotSupported
+}
+
+func (unsupportedShortlistRepository) GetShortlist(ctx context.Context, publicID string) (*models.Shortlist, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedShortlistRepository) GetShortlists(ctx context.Context, recruiterPublicID, companyPublicID string, args *models.SearchArgs) ([]*models.Shortlist, int, error) {
+	return nil, 0, models.ErrNotSupported
+}
+
+func (unsupportedShortlistRepository) UpdateShortlist(ctx context.Context, shortlist *models.Shortlist) error {
+	return models.ErrNotSupported
+}
+
+func (unsupportedShortlistRepository) DeleteShortlist(ctx context.Context, publicID string) error {
+	return models.ErrNotSupported
+}
+
+func (unsupportedShortlistRepository) AddCandidate(ctx context.Context, shortlistPublicID, candidatePublicID, recruiterPublicID, note string) error {
+	return models.ErrNotSupported
+}
+
+func (unsupportedShortlistRepository) RemoveCandidate(ctx context.Context, shortlistPublicID, candidatePublicID string) error {
+	return models.ErrNotSupported
+}
+
+func (unsupportedShortlistRepository) GetCandidates(ctx context.Context, shortlistPublicID string, args *models.SearchArgs) ([]*models.ShortlistCandidate, int, error) {
+	return nil, 0, models.ErrNotSupported
+}
+
+type unsupportedNoteRepository struct{}
+
+func (unsupportedNoteRepository) SubjectExists(ctx context.Context, subjectType, subjectPublicID string) (bool, error) {
+	return false, models.ErrNotSupported
+}
+
+func (unsupportedNoteRepository) CreateNote(ctx context.Context, note *models.Note) (string, error) {
+	return "", models.ErrNotSupported
+}
+
+func (unsupportedNoteRepository) GetNote(ctx context.Context, publicID string) (*models.Note, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedNoteRepository) GetNotes(ctx context.Context, companyPublicID, subjectType, subjectPublicID string, args *models.SearchArgs) ([]*models.Note, int, error) {
+	return nil, 0, models.ErrNotSupported
+}
+
+func (unsupportedNoteRepository) GetMentionedNotes(ctx context.Context, recruiterPublicID, companyPublicID string, args *models.SearchArgs) ([]*models.Note, int, error) {
+	return nil, 0, models.ErrNotSupported
+}
+
+func (unsupportedNoteRepository) UpdateNote(ctx context.Context, note *models.Note, editorPublicID string) error {
+	return models.ErrNotSupported
+}
+
+func (unsupportedNoteRepository) DeleteNote(ctx context.Context, publicID string) error {
+	return models.ErrNotSupported
+}
+
+func (unsupportedNoteRepository) GetNoteRevisions(ctx context.Context, publicID string) ([]*models.NoteRevision, error) {
+	return nil, models.ErrNotSupported
+}
+
+type unsupportedSavedSearchRepository struct{}
+
+func (unsupportedSavedSearchRepository) CreateSavedSearch(ctx context.Context, savedSearch *models.SavedSearch) (string, error) {
+	return "", models.ErrNotSupported
+}
+
+func (unsupportedSavedSearchRepository) GetSavedSearch(ctx context.Context, publicID string) (*models.SavedSearch, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedSavedSearchRepository) GetSavedSearches(ctx context.Context, recruiterPublicID string, args *models.SearchArgs) ([]*models.SavedSearch, int, error) {
+	return nil, 0, models.ErrNotSupported
+}
+
+func (unsupportedSavedSearchRepository) GetSavedSearchIDs(ctx context.Context) ([]string, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedSavedSearchRepository) DeleteSavedSearch(ctx context.Context, publicID string) error {
+	return models.ErrNotSupported
+}
+
+func (unsupportedSavedSearchRepository) RecordNewMatches(ctx context.Context, publicID string) (int, error) {
+	return 0, models.ErrNotSupported
+}
+
+func (unsupportedSavedSearchRepository) GetAlerts(ctx context.Context, recruiterPublicID string, unseenOnly bool, args *models.SearchArgs) ([]*models.SavedSearchAlert, int, error) {
+	return nil, 0, models.ErrNotSupported
+}
+
+func (unsupportedSavedSearchRepository) MarkAlertsSeen(ctx context.Context, recruiterPublicID string, alertPublicIDs []string) error {
+	return models.ErrNotSupported
+}
+
+type unsupportedNotificationRepository struct{}
+
+func (unsupportedNotificationRepository) ClaimPendingNotifications(ctx context.Context, limit int, lease time.Duration) ([]*models.Notification, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedNotificationRepository) GetRecipient(ctx context.Context, userPublicID, eventType string) (*models.NotificationRecipient, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedNotificationRepository) RecordDelivery(ctx context.Context, publicID, status, recipient, deliveryErr string, nextAttemptAt *time.Time) error {
+	return models.ErrNotSupported
+}
+
+func (unsupportedNotificationRepository) GetNotifications(ctx context.Context, userPublicID string, args *models.SearchArgs) ([]*models.Notification, int, error) {
+	return nil, 0, models.ErrNotSupported
+}
+
+func (unsupportedNotificationRepository) GetPreferences(ctx context.Context, userPublicID string) ([]*models.NotificationPreference, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedNotificationRepository) SetPreferences(ctx context.Context, userPublicID string, preferences []*models.NotificationPreference) error {
+	return models.ErrNotSupported
+}
+
+type unsupportedEventRepository struct{}
+
+func (unsupportedEventRepository) ClaimUnpublishedEvents(ctx context.Context, limit int, lease time.Duration) ([]*models.DomainEvent, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedEventRepository) MarkEventPublished(ctx context.Context, publicID string) error {
+	return models.ErrNotSupported
+}
+
+func (unsupportedEventRepository) MarkEventFailed(ctx context.Context, publicID, publishErr string, nextAttemptAt time.Time) error {
+	return models.ErrNotSupported
+}
+
+type unsupportedWebhookRepository struct{}
+
+func (unsupportedWebhookRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) (string, error) {
+	return "", models.ErrNotSupported
+}
+
+func (unsupportedWebhookRepository) GetWebhook(ctx context.Context, publicID string) (*models.Webhook, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedWebhookRepository) GetWebhooks(ctx context.Context, companyPublicID string) ([]*models.Webhook, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedWebhookRepository) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	return models.ErrNotSupported
+}
+
+func (unsupportedWebhookRepository) DeleteWebhook(ctx context.Context, publicID string) error {
+	return models.ErrNotSupported
+}
+
+func (unsupportedWebhookRepository) GetDeliveries(ctx context.Context, webhookPublicID, status string, args *models.SearchArgs) ([]*models.WebhookDelivery, int, error) {
+	return nil, 0, models.ErrNotSupported
+}
+
+func (unsupportedWebhookRepository) GetDelivery(ctx context.Context, publicID string) (*models.WebhookDelivery, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedWebhookRepository) ReplayDelivery(ctx context.Context, publicID string) error {
+	return models.ErrNotSupported
+}
+
+func (unsupportedWebhookRepository) ClaimPendingDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedWebhookRepository) RecordDeliveryAttempt(ctx context.Context, publicID string, delivered bool, statusCode int, attemptErr string, nextAttemptAt *time.Time) error {
+	return models.ErrNotSupported
+}
+
+type unsupportedProvisioningRepository struct{}
+
+func (unsupportedProvisioningRepository) CreateCandidate(ctx context.Context, candidate *models.CandidateProvision, events ...*models.DomainEvent) (*models.ProvisionedUser, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedProvisioningRepository) CreateRecruiter(ctx context.Context, recruiter *models.RecruiterProvision, role string, events ...*models.DomainEvent) (*models.ProvisionedUser, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedProvisioningRepository) FindOrCreateCompany(ctx context.Context, company *models.Company, events ...*models.DomainEvent) (*models.Company, bool, error) {
+	return nil, false, models.ErrNotSupported
+}
+
+type unsupportedMembershipRepository struct{}
+
+func (unsupportedMembershipRepository) GetMembers(ctx context.Context, companyPublicID string) ([]*models.CompanyMember, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedMembershipRepository) HasOwner(ctx context.Context, companyPublicID string) (bool, error) {
+	return false, models.ErrNotSupported
+}
+
+func (unsupportedMembershipRepository) SetMembership(ctx context.Context, recruiterPublicID, companyPublicID, role string, events ...*models.DomainEvent) error {
+	return models.ErrNotSupported
+}
+
+func (unsupportedMembershipRepository) CreateInvitation(ctx context.Context, invitation *models.Invitation, tokenHash string, payload map[string]interface{}) (*models.Invitation, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedMembershipRepository) GetInvitations(ctx context.Context, companyPublicID string, args *models.SearchArgs) ([]*models.Invitation, int, error) {
+	return nil, 0, models.ErrNotSupported
+}
+
+func (unsupportedMembershipRepository) RevokeInvitation(ctx context.Context, companyPublicID, invitationPublicID string) error {
+	return models.ErrNotSupported
+}
+
+func (unsupportedMembershipRepository) AcceptInvitation(ctx context.Context, tokenHash, recruiterPublicID string) (*models.Invitation, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedMembershipRepository) CreateJoinRequest(ctx context.Context, joinRequest *models.JoinRequest) (*models.JoinRequest, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedMembershipRepository) GetJoinRequests(ctx context.Context, companyPublicID, status string, args *models.SearchArgs) ([]*models.JoinRequest, int, error) {
+	return nil, 0, models.ErrNotSupported
+}
+
+func (unsupportedMembershipRepository) DecideJoinRequest(ctx context.Context, companyPublicID, joinRequestPublicID, status, deciderPublicID string) (*models.JoinRequest, error) {
+	return nil, models.ErrNotSupported
+}
+
+type unsupportedDomainRepository struct{}
+
+func (unsupportedDomainRepository) CreateDomain(ctx context.Context, domain *models.CompanyDomain, codeHash string, codeExpiresAt *time.Time, payload map[string]interface{}) (*models.CompanyDomain, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedDomainRepository) GetDomains(ctx context.Context, companyPublicID string) ([]*models.CompanyDomain, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedDomainRepository) GetDomain(ctx context.Context, companyPublicID, domainPublicID string) (*models.CompanyDomain, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedDomainRepository) VerifyDomain(ctx context.Context, companyPublicID, domainPublicID, codeHash string, events ...*models.DomainEvent) (*models.CompanyDomain, error) {
+	return nil, models.ErrNotSupported
+}
+
+func (unsupportedDomainRepository) DeleteDomain(ctx context.Context, companyPublicID, domainPublicID string) error {
+	return models.ErrNotSupported
+}
+
+func (unsupportedDomainRepository) GetVerifiedCompany(ctx context.Context, domain string) (string, error) {
+	return "", models.ErrNotSupported
+}
+
+func (unsupportedDomainRepository) HasVerifiedEmail(ctx context.Context, companyPublicID, userPublicID string) (bool, error) {
+	return false, models.ErrNotSupported
+}
@@ -0,0 +1,395 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
+)
+
+// webhookRepository represents the repository for company webhooks and their deliveries.
+type webhookRepository struct {
+	db     *pgxpool.Pool
+	cfg    *config.DBConf
+	logger *zap.SugaredLogger
+}
+
+// NewWebhookRepository creates a new instance of webhookRepository.
+func NewWebhookRepository(db *pgxpool.Pool, cfg *config.DBConf, logger *zap.SugaredLogger) WebhookRepository {
+	return &webhookRepository{
+		db:     db,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// CreateWebhook creates a new webhook subscription for the company
+func (r *webhookRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		INSERT INTO company_webhooks (company_public_id, url, secret, event_types, active, created_by)
+		VALUES ($1, $2, $3, $4, COALESCE($5, TRUE), $6)
+		RETURNING public_id`
+
+	var publicID string
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		webhook.CompanyPublicID,
+		webhook.URL,
+		webhook.Secret,
+		webhook.EventTypes,
+		webhook.Active,
+		webhook.CreatedBy,
+	).Scan(&publicID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while creating webhook: %v", err)
+		return "", err
+	}
+
+	return publicID, nil
+}
+
+// GetWebhook retrieves a webhook by its public ID. The secret is not returned.
+func (r *webhookRepository) GetWebhook(ctx context.Context, publicID string) (*models.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		SELECT public_id, company_public_id, url, event_types, active, created_by, created_at
+		FROM company_webhooks
+		WHERE public_id = $1`
+
+	webhook := &models.Webhook{}
+	err := conn(ctx, r.db).QueryRow(ctx, query, publicID).Scan(
+		&webhook.PublicID,
+		&webhook.CompanyPublicID,
+		&webhook.URL,
+		&webhook.EventTypes,
+		&webhook.Active,
+		&webhook.CreatedBy,
+		&webhook.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrWebhookNotFound
+		}
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving webhook: %v", err)
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// GetWebhooks retrieves the webhooks of the company. The secrets are not returned.
+func (r *webhookRepository) GetWebhooks(ctx context.Context, companyPublicID string) ([]*models.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		SELECT public_id, company_public_id, url, event_types, active, created_by, created_at
+		FROM company_webhooks
+		WHERE company_public_id = $1
+		ORDER BY created_at, id`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, companyPublicID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving webhooks: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]*models.Webhook, 0)
+	for rows.Next() {
+		webhook := &models.Webhook{}
+		err := rows.Scan(
+			&webhook.PublicID,
+			&webhook.CompanyPublicID,
+			&webhook.URL,
+			&webhook.EventTypes,
+			&webhook.Active,
+			&webhook.CreatedBy,
+			&webhook.CreatedAt,
+		)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning webhook: %v", err)
+			return nil, err
+		}
+		res = append(res, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over webhook rows: %v", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// UpdateWebhook updates the fields of the webhook that are set
+func (r *webhookRepository) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		UPDATE company_webhooks
+		SET
+			url = COALESCE(NULLIF($2, ''), url),
+			secret = COALESCE(NULLIF($3, ''), secret),
+			event_types = COALESCE($4, event_types),
+			active = COALESCE($5, active)
+		WHERE public_id = $1`
+
+	tag, err := conn(ctx, r.db).Exec(ctx, query, webhook.PublicID, webhook.URL, webhook.Secret, webhook.EventTypes, webhook.Active)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while updating webhook: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// DeleteWebhook deletes the webhook together with its deliveries
+func (r *webhookRepository) DeleteWebhook(ctx context.Context, publicID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	tag, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM company_webhooks WHERE public_id = $1`, publicID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while deleting webhook: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// GetDeliveries retrieves the deliveries of the webhook, newest first. An empty status returns deliveries in any status.
+func (r *webhookRepository) GetDeliveries(ctx context.Context, webhookPublicID, status string, args *models.SearchArgs) ([]*models.WebhookDelivery, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		SELECT d.public_id, w.public_id, d.event_type, d.payload, d.status, d.attempts, d.last_status_code,
+			d.last_error, d.next_attempt_at, d.created_at, d.delivered_at
+		FROM webhook_deliveries d
+		JOIN company_webhooks w ON w.id = d.webhook_id
+		WHERE w.public_id = $1 AND ($2 = '' OR d.status = $2)
+		ORDER BY d.created_at DESC, d.id DESC
+		LIMIT $3 OFFSET $4`
+
+	offset := (args.PageNum - 1) * args.PageSize
+	rows, err := conn(ctx, r.db).Query(ctx, query, webhookPublicID, status, args.PageSize, offset)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving webhook deliveries: %v", err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	res := make([]*models.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning webhook delivery: %v", err)
+			return nil, 0, err
+		}
+		res = append(res, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over webhook delivery rows: %v", err)
+		return nil, 0, err
+	}
+
+	query = `
+		SELECT COUNT(*)
+		FROM webhook_deliveries d
+		JOIN company_webhooks w ON w.id = d.webhook_id
+		WHERE w.public_id = $1 AND ($2 = '' OR d.status = $2)`
+
+	var totalCount int
+	if err := conn(ctx, r.db).QueryRow(ctx, query, webhookPublicID, status).Scan(&totalCount); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving webhook deliveries count: %v", err)
+		return nil, 0, err
+	}
+
+	return res, totalCount, nil
+}
+
+// GetDelivery retrieves a webhook delivery by its public ID
+func (r *webhookRepository) GetDelivery(ctx context.Context, publicID string) (*models.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		SELECT d.public_id, w.public_id, d.event_type, d.payload, d.status, d.attempts, d.last_status_code,
+			d.last_error, d.next_attempt_at, d.created_at, d.delivered_at
+		FROM webhook_deliveries d
+		JOIN company_webhooks w ON w.id = d.webhook_id
+		WHERE d.public_id = $1`
+
+	delivery, err := scanWebhookDelivery(conn(ctx, r.db).QueryRow(ctx, query, publicID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrDeliveryNotFound
+		}
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while retrieving webhook delivery: %v", err)
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// ReplayDelivery queues the delivery to be sent again with a fresh set of attempts
+func (r *webhookRepository) ReplayDelivery(ctx context.Context, publicID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW(), delivered_at = NULL
+		WHERE public_id = $1`
+
+	tag, err := conn(ctx, r.db).Exec(ctx, query, publicID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while replaying webhook delivery: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrDeliveryNotFound
+	}
+
+	return nil
+}
+
+// ClaimPendingDeliveries leases up to limit deliveries of active webhooks that are due.
+// Leased deliveries are hidden from other workers until the lease expires.
+func (r *webhookRepository) ClaimPendingDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	query := `
+		WITH claimed AS (
+			UPDATE webhook_deliveries
+			SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond'
+			WHERE id IN (
+				SELECT d.id
+				FROM webhook_deliveries d
+				JOIN company_webhooks w ON w.id = d.webhook_id
+				WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND w.active
+				ORDER BY d.next_attempt_at, d.id
+				LIMIT $1
+				FOR UPDATE OF d SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT c.public_id, w.public_id, c.event_type, c.payload, c.status, c.attempts, c.last_status_code,
+			c.last_error, c.next_attempt_at, c.created_at, c.delivered_at, w.url, w.secret
+		FROM claimed c
+		JOIN company_webhooks w ON w.id = c.webhook_id
+		ORDER BY c.id`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while claiming pending webhook deliveries: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]*models.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery := &models.WebhookDelivery{}
+		err := rows.Scan(
+			&delivery.PublicID,
+			&delivery.WebhookPublicID,
+			&delivery.EventType,
+			&delivery.Payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.LastStatusCode,
+			&delivery.LastError,
+			&delivery.NextAttemptAt,
+			&delivery.CreatedAt,
+			&delivery.DeliveredAt,
+			&delivery.URL,
+			&delivery.Secret,
+		)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Errorf("Error occurred while scanning webhook delivery: %v", err)
+			return nil, err
+		}
+		res = append(res, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while iterating over webhook delivery rows: %v", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// RecordDeliveryAttempt stores the outcome of a delivery attempt.
+// For failed attempts nextAttemptAt schedules a retry; nil moves the delivery to the dead-letter state.
+func (r *webhookRepository) RecordDeliveryAttempt(ctx context.Context, publicID string, delivered bool, statusCode int, attemptErr string, nextAttemptAt *time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.TimeOut)
+	defer cancel()
+
+	status := models.WebhookDeliveryDelivered
+	if !delivered {
+		status = models.WebhookDeliveryPending
+		if nextAttemptAt == nil {
+			status = models.WebhookDeliveryDead
+		}
+	}
+
+	query := `
+		UPDATE webhook_deliveries
+		SET
+			status = $2,
+			attempts = attempts + 1,
+			last_status_code = NULLIF($3, 0),
+			last_error = NULLIF($4, ''),
+			next_attempt_at = COALESCE($5, next_attempt_at),
+			delivered_at = CASE WHEN $2 = 'delivered' THEN NOW() ELSE delivered_at END
+		WHERE public_id = $1`
+
+	if _, err := conn(ctx, r.db).Exec(ctx, query, publicID, status, statusCode, attemptErr, nextAttemptAt); err != nil {
+		logging.FromContext(ctx, r.logger).Errorf("Error occurred while recording webhook delivery attempt: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func scanWebhookDelivery(row pgx.Row) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{}
+	err := row.Scan(
+		&delivery.PublicID,
+		&delivery.WebhookPublicID,
+		&delivery.EventType,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.LastStatusCode,
+		&delivery.LastError,
+		&delivery.NextAttemptAt,
+		&delivery.CreatedAt,
+		&delivery.DeliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
@@ -1,9 +1,13 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"strings"
 
 	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/metrics"
 	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
 	"github.com/Zhiyenbek/sp-users-main-service/internal/repository"
 	"go.uber.org/zap"
@@ -22,19 +26,20 @@ func NewCandidatesService(repo *repository.Repository, cfg *config.Configs, logg
 		logger:        logger,
 	}
 }
-func (s *candidatesService) GetCandidatesBySearch(req *models.SearchArgs) ([]*models.Candidate, int, error) {
-	res, count, err := s.candidateRepo.GetCandidatesBySearch(req)
+func (s *candidatesService) GetCandidatesBySearch(ctx context.Context, req *models.SearchArgs) ([]*models.Candidate, int, error) {
+	req.Filters = normalizeFilters(req.Filters)
+	res, count, err := s.candidateRepo.GetCandidatesBySearch(ctx, req)
 	if err != nil {
 		return nil, 0, err
 	}
 	return res, count, nil
 }
-func (s *candidatesService) GetCandidateByPublicID(publicID string) (*models.Candidate, error) {
-	return s.candidateRepo.GetCandidateByPublicID(publicID)
+func (s *candidatesService) GetCandidateByPublicID(ctx context.Context, publicID string) (*models.Candidate, error) {
+	return s.candidateRepo.GetCandidateByPublicID(ctx, publicID)
 }
 
-func (s *candidatesService) Exists(publicID string) error {
-	exists, err := s.candidateRepo.Exists(publicID)
+func (s *candidatesService) Exists(ctx context.Context, publicID string) error {
+	exists, err := s.candidateRepo.Exists(ctx, publicID)
 	if err != nil {
 		return err
 	}
@@ -44,23 +49,95 @@ func (s *candidatesService) Exists(publicID string) error {
 	return nil
 }
 
-func (s *candidatesService) AddSkillsToCandidate(candidateID string, skills []string) error {
-	return s.candidateRepo.AddSkillsToCandidate(candidateID, skills)
+func (s *candidatesService) AddSkillsToCandidate(ctx context.Context, candidateID string, skills []string) error {
+	change, err := s.candidateRepo.AddSkillsToCandidate(ctx, candidateID, skills, skillsChanged(candidateID))
+	if err != nil {
+		return err
+	}
+	countSkillsChange(change)
+	return nil
+}
+
+func (s *candidatesService) UpdateCandidateByID(ctx context.Context, candidateID string, updateData *models.Candidate) error {
+	err := s.candidateRepo.UpdateCandidateByID(ctx, candidateID, updateData,
+		newDomainEvent(models.EventCandidateUpdated, models.AggregateCandidate, candidateID, map[string]interface{}{
+			"candidate_public_id": candidateID,
+			"changes":             candidateChanges(updateData),
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	metrics.ProfileUpdates.Inc()
+	return nil
+}
+func (s *candidatesService) DeleteCandidateByID(ctx context.Context, candidateID string) error {
+	err := s.candidateRepo.DeleteCandidateByID(ctx, candidateID,
+		newDomainEvent(models.EventCandidateDeleted, models.AggregateCandidate, candidateID, map[string]interface{}{
+			"candidate_public_id": candidateID,
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	metrics.ProfileDeletions.Inc()
+	return nil
+}
+
+func (s *candidatesService) DeleteSkillsFromCandidate(ctx context.Context, candidateID string, skills []string) error {
+	change, err := s.candidateRepo.DeleteSkillsFromCandidate(ctx, candidateID, skills, skillsChanged(candidateID))
+	if err != nil {
+		return err
+	}
+	countSkillsChange(change)
+	return nil
 }
 
-func (s *candidatesService) UpdateCandidateByID(candidateID string, updateData *models.Candidate) error {
-	return s.candidateRepo.UpdateCandidateByID(candidateID, updateData)
+// ReplaceCandidateSkills sets the skills of the candidate to exactly the given ones
+func (s *candidatesService) ReplaceCandidateSkills(ctx context.Context, candidateID string, skills []string) (*models.SkillsChange, error) {
+	change, err := s.candidateRepo.ReplaceCandidateSkills(ctx, candidateID, skills, skillsChanged(candidateID))
+	if err != nil {
+		return nil, err
+	}
+	countSkillsChange(change)
+	return change, nil
 }
-func (s *candidatesService) DeleteCandidateByID(candidateID string) error {
-	return s.candidateRepo.DeleteCandidateByID(candidateID)
+
+// skillsChanged returns the event of a change of the skills of the candidate. The repository
+// fills in the skills added and removed.
+func skillsChanged(candidateID string) *models.DomainEvent {
+	return newDomainEvent(models.EventSkillsChanged, models.AggregateCandidate, candidateID, map[string]interface{}{
+		"candidate_public_id": candidateID,
+	})
+}
+
+func countSkillsChange(change *models.SkillsChange) {
+	metrics.SkillsAdded.Add(float64(len(change.Added)))
+	metrics.SkillsRemoved.Add(float64(len(change.Removed)))
 }
 
-func (s *candidatesService) DeleteSkillsFromCandidate(candidateID string, skills []string) error {
-	return s.candidateRepo.DeleteSkillsFromCandidate(candidateID, skills)
+// candidateChanges returns the fields set in the update, keyed by their JSON name.
+func candidateChanges(updateData *models.Candidate) map[string]interface{} {
+	changes := make(map[string]interface{})
+	fields := map[string]*string{
+		"first_name":       updateData.FirstName,
+		"last_name":        updateData.LastName,
+		"current_position": updateData.CurrentPosition,
+		"education":        updateData.Education,
+		"resume":           updateData.Resume,
+		"bio":              updateData.Bio,
+		"photo":            updateData.Photo,
+	}
+	for name, value := range fields {
+		if value != nil {
+			changes[name] = *value
+		}
+	}
+	return changes
 }
 
-func (s *candidatesService) GetInterviewsByPublicID(publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error) {
-	res, count, err := s.candidateRepo.GetInterviewsByPublicID(publicID, searchArgs)
+func (s *candidatesService) GetInterviewsByPublicID(ctx context.Context, publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error) {
+	res, count, err := s.candidateRepo.GetInterviewsByPublicID(ctx, publicID, searchArgs)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -69,12 +146,33 @@ func (s *candidatesService) GetInterviewsByPublicID(publicID string, searchArgs
 			result := models.Result{}
 			err = json.Unmarshal(r.RawResult, &result)
 			if err != nil {
-				s.logger.Error(err)
+				logging.FromContext(ctx, s.logger).Error(err)
 				return nil, 0, err
 			}
 			r.Result = result
 		}
 	}
+	metrics.InterviewsServed.WithLabelValues("candidate").Add(float64(len(res)))
 	return res, count, nil
 
 }
+
+// normalizeFilters trims the candidate filters and drops the empty ones, returning nil when none is left
+func normalizeFilters(filters *models.CandidateFilters) *models.CandidateFilters {
+	if filters == nil {
+		return nil
+	}
+	res := &models.CandidateFilters{
+		CurrentPosition: strings.TrimSpace(filters.CurrentPosition),
+		Education:       strings.TrimSpace(filters.Education),
+	}
+	for _, skill := range filters.Skills {
+		if skill = strings.TrimSpace(skill); skill != "" {
+			res.Skills = append(res.Skills, skill)
+		}
+	}
+	if res.CurrentPosition == "" && res.Education == "" && len(res.Skills) == 0 {
+		return nil
+	}
+	return res
+}
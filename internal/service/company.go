@@ -1,44 +1,67 @@
 package service
 
 import (
+	"context"
+
 	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/metrics"
 	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
 	"github.com/Zhiyenbek/sp-users-main-service/internal/repository"
 	"go.uber.org/zap"
 )
 
 type companyService struct {
-	companyRepo repository.CompanyRepository
-	cfg         *config.Configs
-	logger      *zap.SugaredLogger
+	companyRepo   repository.CompanyRepository
+	recruiterRepo repository.RecruiterRepository
+	cfg           *config.Configs
+	logger        *zap.SugaredLogger
 }
 
-func NewCompanyService(companyRepo repository.CompanyRepository, cfg *config.Configs, logger *zap.SugaredLogger) *companyService {
+func NewCompanyService(repo *repository.Repository, cfg *config.Configs, logger *zap.SugaredLogger) *companyService {
 	return &companyService{
-		companyRepo: companyRepo,
-		cfg:         cfg,
-		logger:      logger,
+		companyRepo:   repo.CompanyRepository,
+		recruiterRepo: repo.RecruiterRepository,
+		cfg:           cfg,
+		logger:        logger,
+	}
+}
+
+func (s *companyService) CreateCompany(ctx context.Context, company *models.Company) (string, error) {
+	// the repository stamps the event with the public ID of the new company
+	publicID, err := s.companyRepo.CreateCompany(ctx, company, companyCreated(company))
+	if err != nil {
+		return "", err
 	}
+	metrics.CompaniesCreated.Inc()
+	return publicID, nil
 }
 
-func (s *companyService) CreateCompany(company *models.Company) (string, error) {
-	return s.companyRepo.CreateCompany(company)
+func (s *companyService) UpdateCompany(ctx context.Context, company *models.Company) error {
+	return s.companyRepo.UpdateCompany(ctx, company)
 }
 
-func (s *companyService) UpdateCompany(company *models.Company) error {
-	return s.companyRepo.UpdateCompany(company)
+// EditCompany updates the company for one of its admins or owners
+func (s *companyService) EditCompany(ctx context.Context, recruiterID string, company *models.Company) error {
+	membership, err := s.recruiterRepo.GetMembership(ctx, recruiterID)
+	if err != nil {
+		return err
+	}
+	if membership.CompanyPublicID != company.PublicID || !membership.IsAdmin() {
+		return models.ErrPermissionDenied
+	}
+	return s.companyRepo.UpdateCompany(ctx, company)
 }
 
-func (s *companyService) GetCompany(publicID string) (*models.Company, error) {
-	return s.companyRepo.GetCompany(publicID)
+func (s *companyService) GetCompany(ctx context.Context, publicID string) (*models.Company, error) {
+	return s.companyRepo.GetCompany(ctx, publicID)
 }
 
-func (s *companyService) GetCompanies(args *models.SearchArgs) ([]*models.Company, int, error) {
-	return s.companyRepo.GetCompanies(args)
+func (s *companyService) GetCompanies(ctx context.Context, args *models.SearchArgs) ([]*models.Company, int, error) {
+	return s.companyRepo.GetCompanies(ctx, args)
 }
 
-func (s *companyService) Exists(publicID string) error {
-	exists, err := s.companyRepo.Exists(publicID)
+func (s *companyService) Exists(ctx context.Context, publicID string) error {
+	exists, err := s.companyRepo.Exists(ctx, publicID)
 	if err != nil {
 		return err
 	}
@@ -47,3 +70,13 @@ func (s *companyService) Exists(publicID string) error {
 	}
 	return nil
 }
+
+// companyCreated returns the event of the creation of the company, which the repository stamps
+// with its public ID
+func companyCreated(company *models.Company) *models.DomainEvent {
+	return newDomainEvent(models.EventCompanyCreated, models.AggregateCompany, "", map[string]interface{}{
+		"name":        company.Name,
+		"logo":        company.Logo,
+		"description": company.Description,
+	})
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/domains"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/metrics"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultLookupTimeout = 5 * time.Second
+	defaultCodeTTL       = 24 * time.Hour
+)
+
+type domainService struct {
+	cfg            *config.Configs
+	logger         *zap.SugaredLogger
+	domainRepo     repository.DomainRepository
+	membershipRepo repository.MembershipRepository
+	recruiterRepo  repository.RecruiterRepository
+	companyRepo    repository.CompanyRepository
+	uow            repository.UnitOfWork
+	resolver       domains.Resolver
+	lookupTimeout  time.Duration
+	codeTTL        time.Duration
+}
+
+func NewDomainService(repo *repository.Repository, cfg *config.Configs, logger *zap.SugaredLogger) *domainService {
+	s := &domainService{
+		domainRepo:     repo.DomainRepository,
+		membershipRepo: repo.MembershipRepository,
+		recruiterRepo:  repo.RecruiterRepository,
+		companyRepo:    repo.CompanyRepository,
+		uow:            repo.UnitOfWork,
+		lookupTimeout:  defaultLookupTimeout,
+		codeTTL:        defaultCodeTTL,
+		cfg:            cfg,
+		logger:         logger,
+	}
+	if cfg.DomainVerification != nil {
+		s.lookupTimeout = cfg.DomainVerification.LookupTimeout
+		s.codeTTL = cfg.DomainVerification.CodeTTL
+	}
+	resolver, err := domains.NewResolver(cfg.DomainVerification)
+	if err != nil {
+		logger.Errorf("failed to create domain resolver: %v", err)
+		resolver = domains.NewDNSResolver()
+	}
+	s.resolver = resolver
+	return s
+}
+
+// GetDomains returns the domains of the company, to its admins
+func (s *domainService) GetDomains(ctx context.Context, recruiterID, companyID string) ([]*models.CompanyDomain, error) {
+	if err := s.authorize(ctx, recruiterID, companyID); err != nil {
+		return nil, err
+	}
+	res, err := s.domainRepo.GetDomains(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	for _, domain := range res {
+		describeDomain(domain)
+	}
+	return res, nil
+}
+
+// ClaimDomain claims the email domain for the company. A domain verified with DNS gets a token to
+// publish in a TXT record, a domain verified by email gets a code sent to the address on it.
+// Claiming a pending domain again starts its verification over.
+func (s *domainService) ClaimDomain(ctx context.Context, recruiterID, companyID string, domain *models.CompanyDomain) (*models.CompanyDomain, error) {
+	name, err := domains.Normalize(domain.Domain)
+	if err != nil {
+		return nil, models.ErrInvalidInput.WithField("domain", err.Error())
+	}
+	if domain.Method == "" {
+		domain.Method = models.DomainMethodDNS
+	}
+	if domain.Method != models.DomainMethodDNS && domain.Method != models.DomainMethodEmail {
+		return nil, models.ErrInvalidInput.WithField("method", "must be one of dns, email")
+	}
+	domain.Email = strings.TrimSpace(domain.Email)
+	if domain.Method == models.DomainMethodEmail && domains.EmailDomain(domain.Email) != name {
+		return nil, models.ErrInvalidInput.WithField("email", "must be an address on the domain")
+	}
+	if err := s.authorize(ctx, recruiterID, companyID); err != nil {
+		return nil, err
+	}
+
+	domain.Domain = name
+	domain.CompanyPublicID = companyID
+	domain.CreatedBy = recruiterID
+	var (
+		codeHash  string
+		expiresAt *time.Time
+		payload   map[string]interface{}
+	)
+	switch domain.Method {
+	case models.DomainMethodDNS:
+		domain.Email = ""
+		domain.DNSToken, err = newDNSToken()
+		if err != nil {
+			return nil, err
+		}
+	case models.DomainMethodEmail:
+		company, err := s.companyRepo.GetCompany(ctx, companyID)
+		if err != nil {
+			return nil, err
+		}
+		if company == nil {
+			return nil, models.ErrCompanyNotFound
+		}
+		code, err := newVerificationCode()
+		if err != nil {
+			return nil, err
+		}
+		codeHash = hashToken(code)
+		expires := time.Now().Add(s.codeTTL)
+		expiresAt = &expires
+		payload = map[string]interface{}{
+			"company_public_id": companyID,
+			"company_name":      company.Name,
+			"domain":            name,
+			"code":              code,
+			"expires_at":        expires.UTC().Format(time.RFC1123),
+		}
+	}
+
+	res, err := s.domainRepo.CreateDomain(ctx, domain, codeHash, expiresAt, payload)
+	if err != nil {
+		return nil, err
+	}
+	return describeDomain(res), nil
+}
+
+// VerifyDomain checks that the company owns the domain: the TXT record of a domain verified with DNS
+// must hold its token, and a domain verified by email must be sent the code before it expires.
+func (s *domainService) VerifyDomain(ctx context.Context, recruiterID, companyID, domainID, code string) (*models.CompanyDomain, error) {
+	if err := s.authorize(ctx, recruiterID, companyID); err != nil {
+		return nil, err
+	}
+	domain, err := s.domainRepo.GetDomain(ctx, companyID, domainID)
+	if err != nil {
+		return nil, err
+	}
+	if domain.Status == models.DomainVerified {
+		return describeDomain(domain), nil
+	}
+
+	codeHash := ""
+	switch domain.Method {
+	case models.DomainMethodDNS:
+		if err := s.lookup(ctx, domain); err != nil {
+			metrics.DomainVerifications.WithLabelValues(domain.Method, "failed").Inc()
+			return nil, err
+		}
+	case models.DomainMethodEmail:
+		code = strings.TrimSpace(code)
+		if code == "" {
+			return nil, models.ErrInvalidInput.WithField("code", "is required for domains verified by email")
+		}
+		codeHash = hashToken(strings.ToUpper(code))
+	}
+
+	res, err := s.domainRepo.VerifyDomain(ctx, companyID, domainID, codeHash,
+		newDomainEvent(models.EventCompanyDomainVerified, models.AggregateCompany, companyID, map[string]interface{}{
+			"company_public_id": companyID,
+			"domain":            domain.Domain,
+			"method":            domain.Method,
+			"actor_public_id":   recruiterID,
+		}),
+	)
+	if err != nil {
+		if errors.Is(err, models.ErrDomainNotVerified) || errors.Is(err, models.ErrDomainTaken) {
+			metrics.DomainVerifications.WithLabelValues(domain.Method, "failed").Inc()
+		}
+		return nil, err
+	}
+	metrics.DomainVerifications.WithLabelValues(domain.Method, "verified").Inc()
+	return describeDomain(res), nil
+}
+
+// DeleteDomain removes the domain from the company. Recruiters can no longer join it with an address
+// on the domain, the members who did stay.
+func (s *domainService) DeleteDomain(ctx context.Context, recruiterID, companyID, domainID string) error {
+	if err := s.authorize(ctx, recruiterID, companyID); err != nil {
+		return err
+	}
+	return s.domainRepo.DeleteDomain(ctx, companyID, domainID)
+}
+
+// JoinCompany moves the recruiter to the company as a recruiter, without an invitation, if the email
+// address of its user is on a domain the company verified.
+func (s *domainService) JoinCompany(ctx context.Context, recruiterID, companyID string) (*models.Membership, error) {
+	exists, err := s.companyRepo.Exists(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, models.ErrCompanyNotFound
+	}
+
+	var events []*models.DomainEvent
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		events = nil
+		current, err := s.recruiterRepo.GetMembership(ctx, recruiterID)
+		if err != nil {
+			return err
+		}
+		if current.CompanyPublicID == companyID {
+			return models.ErrAlreadyMember
+		}
+		verified, err := s.domainRepo.HasVerifiedEmail(ctx, companyID, recruiterID)
+		if err != nil {
+			return err
+		}
+		if !verified {
+			return models.ErrEmailDomainMismatch
+		}
+		events = membershipChanged(recruiterID, current, companyID, models.RoleRecruiter, recruiterID)
+		return s.membershipRepo.SetMembership(ctx, recruiterID, companyID, models.RoleRecruiter, events...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	countMembershipChanges(events)
+	return &models.Membership{CompanyPublicID: companyID, Role: models.RoleRecruiter}, nil
+}
+
+// lookup checks that a TXT record of the domain holds its token
+func (s *domainService) lookup(ctx context.Context, domain *models.CompanyDomain) error {
+	ctx, cancel := context.WithTimeout(ctx, s.lookupTimeout)
+	defer cancel()
+
+	records, err := s.resolver.LookupTXT(ctx, domains.RecordName(domain.Domain))
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Warnf("Error occurred while looking up the TXT records of %s: %v", domain.Domain, err)
+		return models.ErrDomainNotVerified.Wrap(err)
+	}
+	for _, record := range records {
+		if strings.TrimSpace(record) == domain.DNSToken {
+			return nil
+		}
+	}
+	return models.ErrDomainNotVerified
+}
+
+// authorize checks that the recruiter is an admin or owner of the company
+func (s *domainService) authorize(ctx context.Context, recruiterID, companyID string) error {
+	membership, err := s.recruiterRepo.GetMembership(ctx, recruiterID)
+	if err != nil {
+		return err
+	}
+	if membership.CompanyPublicID != companyID || !membership.IsAdmin() {
+		return models.ErrPermissionDenied
+	}
+	return nil
+}
+
+// describeDomain sets the TXT record a pending domain verified with DNS must publish
+func describeDomain(domain *models.CompanyDomain) *models.CompanyDomain {
+	if domain.Method == models.DomainMethodDNS && domain.Status == models.DomainPending {
+		domain.DNSRecord = &models.DNSRecord{
+			Type:  "TXT",
+			Name:  domains.RecordName(domain.Domain),
+			Value: domain.DNSToken,
+		}
+	}
+	return domain
+}
+
+// newDNSToken returns the random value of the TXT record proving the ownership of a domain
+func newDNSToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// newVerificationCode returns a random code, sent by email and only stored hashed. It is short enough
+// to be typed in, in upper case without padding.
+func newVerificationCode() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b), nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/events"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultEventBatchSize    = 100
+	defaultEventRetryBackoff = 10 * time.Second
+	maxEventRetryBackoff     = time.Hour
+	// eventLease is how long a claimed event is hidden from other relays.
+	eventLease = time.Minute
+	// eventPublishTimeout bounds a single publish, so a hanging broker does not outlive the lease.
+	eventPublishTimeout = 10 * time.Second
+)
+
+var errPublisherNotConfigured = errors.New("event publisher is not configured")
+
+type eventService struct {
+	cfg       *config.Configs
+	logger    *zap.SugaredLogger
+	eventRepo repository.EventRepository
+	publisher events.Publisher
+}
+
+func NewEventService(repo *repository.Repository, cfg *config.Configs, logger *zap.SugaredLogger) *eventService {
+	s := &eventService{
+		eventRepo: repo.EventRepository,
+		cfg:       cfg,
+		logger:    logger,
+	}
+	publisher, err := events.NewPublisher(cfg.Events, logger)
+	if err != nil {
+		logger.Errorf("failed to create event publisher: %v", err)
+	}
+	s.publisher = publisher
+	return s
+}
+
+// PublishEvents publishes the events in the outbox that are due and returns how many were published.
+// Events of an aggregate are published in the order they occurred: once an event fails,
+// the later events of the same aggregate wait until it has been published.
+func (s *eventService) PublishEvents(ctx context.Context) (int, error) {
+	if s.publisher == nil {
+		return 0, errPublisherNotConfigured
+	}
+	batchSize, backoff := defaultEventBatchSize, defaultEventRetryBackoff
+	if cfg := s.cfg.Events; cfg != nil {
+		if cfg.BatchSize > 0 {
+			batchSize = cfg.BatchSize
+		}
+		if cfg.RetryBackoff > 0 {
+			backoff = cfg.RetryBackoff
+		}
+	}
+
+	pending, err := s.eventRepo.ClaimUnpublishedEvents(ctx, batchSize, eventLease)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	blocked := make(map[string]bool)
+	for _, event := range pending {
+		aggregate := event.AggregateType + ":" + event.AggregatePublicID
+		if blocked[aggregate] {
+			continue
+		}
+		publishCtx, cancel := context.WithTimeout(ctx, eventPublishTimeout)
+		err := s.publisher.Publish(publishCtx, event)
+		cancel()
+		if err != nil {
+			blocked[aggregate] = true
+			logging.FromContext(ctx, s.logger).Warnf("failed to publish event %s (attempt %d): %v", event.PublicID, event.Attempts+1, err)
+			if err := s.eventRepo.MarkEventFailed(ctx, event.PublicID, err.Error(), time.Now().Add(eventRetryDelay(backoff, event.Attempts))); err != nil {
+				logging.FromContext(ctx, s.logger).Errorf("failed to record failed publish of event %s: %v", event.PublicID, err)
+			}
+			continue
+		}
+		published++
+		if err := s.eventRepo.MarkEventPublished(ctx, event.PublicID); err != nil {
+			logging.FromContext(ctx, s.logger).Errorf("failed to mark event %s as published: %v", event.PublicID, err)
+		}
+	}
+	return published, nil
+}
+
+func (s *eventService) ClosePublisher() error {
+	if s.publisher == nil {
+		return nil
+	}
+	return s.publisher.Close()
+}
+
+// eventRetryDelay doubles the backoff with every attempt. Events are never given up on,
+// so the delay is capped instead.
+func eventRetryDelay(backoff time.Duration, attempts int) time.Duration {
+	delay := backoff
+	for i := 0; i < attempts && delay < maxEventRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxEventRetryBackoff {
+		delay = maxEventRetryBackoff
+	}
+	return delay
+}
+
+func newDomainEvent(eventType, aggregateType, aggregatePublicID string, payload map[string]interface{}) *models.DomainEvent {
+	return &models.DomainEvent{
+		Type:              eventType,
+		AggregateType:     aggregateType,
+		AggregatePublicID: aggregatePublicID,
+		Payload:           payload,
+	}
+}
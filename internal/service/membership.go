@@ -0,0 +1,407 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/metrics"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+const defaultInvitationTTL = 7 * 24 * time.Hour
+
+type membershipService struct {
+	cfg            *config.Configs
+	logger         *zap.SugaredLogger
+	membershipRepo repository.MembershipRepository
+	recruiterRepo  repository.RecruiterRepository
+	companyRepo    repository.CompanyRepository
+	uow            repository.UnitOfWork
+}
+
+func NewMembershipService(repo *repository.Repository, cfg *config.Configs, logger *zap.SugaredLogger) *membershipService {
+	return &membershipService{
+		membershipRepo: repo.MembershipRepository,
+		recruiterRepo:  repo.RecruiterRepository,
+		companyRepo:    repo.CompanyRepository,
+		uow:            repo.UnitOfWork,
+		cfg:            cfg,
+		logger:         logger,
+	}
+}
+
+// GetMembers returns the members of the company, to any of them
+func (s *membershipService) GetMembers(ctx context.Context, recruiterID, companyID string) ([]*models.CompanyMember, error) {
+	if _, err := s.member(ctx, recruiterID, companyID); err != nil {
+		return nil, err
+	}
+	return s.membershipRepo.GetMembers(ctx, companyID)
+}
+
+// UpdateMemberRole changes the role of a member of the company. Admins manage the members with a
+// role up to their own, so only owners grant or take the owner role.
+func (s *membershipService) UpdateMemberRole(ctx context.Context, recruiterID, companyID, memberID, role string) (*models.Membership, error) {
+	if err := validateRole(role); err != nil {
+		return nil, err
+	}
+	var events []*models.DomainEvent
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		events = nil
+		actor, err := s.admin(ctx, recruiterID, companyID)
+		if err != nil {
+			return err
+		}
+		current, err := s.companyMember(ctx, companyID, memberID)
+		if err != nil {
+			return err
+		}
+		if !outranks(actor, current.Role) || !outranks(actor, role) {
+			return models.ErrPermissionDenied
+		}
+		if current.Role == role {
+			return nil
+		}
+		events = membershipChanged(memberID, current, companyID, role, recruiterID)
+		return s.membershipRepo.SetMembership(ctx, memberID, companyID, role, events...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	countMembershipChanges(events)
+	return &models.Membership{CompanyPublicID: companyID, Role: role}, nil
+}
+
+// RemoveMember removes a member from the company. Members may leave the company themselves,
+// other members are removed by the admins.
+func (s *membershipService) RemoveMember(ctx context.Context, recruiterID, companyID, memberID string) error {
+	var events []*models.DomainEvent
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		events = nil
+		actor, err := s.member(ctx, recruiterID, companyID)
+		if err != nil {
+			return err
+		}
+		current := actor
+		if memberID != recruiterID {
+			if !actor.IsAdmin() {
+				return models.ErrPermissionDenied
+			}
+			if current, err = s.companyMember(ctx, companyID, memberID); err != nil {
+				return err
+			}
+			if !outranks(actor, current.Role) {
+				return models.ErrPermissionDenied
+			}
+		}
+		events = membershipChanged(memberID, current, "", models.RoleRecruiter, recruiterID)
+		return s.membershipRepo.SetMembership(ctx, memberID, "", models.RoleRecruiter, events...)
+	})
+	if err != nil {
+		return err
+	}
+	countMembershipChanges(events)
+	return nil
+}
+
+// CreateInvitation invites the email address to join the company with the role, which defaults
+// to recruiter. The invitation is sent by email with a token that is only valid until the invitation
+// expires, and is replaced by a new invitation of the same address.
+func (s *membershipService) CreateInvitation(ctx context.Context, recruiterID, companyID string, invitation *models.Invitation) (*models.Invitation, error) {
+	if invitation.Role == "" {
+		invitation.Role = models.RoleRecruiter
+	}
+	if err := validateRole(invitation.Role); err != nil {
+		return nil, err
+	}
+	invitation.Email = strings.ToLower(strings.TrimSpace(invitation.Email))
+
+	actor, err := s.admin(ctx, recruiterID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if !outranks(actor, invitation.Role) {
+		return nil, models.ErrPermissionDenied
+	}
+	company, err := s.companyRepo.GetCompany(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newInvitationToken()
+	if err != nil {
+		return nil, err
+	}
+	ttl, acceptURL := defaultInvitationTTL, ""
+	if cfg := s.cfg.Invitations; cfg != nil {
+		if cfg.TTL > 0 {
+			ttl = cfg.TTL
+		}
+		acceptURL = cfg.AcceptURL
+	}
+	if acceptURL != "" {
+		acceptURL += "?token=" + url.QueryEscape(token)
+	}
+	invitation.CompanyPublicID = companyID
+	invitation.InvitedBy = recruiterID
+	invitation.ExpiresAt = time.Now().Add(ttl)
+
+	res, err := s.membershipRepo.CreateInvitation(ctx, invitation, hashToken(token), map[string]interface{}{
+		"company_public_id": companyID,
+		"company_name":      company.Name,
+		"role":              invitation.Role,
+		"token":             token,
+		"accept_url":        acceptURL,
+		"expires_at":        invitation.ExpiresAt.UTC().Format(time.RFC1123),
+	})
+	if err != nil {
+		return nil, err
+	}
+	metrics.InvitationsSent.Inc()
+	return res, nil
+}
+
+func (s *membershipService) GetInvitations(ctx context.Context, recruiterID, companyID string, args *models.SearchArgs) ([]*models.Invitation, int, error) {
+	if _, err := s.admin(ctx, recruiterID, companyID); err != nil {
+		return nil, 0, err
+	}
+	return s.membershipRepo.GetInvitations(ctx, companyID, args)
+}
+
+func (s *membershipService) RevokeInvitation(ctx context.Context, recruiterID, companyID, invitationID string) error {
+	if _, err := s.admin(ctx, recruiterID, companyID); err != nil {
+		return err
+	}
+	return s.membershipRepo.RevokeInvitation(ctx, companyID, invitationID)
+}
+
+// AcceptInvitation moves the recruiter to the company it was invited to, with the role of the
+// invitation. A recruiter leaving another company must not be its last owner.
+func (s *membershipService) AcceptInvitation(ctx context.Context, recruiterID, token string) (*models.Membership, error) {
+	var (
+		res    *models.Membership
+		events []*models.DomainEvent
+	)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		events = nil
+		invitation, err := s.membershipRepo.AcceptInvitation(ctx, hashToken(token), recruiterID)
+		if err != nil {
+			return err
+		}
+		current, err := s.recruiterRepo.GetMembership(ctx, recruiterID)
+		if err != nil {
+			return err
+		}
+		res = &models.Membership{CompanyPublicID: invitation.CompanyPublicID, Role: invitation.Role}
+		if current.CompanyPublicID == invitation.CompanyPublicID {
+			// the invitation was accepted already by a retried request
+			if current.Role == invitation.Role {
+				return nil
+			}
+			return models.ErrAlreadyMember
+		}
+		events = membershipChanged(recruiterID, current, invitation.CompanyPublicID, invitation.Role, recruiterID)
+		return s.membershipRepo.SetMembership(ctx, recruiterID, invitation.CompanyPublicID, invitation.Role, events...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	countMembershipChanges(events)
+	return res, nil
+}
+
+// CreateJoinRequest asks the admins of the company to let the recruiter join it. A recruiter
+// asking again while its request is pending gets the pending request.
+func (s *membershipService) CreateJoinRequest(ctx context.Context, recruiterID, companyID, message string) (*models.JoinRequest, error) {
+	exists, err := s.companyRepo.Exists(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, models.ErrCompanyNotFound
+	}
+	current, err := s.recruiterRepo.GetMembership(ctx, recruiterID)
+	if err != nil {
+		return nil, err
+	}
+	if current.CompanyPublicID == companyID {
+		return nil, models.ErrAlreadyMember
+	}
+	return s.membershipRepo.CreateJoinRequest(ctx, &models.JoinRequest{
+		CompanyPublicID:   companyID,
+		RecruiterPublicID: recruiterID,
+		Message:           strings.TrimSpace(message),
+	})
+}
+
+func (s *membershipService) GetJoinRequests(ctx context.Context, recruiterID, companyID, status string, args *models.SearchArgs) ([]*models.JoinRequest, int, error) {
+	switch status {
+	case "", models.JoinRequestPending, models.JoinRequestApproved, models.JoinRequestRejected:
+	default:
+		return nil, 0, models.ErrInvalidInput.WithField("status", "must be one of pending, approved, rejected")
+	}
+	if _, err := s.admin(ctx, recruiterID, companyID); err != nil {
+		return nil, 0, err
+	}
+	return s.membershipRepo.GetJoinRequests(ctx, companyID, status, args)
+}
+
+// ApproveJoinRequest moves the recruiter that made the request to the company, with the role,
+// which defaults to recruiter.
+func (s *membershipService) ApproveJoinRequest(ctx context.Context, recruiterID, companyID, joinRequestID, role string) (*models.JoinRequest, error) {
+	if role == "" {
+		role = models.RoleRecruiter
+	}
+	if err := validateRole(role); err != nil {
+		return nil, err
+	}
+	var (
+		res    *models.JoinRequest
+		events []*models.DomainEvent
+	)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		events = nil
+		actor, err := s.admin(ctx, recruiterID, companyID)
+		if err != nil {
+			return err
+		}
+		if !outranks(actor, role) {
+			return models.ErrPermissionDenied
+		}
+		res, err = s.membershipRepo.DecideJoinRequest(ctx, companyID, joinRequestID, models.JoinRequestApproved, recruiterID)
+		if err != nil {
+			return err
+		}
+		current, err := s.recruiterRepo.GetMembership(ctx, res.RecruiterPublicID)
+		if err != nil {
+			return err
+		}
+		// the recruiter may have joined with an invitation in the meantime
+		if current.CompanyPublicID == companyID {
+			return nil
+		}
+		events = membershipChanged(res.RecruiterPublicID, current, companyID, role, recruiterID)
+		return s.membershipRepo.SetMembership(ctx, res.RecruiterPublicID, companyID, role, events...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	countMembershipChanges(events)
+	return res, nil
+}
+
+func (s *membershipService) RejectJoinRequest(ctx context.Context, recruiterID, companyID, joinRequestID string) (*models.JoinRequest, error) {
+	if _, err := s.admin(ctx, recruiterID, companyID); err != nil {
+		return nil, err
+	}
+	return s.membershipRepo.DecideJoinRequest(ctx, companyID, joinRequestID, models.JoinRequestRejected, recruiterID)
+}
+
+// member returns the membership of the recruiter, which must belong to the company
+func (s *membershipService) member(ctx context.Context, recruiterID, companyID string) (*models.Membership, error) {
+	membership, err := s.recruiterRepo.GetMembership(ctx, recruiterID)
+	if err != nil {
+		return nil, err
+	}
+	if membership.CompanyPublicID != companyID {
+		return nil, models.ErrPermissionDenied
+	}
+	return membership, nil
+}
+
+// admin returns the membership of the recruiter, which must be an admin or owner of the company
+func (s *membershipService) admin(ctx context.Context, recruiterID, companyID string) (*models.Membership, error) {
+	membership, err := s.member(ctx, recruiterID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if !membership.IsAdmin() {
+		return nil, models.ErrPermissionDenied
+	}
+	return membership, nil
+}
+
+// companyMember returns the membership of a member of the company. Recruiters of other companies
+// are reported as not found.
+func (s *membershipService) companyMember(ctx context.Context, companyID, memberID string) (*models.Membership, error) {
+	membership, err := s.recruiterRepo.GetMembership(ctx, memberID)
+	if err != nil && !errors.Is(err, models.ErrUserNotFound) {
+		return nil, err
+	}
+	if err != nil || membership.CompanyPublicID != companyID {
+		return nil, models.ErrUserNotFound
+	}
+	return membership, nil
+}
+
+// outranks tells whether the member may grant the role, or manage a member with it
+func outranks(member *models.Membership, role string) bool {
+	return models.RoleRank(role) <= models.RoleRank(member.Role)
+}
+
+func validateRole(role string) error {
+	if models.RoleRank(role) == 0 {
+		return models.ErrInvalidInput.WithField("role", "must be one of "+strings.Join(models.CompanyRoles, ", "))
+	}
+	return nil
+}
+
+// membershipChanged returns the events of the recruiter moving from its membership to the company
+// with the role, on the action of actorID. An empty company removes the recruiter from its own.
+func membershipChanged(recruiterID string, from *models.Membership, companyID, role, actorID string) []*models.DomainEvent {
+	payload := func(companyID, role string) map[string]interface{} {
+		return map[string]interface{}{
+			"company_public_id":   companyID,
+			"recruiter_public_id": recruiterID,
+			"role":                role,
+			"actor_public_id":     actorID,
+		}
+	}
+	var events []*models.DomainEvent
+	if from.CompanyPublicID == companyID {
+		if companyID != "" && from.Role != role {
+			changed := payload(companyID, role)
+			changed["previous_role"] = from.Role
+			events = append(events, newDomainEvent(models.EventCompanyRoleChanged, models.AggregateCompany, companyID, changed))
+		}
+		return events
+	}
+	if from.CompanyPublicID != "" {
+		events = append(events, newDomainEvent(models.EventCompanyMemberLeft, models.AggregateCompany, from.CompanyPublicID, payload(from.CompanyPublicID, from.Role)))
+	}
+	if companyID != "" {
+		events = append(events, newDomainEvent(models.EventCompanyMemberJoined, models.AggregateCompany, companyID, payload(companyID, role)))
+	}
+	return events
+}
+
+// countMembershipChanges counts the changes of committed membership events
+func countMembershipChanges(events []*models.DomainEvent) {
+	for _, event := range events {
+		metrics.MembershipChanges.WithLabelValues(strings.TrimPrefix(event.Type, "company.")).Inc()
+	}
+}
+
+// newInvitationToken returns a random token, sent by email and only stored hashed
+func newInvitationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the hash a token sent by email is stored with
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
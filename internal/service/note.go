@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+// mentionPattern matches @mentions of recruiters by their public ID, e.g. "@6f1c...-...".
+var mentionPattern = regexp.MustCompile(`@([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})`)
+
+type noteService struct {
+	cfg           *config.Configs
+	logger        *zap.SugaredLogger
+	noteRepo      repository.NoteRepository
+	recruiterRepo repository.RecruiterRepository
+	uow           repository.UnitOfWork
+}
+
+func NewNoteService(repo *repository.Repository, cfg *config.Configs, logger *zap.SugaredLogger) *noteService {
+	return &noteService{
+		noteRepo:      repo.NoteRepository,
+		recruiterRepo: repo.RecruiterRepository,
+		uow:           repo.UnitOfWork,
+		cfg:           cfg,
+		logger:        logger,
+	}
+}
+
+func (s *noteService) CreateNote(ctx context.Context, recruiterID string, note *models.Note) (*models.Note, error) {
+	if err := validateNote(note); err != nil {
+		return nil, err
+	}
+	if (note.Body == nil || strings.TrimSpace(*note.Body) == "") && note.Rating == nil {
+		return nil, models.ErrInvalidInput.WithField("body", "body or rating is required")
+	}
+	if note.Body == nil {
+		empty := ""
+		note.Body = &empty
+	}
+	exists, err := s.noteRepo.SubjectExists(ctx, note.SubjectType, note.SubjectPublicID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if note.SubjectType == models.NoteSubjectInterview {
+			return nil, models.ErrInterviewNotFound
+		}
+		return nil, models.ErrUserNotFound
+	}
+
+	companyID, err := s.recruiterRepo.GetCompanyPublicID(ctx, recruiterID)
+	if err != nil {
+		return nil, err
+	}
+	note.AuthorPublicID = recruiterID
+	note.CompanyPublicID = companyID
+	note.Mentions, err = s.mentions(ctx, companyID, *note.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	publicID, err := s.noteRepo.CreateNote(ctx, note)
+	if err != nil {
+		return nil, err
+	}
+	return s.noteRepo.GetNote(ctx, publicID)
+}
+
+// GetNote returns the note if it was written by a recruiter of the same company.
+// Notes of other companies are reported as not found.
+func (s *noteService) GetNote(ctx context.Context, recruiterID, noteID string) (*models.Note, error) {
+	note, err := s.noteRepo.GetNote(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+	companyID, err := s.recruiterRepo.GetCompanyPublicID(ctx, recruiterID)
+	if err != nil {
+		return nil, err
+	}
+	if note.CompanyPublicID != companyID {
+		return nil, models.ErrNoteNotFound
+	}
+	return note, nil
+}
+
+func (s *noteService) GetNotes(ctx context.Context, recruiterID, subjectType, subjectID string, args *models.SearchArgs) ([]*models.Note, int, error) {
+	companyID, err := s.recruiterRepo.GetCompanyPublicID(ctx, recruiterID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.noteRepo.GetNotes(ctx, companyID, subjectType, subjectID, args)
+}
+
+func (s *noteService) GetMentions(ctx context.Context, recruiterID string, args *models.SearchArgs) ([]*models.Note, int, error) {
+	companyID, err := s.recruiterRepo.GetCompanyPublicID(ctx, recruiterID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.noteRepo.GetMentionedNotes(ctx, recruiterID, companyID, args)
+}
+
+// UpdateNote edits the note and records a new revision. Only the author may edit a note.
+// The note is checked and edited in one unit of work, so concurrent edits cannot skip a revision.
+func (s *noteService) UpdateNote(ctx context.Context, recruiterID string, note *models.Note) (*models.Note, error) {
+	if err := validateNote(note); err != nil {
+		return nil, err
+	}
+	var res *models.Note
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		current, err := s.GetNote(ctx, recruiterID, note.PublicID)
+		if err != nil {
+			return err
+		}
+		if current.AuthorPublicID != recruiterID {
+			return models.ErrPermissionDenied
+		}
+		if note.Body != nil {
+			note.Mentions, err = s.mentions(ctx, current.CompanyPublicID, *note.Body)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := s.noteRepo.UpdateNote(ctx, note, recruiterID); err != nil {
+			return err
+		}
+		res, err = s.noteRepo.GetNote(ctx, note.PublicID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteNote deletes the note. Only the author may delete a note.
+func (s *noteService) DeleteNote(ctx context.Context, recruiterID, noteID string) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		current, err := s.GetNote(ctx, recruiterID, noteID)
+		if err != nil {
+			return err
+		}
+		if current.AuthorPublicID != recruiterID {
+			return models.ErrPermissionDenied
+		}
+		return s.noteRepo.DeleteNote(ctx, noteID)
+	})
+}
+
+func (s *noteService) GetNoteHistory(ctx context.Context, recruiterID, noteID string) ([]*models.NoteRevision, error) {
+	if _, err := s.GetNote(ctx, recruiterID, noteID); err != nil {
+		return nil, err
+	}
+	return s.noteRepo.GetNoteRevisions(ctx, noteID)
+}
+
+// mentions extracts the recruiters @mentioned in the body.
+// Mentions of recruiters outside the company are dropped.
+func (s *noteService) mentions(ctx context.Context, companyID, body string) ([]string, error) {
+	matches := mentionPattern.FindAllStringSubmatch(body, -1)
+	if len(matches) == 0 {
+		return []string{}, nil
+	}
+	seen := make(map[string]bool, len(matches))
+	publicIDs := make([]string, 0, len(matches))
+	for _, match := range matches {
+		publicID := strings.ToLower(match[1])
+		if !seen[publicID] {
+			seen[publicID] = true
+			publicIDs = append(publicIDs, publicID)
+		}
+	}
+	return s.recruiterRepo.FilterByCompany(ctx, companyID, publicIDs)
+}
+
+func validateNote(note *models.Note) error {
+	if note.Rating != nil && (*note.Rating < models.MinNoteRating || *note.Rating > models.MaxNoteRating) {
+		return models.ErrInvalidInput.WithField("rating", fmt.Sprintf("must be between %d and %d", models.MinNoteRating, models.MaxNoteRating))
+	}
+	return nil
+}
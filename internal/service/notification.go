@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/notification"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultNotificationBatchSize   = 50
+	defaultNotificationMaxAttempts = 5
+	defaultNotificationBackoff     = time.Minute
+	// notificationLease is how long a claimed notification is hidden from other workers.
+	notificationLease = 5 * time.Minute
+	// maxRetryDelay caps the backoff of failed deliveries, which doubles on every attempt.
+	maxRetryDelay = 24 * time.Hour
+)
+
+type notificationService struct {
+	cfg              *config.Configs
+	logger           *zap.SugaredLogger
+	notificationRepo repository.NotificationRepository
+	mailer           notification.Mailer
+	templates        *notification.Templates
+}
+
+func NewNotificationService(repo *repository.Repository, cfg *config.Configs, logger *zap.SugaredLogger) *notificationService {
+	s := &notificationService{
+		notificationRepo: repo.NotificationRepository,
+		templates:        notification.NewTemplates(),
+		cfg:              cfg,
+		logger:           logger,
+	}
+	if cfg.SMTP != nil {
+		s.mailer = notification.NewSMTPMailer(cfg.SMTP)
+	}
+	return s
+}
+
+func (s *notificationService) GetNotifications(ctx context.Context, userID string, args *models.SearchArgs) ([]*models.Notification, int, error) {
+	return s.notificationRepo.GetNotifications(ctx, userID, args)
+}
+
+// GetNotificationPreferences returns the preference of the user for every event type.
+// Email notifications are enabled for event types the user has not configured.
+func (s *notificationService) GetNotificationPreferences(ctx context.Context, userID string) ([]*models.NotificationPreference, error) {
+	stored, err := s.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	enabled := make(map[string]bool, len(stored))
+	for _, preference := range stored {
+		enabled[preference.EventType] = preference.EmailEnabled
+	}
+	res := make([]*models.NotificationPreference, 0, len(models.NotificationEventTypes))
+	for _, eventType := range models.NotificationEventTypes {
+		emailEnabled, ok := enabled[eventType]
+		res = append(res, &models.NotificationPreference{
+			EventType:    eventType,
+			EmailEnabled: !ok || emailEnabled,
+		})
+	}
+	return res, nil
+}
+
+func (s *notificationService) UpdateNotificationPreferences(ctx context.Context, userID string, preferences []*models.NotificationPreference) error {
+	for _, preference := range preferences {
+		if preference == nil || !isNotificationEventType(preference.EventType) {
+			return models.ErrInvalidInput.WithField("preferences.event_type", "is not a known event type")
+		}
+	}
+	return s.notificationRepo.SetPreferences(ctx, userID, preferences)
+}
+
+// DeliverNotifications sends the notifications that are due and returns how many were sent.
+// Failed deliveries are retried with exponential backoff until the maximum number of attempts is reached.
+func (s *notificationService) DeliverNotifications(ctx context.Context) (int, error) {
+	batchSize, maxAttempts, backoff := defaultNotificationBatchSize, defaultNotificationMaxAttempts, defaultNotificationBackoff
+	if cfg := s.cfg.Notifications; cfg != nil {
+		if cfg.BatchSize > 0 {
+			batchSize = cfg.BatchSize
+		}
+		if cfg.MaxAttempts > 0 {
+			maxAttempts = cfg.MaxAttempts
+		}
+		if cfg.RetryBackoff > 0 {
+			backoff = cfg.RetryBackoff
+		}
+	}
+
+	pending, err := s.notificationRepo.ClaimPendingNotifications(ctx, batchSize, notificationLease)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, n := range pending {
+		recipient, status, deliveryErr := s.deliver(ctx, n)
+		var nextAttemptAt *time.Time
+		if status == models.NotificationStatusFailed && n.Attempts+1 < maxAttempts {
+			next := time.Now().Add(retryDelay(backoff, n.Attempts))
+			nextAttemptAt = &next
+		}
+		if status == models.NotificationStatusFailed {
+			logging.FromContext(ctx, s.logger).Warnf("failed to deliver notification %s (attempt %d): %s", n.PublicID, n.Attempts+1, deliveryErr)
+		}
+		if status == models.NotificationStatusSent {
+			sent++
+		}
+		if err := s.notificationRepo.RecordDelivery(ctx, n.PublicID, status, recipient, deliveryErr, nextAttemptAt); err != nil {
+			logging.FromContext(ctx, s.logger).Errorf("failed to record delivery of notification %s: %v", n.PublicID, err)
+		}
+	}
+	return sent, nil
+}
+
+// deliver sends the notification by email and returns the recipient address, the delivery status and the error message.
+func (s *notificationService) deliver(ctx context.Context, n *models.Notification) (string, string, string) {
+	recipient, err := s.recipient(ctx, n)
+	if err != nil {
+		return "", models.NotificationStatusFailed, err.Error()
+	}
+	if !recipient.EmailEnabled {
+		return recipient.Email, models.NotificationStatusSkipped, "disabled by user preference"
+	}
+	if recipient.Email == "" {
+		return "", models.NotificationStatusSkipped, "user has no email address"
+	}
+	if s.mailer == nil {
+		return recipient.Email, models.NotificationStatusFailed, "smtp is not configured"
+	}
+
+	subject, body, err := s.templates.Render(n.EventType, &notification.TemplateData{
+		FirstName: recipient.FirstName,
+		LastName:  recipient.LastName,
+		Payload:   n.Payload,
+	})
+	if err != nil {
+		return recipient.Email, models.NotificationStatusFailed, err.Error()
+	}
+	if err := s.mailer.Send(recipient.Email, subject, body); err != nil {
+		return recipient.Email, models.NotificationStatusFailed, err.Error()
+	}
+	return recipient.Email, models.NotificationStatusSent, ""
+}
+
+// recipient returns the user the notification is sent to, or the email address of a notification sent to no user
+func (s *notificationService) recipient(ctx context.Context, n *models.Notification) (*models.NotificationRecipient, error) {
+	if n.UserPublicID == "" {
+		return &models.NotificationRecipient{Email: n.RecipientEmail, EmailEnabled: true}, nil
+	}
+	return s.notificationRepo.GetRecipient(ctx, n.UserPublicID, n.EventType)
+}
+
+func isNotificationEventType(eventType string) bool {
+	for _, t := range models.NotificationEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// retryDelay returns the backoff doubled for every attempt made, up to maxRetryDelay. The shift is
+// bounded too, so a large number of attempts cannot overflow the duration.
+func retryDelay(backoff time.Duration, attempts int) time.Duration {
+	if attempts > 16 {
+		attempts = 16
+	}
+	if backoff > maxRetryDelay>>attempts {
+		return maxRetryDelay
+	}
+	return backoff << attempts
+}
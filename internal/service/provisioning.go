@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/domains"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/metrics"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/repository"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type provisioningService struct {
+	cfg              *config.Configs
+	logger           *zap.SugaredLogger
+	provisioningRepo repository.ProvisioningRepository
+	candidateRepo    repository.CandidateRepository
+	membershipRepo   repository.MembershipRepository
+	domainRepo       repository.DomainRepository
+	uow              repository.UnitOfWork
+}
+
+func NewProvisioningService(repo *repository.Repository, cfg *config.Configs, logger *zap.SugaredLogger) *provisioningService {
+	return &provisioningService{
+		provisioningRepo: repo.ProvisioningRepository,
+		candidateRepo:    repo.CandidateRepository,
+		membershipRepo:   repo.MembershipRepository,
+		domainRepo:       repo.DomainRepository,
+		uow:              repo.UnitOfWork,
+		cfg:              cfg,
+		logger:           logger,
+	}
+}
+
+// ProvisionCandidate creates the user with its candidate profile and skills at once. A public ID
+// is generated when none is given.
+func (s *provisioningService) ProvisionCandidate(ctx context.Context, candidate *models.CandidateProvision) (*models.ProvisionedUser, error) {
+	if candidate.PublicID == "" {
+		candidate.PublicID = uuid.NewString()
+	}
+
+	var (
+		res    *models.ProvisionedUser
+		skills *models.SkillsChange
+	)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		skills = nil
+		res, err = s.provisioningRepo.CreateCandidate(ctx, candidate,
+			newDomainEvent(models.EventCandidateCreated, models.AggregateCandidate, candidate.PublicID, map[string]interface{}{
+				"candidate_public_id": candidate.PublicID,
+				"first_name":          candidate.FirstName,
+				"last_name":           candidate.LastName,
+			}),
+		)
+		// the skills of a retried sign up were added with the profile
+		if err != nil || !res.Created || len(candidate.Skills) == 0 {
+			return err
+		}
+		skills, err = s.candidateRepo.AddSkillsToCandidate(ctx, candidate.PublicID, candidate.Skills, skillsChanged(candidate.PublicID))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.Created {
+		metrics.UsersProvisioned.WithLabelValues("candidate").Inc()
+	}
+	if skills != nil {
+		countSkillsChange(skills)
+	}
+	return res, nil
+}
+
+// ProvisionRecruiter creates the user with its recruiter profile in the company it was invited to,
+// or else in the company with the public ID, or else in the company with the name, which is created
+// along with the recruiter if there is none, or else in the company that verified the domain of its
+// verified email. The recruiter owns the company it creates, or one without an owner. A recruiter
+// signing up to a company with an owner is created without a company, with a request to join it,
+// unless the auth service verified its email and the email is on a domain the company verified.
+// A public ID is generated when none is given.
+func (s *provisioningService) ProvisionRecruiter(ctx context.Context, recruiter *models.RecruiterProvision) (*models.ProvisionedUser, error) {
+	recruiter.CompanyName = strings.TrimSpace(recruiter.CompanyName)
+	// anyone can sign up with any address, only one the auth service verified proves the domain
+	emailDomain := ""
+	if recruiter.EmailVerified {
+		emailDomain = domains.EmailDomain(recruiter.Email)
+	}
+	if recruiter.InvitationToken == "" && recruiter.CompanyPublicID == "" && recruiter.CompanyName == "" && emailDomain == "" {
+		return nil, models.ErrInvalidInput.WithField("company_public_id", "is required unless company_name, invitation_token or a verified email on a verified domain is given")
+	}
+	if recruiter.PublicID == "" {
+		recruiter.PublicID = uuid.NewString()
+	}
+
+	var (
+		res        *models.ProvisionedUser
+		newCompany bool
+	)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		provision := *recruiter
+		newCompany = false
+		role := models.RoleRecruiter
+		joinCompanyID := ""
+		verifiedCompanyID := ""
+		if provision.InvitationToken == "" && emailDomain != "" {
+			var err error
+			verifiedCompanyID, err = s.domainRepo.GetVerifiedCompany(ctx, emailDomain)
+			if err != nil {
+				return err
+			}
+		}
+		switch {
+		case provision.InvitationToken != "":
+			invitation, err := s.membershipRepo.AcceptInvitation(ctx, hashToken(provision.InvitationToken), provision.PublicID)
+			if err != nil {
+				return err
+			}
+			provision.CompanyPublicID = invitation.CompanyPublicID
+			role = invitation.Role
+		case provision.CompanyPublicID == "" && provision.CompanyName == "":
+			if verifiedCompanyID == "" {
+				return models.ErrInvalidInput.WithField("company_public_id", "is required unless company_name, invitation_token or a verified email on a verified domain is given")
+			}
+			provision.CompanyPublicID = verifiedCompanyID
+		case provision.CompanyPublicID == "":
+			company, created, err := s.findOrCreateCompany(ctx, &models.Company{Name: provision.CompanyName})
+			if err != nil {
+				return err
+			}
+			provision.CompanyPublicID = company.PublicID
+			newCompany = created
+		}
+		if provision.InvitationToken == "" {
+			// a company created by another service, or left without members, has no owner yet
+			owned := false
+			if !newCompany {
+				var err error
+				owned, err = s.membershipRepo.HasOwner(ctx, provision.CompanyPublicID)
+				if err != nil {
+					return err
+				}
+			}
+			switch {
+			case !owned:
+				role = models.RoleOwner
+			case provision.CompanyPublicID == verifiedCompanyID:
+				// the verified email is on a domain the company verified, the recruiter joins it right away
+			default:
+				joinCompanyID, provision.CompanyPublicID = provision.CompanyPublicID, ""
+			}
+		}
+
+		var err error
+		res, err = s.provisioningRepo.CreateRecruiter(ctx, &provision, role,
+			newDomainEvent(models.EventRecruiterCreated, models.AggregateRecruiter, provision.PublicID, map[string]interface{}{
+				"recruiter_public_id": provision.PublicID,
+				"company_public_id":   provision.CompanyPublicID,
+				"role":                role,
+				"first_name":          provision.FirstName,
+				"last_name":           provision.LastName,
+			}),
+		)
+		// the join request of a retried sign up was made with the profile
+		if err != nil || !res.Created || joinCompanyID == "" {
+			return err
+		}
+		joinRequest, err := s.membershipRepo.CreateJoinRequest(ctx, &models.JoinRequest{
+			CompanyPublicID:   joinCompanyID,
+			RecruiterPublicID: provision.PublicID,
+		})
+		if err != nil {
+			return err
+		}
+		res.JoinRequestPublicID = joinRequest.PublicID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if newCompany {
+		metrics.CompaniesCreated.Inc()
+	}
+	if res.Created {
+		metrics.UsersProvisioned.WithLabelValues("recruiter").Inc()
+	}
+	return res, nil
+}
+
+// FindOrCreateCompany returns the company with the name, regardless of case, and creates it if
+// there is none. created tells which one happened.
+func (s *provisioningService) FindOrCreateCompany(ctx context.Context, company *models.Company) (*models.Company, bool, error) {
+	company.Name = strings.TrimSpace(company.Name)
+	res, created, err := s.findOrCreateCompany(ctx, company)
+	if err != nil {
+		return nil, false, err
+	}
+	if created {
+		metrics.CompaniesCreated.Inc()
+	}
+	return res, created, nil
+}
+
+func (s *provisioningService) findOrCreateCompany(ctx context.Context, company *models.Company) (*models.Company, bool, error) {
+	return s.provisioningRepo.FindOrCreateCompany(ctx, company, companyCreated(company))
+}
@@ -1,9 +1,12 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/metrics"
 	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
 	"github.com/Zhiyenbek/sp-users-main-service/internal/repository"
 	"go.uber.org/zap"
@@ -22,8 +25,8 @@ func NewRecruitersService(repo *repository.Repository, cfg *config.Configs, logg
 		logger:        logger,
 	}
 }
-func (r *recruiterService) Exists(publicID string) error {
-	exists, err := r.recruiterRepo.Exists(publicID)
+func (r *recruiterService) Exists(ctx context.Context, publicID string) error {
+	exists, err := r.recruiterRepo.Exists(ctx, publicID)
 	if err != nil {
 		return err
 	}
@@ -33,12 +36,12 @@ func (r *recruiterService) Exists(publicID string) error {
 	return nil
 }
 
-func (r *recruiterService) GetRecruiter(publicID string) (*models.Recruiter, error) {
-	return r.recruiterRepo.GetRecruiter(publicID)
+func (r *recruiterService) GetRecruiter(ctx context.Context, publicID string) (*models.Recruiter, error) {
+	return r.recruiterRepo.GetRecruiter(ctx, publicID)
 }
 
-func (s *recruiterService) GetInterviewsByPublicID(publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error) {
-	res, count, err := s.recruiterRepo.GetInterviewsByPublicID(publicID, searchArgs)
+func (s *recruiterService) GetInterviewsByPublicID(ctx context.Context, publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error) {
+	res, count, err := s.recruiterRepo.GetInterviewsByPublicID(ctx, publicID, searchArgs)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -47,12 +50,13 @@ func (s *recruiterService) GetInterviewsByPublicID(publicID string, searchArgs *
 			result := models.Result{}
 			err = json.Unmarshal(r.RawResult, &result)
 			if err != nil {
-				s.logger.Error(err)
+				logging.FromContext(ctx, s.logger).Error(err)
 				return nil, 0, err
 			}
 			r.Result = result
 		}
 	}
+	metrics.InterviewsServed.WithLabelValues("recruiter").Add(float64(len(res)))
 	return res, count, nil
 
 }
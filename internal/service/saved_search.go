@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+type savedSearchService struct {
+	cfg             *config.Configs
+	logger          *zap.SugaredLogger
+	savedSearchRepo repository.SavedSearchRepository
+	candidateRepo   repository.CandidateRepository
+}
+
+func NewSavedSearchService(repo *repository.Repository, cfg *config.Configs, logger *zap.SugaredLogger) *savedSearchService {
+	return &savedSearchService{
+		savedSearchRepo: repo.SavedSearchRepository,
+		candidateRepo:   repo.CandidateRepository,
+		cfg:             cfg,
+		logger:          logger,
+	}
+}
+
+func (s *savedSearchService) CreateSavedSearch(ctx context.Context, recruiterID string, savedSearch *models.SavedSearch) (*models.SavedSearch, error) {
+	savedSearch.Name = strings.TrimSpace(savedSearch.Name)
+	if savedSearch.Name == "" {
+		return nil, models.ErrInvalidInput.WithField("name", "is required")
+	}
+	if savedSearch.Params.PageNum < 1 {
+		savedSearch.Params.PageNum = models.DefaultPageNum
+	}
+	if savedSearch.Params.PageSize < 1 {
+		savedSearch.Params.PageSize = models.DefaultPageSize
+	}
+	savedSearch.Params.Filters = normalizeFilters(savedSearch.Params.Filters)
+	savedSearch.RecruiterPublicID = recruiterID
+
+	publicID, err := s.savedSearchRepo.CreateSavedSearch(ctx, savedSearch)
+	if err != nil {
+		return nil, err
+	}
+	return s.savedSearchRepo.GetSavedSearch(ctx, publicID)
+}
+
+// GetSavedSearch returns the saved search if it belongs to the recruiter.
+// Saved searches of other recruiters are reported as not found.
+func (s *savedSearchService) GetSavedSearch(ctx context.Context, recruiterID, savedSearchID string) (*models.SavedSearch, error) {
+	savedSearch, err := s.savedSearchRepo.GetSavedSearch(ctx, savedSearchID)
+	if err != nil {
+		return nil, err
+	}
+	if savedSearch.RecruiterPublicID != recruiterID {
+		return nil, models.ErrSavedSearchNotFound
+	}
+	return savedSearch, nil
+}
+
+func (s *savedSearchService) GetSavedSearches(ctx context.Context, recruiterID string, args *models.SearchArgs) ([]*models.SavedSearch, int, error) {
+	return s.savedSearchRepo.GetSavedSearches(ctx, recruiterID, args)
+}
+
+func (s *savedSearchService) DeleteSavedSearch(ctx context.Context, recruiterID, savedSearchID string) error {
+	if _, err := s.GetSavedSearch(ctx, recruiterID, savedSearchID); err != nil {
+		return err
+	}
+	return s.savedSearchRepo.DeleteSavedSearch(ctx, savedSearchID)
+}
+
+// RunSavedSearch runs the saved search again. Non-zero page arguments override the saved ones.
+func (s *savedSearchService) RunSavedSearch(ctx context.Context, recruiterID, savedSearchID string, page *models.SearchArgs) ([]*models.Candidate, int, error) {
+	savedSearch, err := s.GetSavedSearch(ctx, recruiterID, savedSearchID)
+	if err != nil {
+		return nil, 0, err
+	}
+	args := savedSearch.Params
+	if page.PageNum > 0 {
+		args.PageNum = page.PageNum
+	}
+	if page.PageSize > 0 {
+		args.PageSize = page.PageSize
+	}
+	return s.candidateRepo.GetCandidatesBySearch(ctx, &args)
+}
+
+func (s *savedSearchService) GetSavedSearchAlerts(ctx context.Context, recruiterID string, unseenOnly bool, args *models.SearchArgs) ([]*models.SavedSearchAlert, int, error) {
+	return s.savedSearchRepo.GetAlerts(ctx, recruiterID, unseenOnly, args)
+}
+
+func (s *savedSearchService) MarkSavedSearchAlertsSeen(ctx context.Context, recruiterID string, alertIDs []string) error {
+	if alertIDs == nil {
+		alertIDs = []string{}
+	}
+	return s.savedSearchRepo.MarkAlertsSeen(ctx, recruiterID, alertIDs)
+}
+
+// ProcessSavedSearchAlerts checks every saved search for newly matching candidates and records alerts for them.
+// A failing saved search is logged and skipped so it does not block the others.
+func (s *savedSearchService) ProcessSavedSearchAlerts(ctx context.Context) (int, error) {
+	ids, err := s.savedSearchRepo.GetSavedSearchIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, id := range ids {
+		alerts, err := s.savedSearchRepo.RecordNewMatches(ctx, id)
+		if err != nil {
+			if errors.Is(err, models.ErrSavedSearchNotFound) {
+				continue
+			}
+			logging.FromContext(ctx, s.logger).Errorf("failed to process alerts for saved search %s: %v", id, err)
+			continue
+		}
+		total += alerts
+	}
+	return total, nil
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+
 	"github.com/Zhiyenbek/sp-users-main-service/config"
 	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
 	"github.com/Zhiyenbek/sp-users-main-service/internal/repository"
@@ -8,38 +10,143 @@ import (
 )
 
 type CandidatesService interface {
-	GetCandidatesBySearch(*models.SearchArgs) ([]*models.Candidate, int, error)
-	GetCandidateByPublicID(publicID string) (*models.Candidate, error)
-	Exists(publicID string) error
-	AddSkillsToCandidate(candidateID string, skills []string) error
-	UpdateCandidateByID(candidateID string, updateData *models.Candidate) error
-	DeleteCandidateByID(candidateID string) error
-	DeleteSkillsFromCandidate(candidateID string, skills []string) error
-	GetInterviewsByPublicID(publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error)
+	GetCandidatesBySearch(ctx context.Context, args *models.SearchArgs) ([]*models.Candidate, int, error)
+	GetCandidateByPublicID(ctx context.Context, publicID string) (*models.Candidate, error)
+	Exists(ctx context.Context, publicID string) error
+	AddSkillsToCandidate(ctx context.Context, candidateID string, skills []string) error
+	UpdateCandidateByID(ctx context.Context, candidateID string, updateData *models.Candidate) error
+	DeleteCandidateByID(ctx context.Context, candidateID string) error
+	DeleteSkillsFromCandidate(ctx context.Context, candidateID string, skills []string) error
+	ReplaceCandidateSkills(ctx context.Context, candidateID string, skills []string) (*models.SkillsChange, error)
+	GetInterviewsByPublicID(ctx context.Context, publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error)
 }
 type RecruiterService interface {
-	Exists(publicID string) error
-	GetRecruiter(publicID string) (*models.Recruiter, error)
-	GetInterviewsByPublicID(publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error)
+	Exists(ctx context.Context, publicID string) error
+	GetRecruiter(ctx context.Context, publicID string) (*models.Recruiter, error)
+	GetInterviewsByPublicID(ctx context.Context, publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error)
 }
 
 type CompanyService interface {
-	CreateCompany(company *models.Company) (string, error)
-	UpdateCompany(company *models.Company) error
-	GetCompany(publicID string) (*models.Company, error)
-	GetCompanies(args *models.SearchArgs) ([]*models.Company, int, error)
-	Exists(publicID string) error
+	CreateCompany(ctx context.Context, company *models.Company) (string, error)
+	UpdateCompany(ctx context.Context, company *models.Company) error
+	EditCompany(ctx context.Context, recruiterID string, company *models.Company) error
+	GetCompany(ctx context.Context, publicID string) (*models.Company, error)
+	GetCompanies(ctx context.Context, args *models.SearchArgs) ([]*models.Company, int, error)
+	Exists(ctx context.Context, publicID string) error
+}
+type ShortlistService interface {
+	CreateShortlist(ctx context.Context, recruiterID string, shortlist *models.Shortlist) (*models.Shortlist, error)
+	GetShortlist(ctx context.Context, recruiterID, shortlistID string) (*models.Shortlist, error)
+	GetShortlists(ctx context.Context, recruiterID string, args *models.SearchArgs) ([]*models.Shortlist, int, error)
+	UpdateShortlist(ctx context.Context, recruiterID string, shortlist *models.Shortlist) (*models.Shortlist, error)
+	DeleteShortlist(ctx context.Context, recruiterID, shortlistID string) error
+	AddCandidateToShortlist(ctx context.Context, recruiterID, shortlistID, candidateID, note string) error
+	RemoveCandidateFromShortlist(ctx context.Context, recruiterID, shortlistID, candidateID string) error
+	GetShortlistCandidates(ctx context.Context, recruiterID, shortlistID string, args *models.SearchArgs) ([]*models.ShortlistCandidate, int, error)
+	ExportShortlist(ctx context.Context, recruiterID, shortlistID string) (*models.Shortlist, []*models.ShortlistCandidate, error)
+}
+type NoteService interface {
+	CreateNote(ctx context.Context, recruiterID string, note *models.Note) (*models.Note, error)
+	GetNote(ctx context.Context, recruiterID, noteID string) (*models.Note, error)
+	GetNotes(ctx context.Context, recruiterID, subjectType, subjectID string, args *models.SearchArgs) ([]*models.Note, int, error)
+	GetMentions(ctx context.Context, recruiterID string, args *models.SearchArgs) ([]*models.Note, int, error)
+	UpdateNote(ctx context.Context, recruiterID string, note *models.Note) (*models.Note, error)
+	DeleteNote(ctx context.Context, recruiterID, noteID string) error
+	GetNoteHistory(ctx context.Context, recruiterID, noteID string) ([]*models.NoteRevision, error)
+}
+type SavedSearchService interface {
+	CreateSavedSearch(ctx context.Context, recruiterID string, savedSearch *models.SavedSearch) (*models.SavedSearch, error)
+	GetSavedSearch(ctx context.Context, recruiterID, savedSearchID string) (*models.SavedSearch, error)
+	GetSavedSearches(ctx context.Context, recruiterID string, args *models.SearchArgs) ([]*models.SavedSearch, int, error)
+	DeleteSavedSearch(ctx context.Context, recruiterID, savedSearchID string) error
+	RunSavedSearch(ctx context.Context, recruiterID, savedSearchID string, page *models.SearchArgs) ([]*models.Candidate, int, error)
+	GetSavedSearchAlerts(ctx context.Context, recruiterID string, unseenOnly bool, args *models.SearchArgs) ([]*models.SavedSearchAlert, int, error)
+	MarkSavedSearchAlertsSeen(ctx context.Context, recruiterID string, alertIDs []string) error
+	ProcessSavedSearchAlerts(ctx context.Context) (int, error)
+}
+type NotificationService interface {
+	GetNotifications(ctx context.Context, userID string, args *models.SearchArgs) ([]*models.Notification, int, error)
+	GetNotificationPreferences(ctx context.Context, userID string) ([]*models.NotificationPreference, error)
+	UpdateNotificationPreferences(ctx context.Context, userID string, preferences []*models.NotificationPreference) error
+	DeliverNotifications(ctx context.Context) (int, error)
+}
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, recruiterID, companyID string, webhook *models.Webhook) (*models.Webhook, error)
+	GetWebhook(ctx context.Context, recruiterID, companyID, webhookID string) (*models.Webhook, error)
+	GetWebhooks(ctx context.Context, recruiterID, companyID string) ([]*models.Webhook, error)
+	UpdateWebhook(ctx context.Context, recruiterID, companyID string, webhook *models.Webhook) (*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, recruiterID, companyID, webhookID string) error
+	GetWebhookDeliveries(ctx context.Context, recruiterID, companyID, webhookID, status string, args *models.SearchArgs) ([]*models.WebhookDelivery, int, error)
+	ReplayWebhookDelivery(ctx context.Context, recruiterID, companyID, webhookID, deliveryID string) (*models.WebhookDelivery, error)
+	DeliverWebhooks(ctx context.Context) (int, error)
+}
+
+// ProvisioningService creates the profiles of the users signing up with the auth service
+type ProvisioningService interface {
+	ProvisionCandidate(ctx context.Context, candidate *models.CandidateProvision) (*models.ProvisionedUser, error)
+	ProvisionRecruiter(ctx context.Context, recruiter *models.RecruiterProvision) (*models.ProvisionedUser, error)
+	FindOrCreateCompany(ctx context.Context, company *models.Company) (*models.Company, bool, error)
+}
+
+// MembershipService manages the members of companies. Admins and owners invite recruiters by
+// email, decide the requests of recruiters to join, and change the role of members or remove them.
+type MembershipService interface {
+	GetMembers(ctx context.Context, recruiterID, companyID string) ([]*models.CompanyMember, error)
+	UpdateMemberRole(ctx context.Context, recruiterID, companyID, memberID, role string) (*models.Membership, error)
+	RemoveMember(ctx context.Context, recruiterID, companyID, memberID string) error
+	CreateInvitation(ctx context.Context, recruiterID, companyID string, invitation *models.Invitation) (*models.Invitation, error)
+	GetInvitations(ctx context.Context, recruiterID, companyID string, args *models.SearchArgs) ([]*models.Invitation, int, error)
+	RevokeInvitation(ctx context.Context, recruiterID, companyID, invitationID string) error
+	AcceptInvitation(ctx context.Context, recruiterID, token string) (*models.Membership, error)
+	CreateJoinRequest(ctx context.Context, recruiterID, companyID, message string) (*models.JoinRequest, error)
+	GetJoinRequests(ctx context.Context, recruiterID, companyID, status string, args *models.SearchArgs) ([]*models.JoinRequest, int, error)
+	ApproveJoinRequest(ctx context.Context, recruiterID, companyID, joinRequestID, role string) (*models.JoinRequest, error)
+	RejectJoinRequest(ctx context.Context, recruiterID, companyID, joinRequestID string) (*models.JoinRequest, error)
+}
+
+// DomainService verifies the email domains companies claim. A company that verified a domain is
+// verified, and recruiters with an email address on the domain can join it without an invitation.
+type DomainService interface {
+	GetDomains(ctx context.Context, recruiterID, companyID string) ([]*models.CompanyDomain, error)
+	ClaimDomain(ctx context.Context, recruiterID, companyID string, domain *models.CompanyDomain) (*models.CompanyDomain, error)
+	VerifyDomain(ctx context.Context, recruiterID, companyID, domainID, code string) (*models.CompanyDomain, error)
+	DeleteDomain(ctx context.Context, recruiterID, companyID, domainID string) error
+	JoinCompany(ctx context.Context, recruiterID, companyID string) (*models.Membership, error)
+}
+type EventService interface {
+	PublishEvents(ctx context.Context) (int, error)
+	ClosePublisher() error
 }
 type Service struct {
 	CandidatesService
 	RecruiterService
 	CompanyService
+	ShortlistService
+	NoteService
+	SavedSearchService
+	NotificationService
+	EventService
+	WebhookService
+	ProvisioningService
+	MembershipService
+	DomainService
 }
 
 func New(repos *repository.Repository, log *zap.SugaredLogger, cfg *config.Configs) *Service {
-	return &Service{
-		CandidatesService: NewCandidatesService(repos, cfg, log),
-		RecruiterService:  NewRecruitersService(repos, cfg, log),
-		CompanyService:    NewCompanyService(repos.CompanyRepository, cfg, log),
+	s := &Service{
+		CandidatesService:   NewCandidatesService(repos, cfg, log),
+		RecruiterService:    NewRecruitersService(repos, cfg, log),
+		CompanyService:      NewCompanyService(repos, cfg, log),
+		ShortlistService:    NewShortlistService(repos, cfg, log),
+		NoteService:         NewNoteService(repos, cfg, log),
+		SavedSearchService:  NewSavedSearchService(repos, cfg, log),
+		NotificationService: NewNotificationService(repos, cfg, log),
+		EventService:        NewEventService(repos, cfg, log),
+		WebhookService:      NewWebhookService(repos, cfg, log),
+		ProvisioningService: NewProvisioningService(repos, cfg, log),
+		MembershipService:   NewMembershipService(repos, cfg, log),
+		DomainService:       NewDomainService(repos, cfg, log),
 	}
+	s.trace()
+	return s
 }
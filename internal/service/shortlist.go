@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+type shortlistService struct {
+	cfg           *config.Configs
+	logger        *zap.SugaredLogger
+	shortlistRepo repository.ShortlistRepository
+	recruiterRepo repository.RecruiterRepository
+	uow           repository.UnitOfWork
+}
+
+func NewShortlistService(repo *repository.Repository, cfg *config.Configs, logger *zap.SugaredLogger) *shortlistService {
+	return &shortlistService{
+		shortlistRepo: repo.ShortlistRepository,
+		recruiterRepo: repo.RecruiterRepository,
+		uow:           repo.UnitOfWork,
+		cfg:           cfg,
+		logger:        logger,
+	}
+}
+
+func (s *shortlistService) CreateShortlist(ctx context.Context, recruiterID string, shortlist *models.Shortlist) (*models.Shortlist, error) {
+	companyID, err := s.recruiterRepo.GetCompanyPublicID(ctx, recruiterID)
+	if err != nil {
+		return nil, err
+	}
+	shortlist.RecruiterPublicID = recruiterID
+	shortlist.CompanyPublicID = companyID
+
+	publicID, err := s.shortlistRepo.CreateShortlist(ctx, shortlist)
+	if err != nil {
+		return nil, err
+	}
+	return s.shortlistRepo.GetShortlist(ctx, publicID)
+}
+
+// GetShortlist returns the shortlist if it is owned by the recruiter or shared across the recruiter's company.
+// Shortlists the recruiter cannot see are reported as not found.
+func (s *shortlistService) GetShortlist(ctx context.Context, recruiterID, shortlistID string) (*models.Shortlist, error) {
+	shortlist, err := s.shortlistRepo.GetShortlist(ctx, shortlistID)
+	if err != nil {
+		return nil, err
+	}
+	if shortlist.RecruiterPublicID == recruiterID {
+		return shortlist, nil
+	}
+	if shortlist.Shared == nil || !*shortlist.Shared {
+		return nil, models.ErrShortlistNotFound
+	}
+	companyID, err := s.recruiterRepo.GetCompanyPublicID(ctx, recruiterID)
+	if err != nil {
+		return nil, err
+	}
+	if shortlist.CompanyPublicID != companyID {
+		return nil, models.ErrShortlistNotFound
+	}
+	return shortlist, nil
+}
+
+func (s *shortlistService) GetShortlists(ctx context.Context, recruiterID string, args *models.SearchArgs) ([]*models.Shortlist, int, error) {
+	companyID, err := s.recruiterRepo.GetCompanyPublicID(ctx, recruiterID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.shortlistRepo.GetShortlists(ctx, recruiterID, companyID, args)
+}
+
+// UpdateShortlist updates the shortlist. Only the owner of the shortlist may rename it or change its sharing.
+func (s *shortlistService) UpdateShortlist(ctx context.Context, recruiterID string, shortlist *models.Shortlist) (*models.Shortlist, error) {
+	var res *models.Shortlist
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		current, err := s.GetShortlist(ctx, recruiterID, shortlist.PublicID)
+		if err != nil {
+			return err
+		}
+		if current.RecruiterPublicID != recruiterID {
+			return models.ErrPermissionDenied
+		}
+		if err := s.shortlistRepo.UpdateShortlist(ctx, shortlist); err != nil {
+			return err
+		}
+		res, err = s.shortlistRepo.GetShortlist(ctx, shortlist.PublicID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteShortlist deletes the shortlist. Only the owner of the shortlist may delete it.
+func (s *shortlistService) DeleteShortlist(ctx context.Context, recruiterID, shortlistID string) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		current, err := s.GetShortlist(ctx, recruiterID, shortlistID)
+		if err != nil {
+			return err
+		}
+		if current.RecruiterPublicID != recruiterID {
+			return models.ErrPermissionDenied
+		}
+		return s.shortlistRepo.DeleteShortlist(ctx, shortlistID)
+	})
+}
+
+func (s *shortlistService) AddCandidateToShortlist(ctx context.Context, recruiterID, shortlistID, candidateID, note string) error {
+	if _, err := s.GetShortlist(ctx, recruiterID, shortlistID); err != nil {
+		return err
+	}
+	return s.shortlistRepo.AddCandidate(ctx, shortlistID, candidateID, recruiterID, note)
+}
+
+func (s *shortlistService) RemoveCandidateFromShortlist(ctx context.Context, recruiterID, shortlistID, candidateID string) error {
+	if _, err := s.GetShortlist(ctx, recruiterID, shortlistID); err != nil {
+		return err
+	}
+	return s.shortlistRepo.RemoveCandidate(ctx, shortlistID, candidateID)
+}
+
+func (s *shortlistService) GetShortlistCandidates(ctx context.Context, recruiterID, shortlistID string, args *models.SearchArgs) ([]*models.ShortlistCandidate, int, error) {
+	if _, err := s.GetShortlist(ctx, recruiterID, shortlistID); err != nil {
+		return nil, 0, err
+	}
+	return s.shortlistRepo.GetCandidates(ctx, shortlistID, args)
+}
+
+// ExportShortlist returns the shortlist together with every candidate on it.
+func (s *shortlistService) ExportShortlist(ctx context.Context, recruiterID, shortlistID string) (*models.Shortlist, []*models.ShortlistCandidate, error) {
+	shortlist, err := s.GetShortlist(ctx, recruiterID, shortlistID)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, _, err := s.shortlistRepo.GetCandidates(ctx, shortlistID, &models.SearchArgs{
+		PageNum:  models.DefaultPageNum,
+		PageSize: 0,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return shortlist, res, nil
+}
@@ -0,0 +1,595 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/tracing"
+)
+
+// trace starts a span for every service call, so the spans of the repositories and queries
+// a request runs are grouped by the business operation they belong to.
+func (s *Service) trace() {
+	s.CandidatesService = &tracedCandidatesService{next: s.CandidatesService}
+	s.RecruiterService = &tracedRecruiterService{next: s.RecruiterService}
+	s.CompanyService = &tracedCompanyService{next: s.CompanyService}
+	s.ShortlistService = &tracedShortlistService{next: s.ShortlistService}
+	s.NoteService = &tracedNoteService{next: s.NoteService}
+	s.SavedSearchService = &tracedSavedSearchService{next: s.SavedSearchService}
+	s.NotificationService = &tracedNotificationService{next: s.NotificationService}
+	s.WebhookService = &tracedWebhookService{next: s.WebhookService}
+	s.EventService = &tracedEventService{next: s.EventService}
+	s.ProvisioningService = &tracedProvisioningService{next: s.ProvisioningService}
+	s.MembershipService = &tracedMembershipService{next: s.MembershipService}
+	s.DomainService = &tracedDomainService{next: s.DomainService}
+}
+
+type tracedCandidatesService struct {
+	next CandidatesService
+}
+
+func (s *tracedCandidatesService) GetCandidatesBySearch(ctx context.Context, args *models.SearchArgs) ([]*models.Candidate, int, error) {
+	ctx, span := tracing.Start(ctx, "CandidatesService.GetCandidatesBySearch")
+	res, total, err := s.next.GetCandidatesBySearch(ctx, args)
+	tracing.End(span, err)
+	return res, total, err
+}
+
+func (s *tracedCandidatesService) GetCandidateByPublicID(ctx context.Context, publicID string) (*models.Candidate, error) {
+	ctx, span := tracing.Start(ctx, "CandidatesService.GetCandidateByPublicID")
+	res, err := s.next.GetCandidateByPublicID(ctx, publicID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedCandidatesService) Exists(ctx context.Context, publicID string) error {
+	ctx, span := tracing.Start(ctx, "CandidatesService.Exists")
+	err := s.next.Exists(ctx, publicID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedCandidatesService) AddSkillsToCandidate(ctx context.Context, candidateID string, skills []string) error {
+	ctx, span := tracing.Start(ctx, "CandidatesService.AddSkillsToCandidate")
+	err := s.next.AddSkillsToCandidate(ctx, candidateID, skills)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedCandidatesService) UpdateCandidateByID(ctx context.Context, candidateID string, updateData *models.Candidate) error {
+	ctx, span := tracing.Start(ctx, "CandidatesService.UpdateCandidateByID")
+	err := s.next.UpdateCandidateByID(ctx, candidateID, updateData)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedCandidatesService) DeleteCandidateByID(ctx context.Context, candidateID string) error {
+	ctx, span := tracing.Start(ctx, "CandidatesService.DeleteCandidateByID")
+	err := s.next.DeleteCandidateByID(ctx, candidateID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedCandidatesService) DeleteSkillsFromCandidate(ctx context.Context, candidateID string, skills []string) error {
+	ctx, span := tracing.Start(ctx, "CandidatesService.DeleteSkillsFromCandidate")
+	err := s.next.DeleteSkillsFromCandidate(ctx, candidateID, skills)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedCandidatesService) ReplaceCandidateSkills(ctx context.Context, candidateID string, skills []string) (*models.SkillsChange, error) {
+	ctx, span := tracing.Start(ctx, "CandidatesService.ReplaceCandidateSkills")
+	res, err := s.next.ReplaceCandidateSkills(ctx, candidateID, skills)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedCandidatesService) GetInterviewsByPublicID(ctx context.Context, publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error) {
+	ctx, span := tracing.Start(ctx, "CandidatesService.GetInterviewsByPublicID")
+	res, total, err := s.next.GetInterviewsByPublicID(ctx, publicID, searchArgs)
+	tracing.End(span, err)
+	return res, total, err
+}
+
+type tracedRecruiterService struct {
+	next RecruiterService
+}
+
+func (s *tracedRecruiterService) Exists(ctx context.Context, publicID string) error {
+	ctx, span := tracing.Start(ctx, "RecruiterService.Exists")
+	err := s.next.Exists(ctx, publicID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedRecruiterService) GetRecruiter(ctx context.Context, publicID string) (*models.Recruiter, error) {
+	ctx, span := tracing.Start(ctx, "RecruiterService.GetRecruiter")
+	res, err := s.next.GetRecruiter(ctx, publicID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedRecruiterService) GetInterviewsByPublicID(ctx context.Context, publicID string, searchArgs *models.SearchArgs) ([]*models.InterviewResults, int, error) {
+	ctx, span := tracing.Start(ctx, "RecruiterService.GetInterviewsByPublicID")
+	res, total, err := s.next.GetInterviewsByPublicID(ctx, publicID, searchArgs)
+	tracing.End(span, err)
+	return res, total, err
+}
+
+type tracedCompanyService struct {
+	next CompanyService
+}
+
+func (s *tracedCompanyService) CreateCompany(ctx context.Context, company *models.Company) (string, error) {
+	ctx, span := tracing.Start(ctx, "CompanyService.CreateCompany")
+	res, err := s.next.CreateCompany(ctx, company)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedCompanyService) UpdateCompany(ctx context.Context, company *models.Company) error {
+	ctx, span := tracing.Start(ctx, "CompanyService.UpdateCompany")
+	err := s.next.UpdateCompany(ctx, company)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedCompanyService) EditCompany(ctx context.Context, recruiterID string, company *models.Company) error {
+	ctx, span := tracing.Start(ctx, "CompanyService.EditCompany")
+	err := s.next.EditCompany(ctx, recruiterID, company)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedCompanyService) GetCompany(ctx context.Context, publicID string) (*models.Company, error) {
+	ctx, span := tracing.Start(ctx, "CompanyService.GetCompany")
+	res, err := s.next.GetCompany(ctx, publicID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedCompanyService) GetCompanies(ctx context.Context, args *models.SearchArgs) ([]*models.Company, int, error) {
+	ctx, span := tracing.Start(ctx, "CompanyService.GetCompanies")
+	res, total, err := s.next.GetCompanies(ctx, args)
+	tracing.End(span, err)
+	return res, total, err
+}
+
+func (s *tracedCompanyService) Exists(ctx context.Context, publicID string) error {
+	ctx, span := tracing.Start(ctx, "CompanyService.Exists")
+	err := s.next.Exists(ctx, publicID)
+	tracing.End(span, err)
+	return err
+}
+
+type tracedShortlistService struct {
+	next ShortlistService
+}
+
+func (s *tracedShortlistService) CreateShortlist(ctx context.Context, recruiterID string, shortlist *models.Shortlist) (*models.Shortlist, error) {
+	ctx, span := tracing.Start(ctx, "ShortlistService.CreateShortlist")
+	res, err := s.next.CreateShortlist(ctx, recruiterID, shortlist)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedShortlistService) GetShortlist(ctx context.Context, recruiterID, shortlistID string) (*models.Shortlist, error) {
+	ctx, span := tracing.Start(ctx, "ShortlistService.GetShortlist")
+	res, err := s.next.GetShortlist(ctx, recruiterID, shortlistID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedShortlistService) GetShortlists(ctx context.Context, recruiterID string, args *models.SearchArgs) ([]*models.Shortlist, int, error) {
+	ctx, span := tracing.Start(ctx, "ShortlistService.GetShortlists")
+	res, total, err := s.next.GetShortlists(ctx, recruiterID, args)
+	tracing.End(span, err)
+	return res, total, err
+}
+
+func (s *tracedShortlistService) UpdateShortlist(ctx context.Context, recruiterID string, shortlist *models.Shortlist) (*models.Shortlist, error) {
+	ctx, span := tracing.Start(ctx, "ShortlistService.UpdateShortlist")
+	res, err := s.next.UpdateShortlist(ctx, recruiterID, shortlist)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedShortlistService) DeleteShortlist(ctx context.Context, recruiterID, shortlistID string) error {
+	ctx, span := tracing.Start(ctx, "ShortlistService.DeleteShortlist")
+	err := s.next.DeleteShortlist(ctx, recruiterID, shortlistID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedShortlistService) AddCandidateToShortlist(ctx context.Context, recruiterID, shortlistID, candidateID, note string) error {
+	ctx, span := tracing.Start(ctx, "ShortlistService.AddCandidateToShortlist")
+	err := s.next.AddCandidateToShortlist(ctx, recruiterID, shortlistID, candidateID, note)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedShortlistService) RemoveCandidateFromShortlist(ctx context.Context, recruiterID, shortlistID, candidateID string) error {
+	ctx, span := tracing.Start(ctx, "ShortlistService.RemoveCandidateFromShortlist")
+	err := s.next.RemoveCandidateFromShortlist(ctx, recruiterID, shortlistID, candidateID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedShortlistService) GetShortlistCandidates(ctx context.Context, recruiterID, shortlistID string, args *models.SearchArgs) ([]*models.ShortlistCandidate, int, error) {
+	ctx, span := tracing.Start(ctx, "ShortlistService.GetShortlistCandidates")
+	res, total, err := s.next.GetShortlistCandidates(ctx, recruiterID, shortlistID, args)
+	tracing.End(span, err)
+	return res, total, err
+}
+
+func (s *tracedShortlistService) ExportShortlist(ctx context.Context, recruiterID, shortlistID string) (*models.Shortlist, []*models.ShortlistCandidate, error) {
+	ctx, span := tracing.Start(ctx, "ShortlistService.ExportShortlist")
+	shortlist, candidates, err := s.next.ExportShortlist(ctx, recruiterID, shortlistID)
+	tracing.End(span, err)
+	return shortlist, candidates, err
+}
+
+type tracedNoteService struct {
+	next NoteService
+}
+
+func (s *tracedNoteService) CreateNote(ctx context.Context, recruiterID string, note *models.Note) (*models.Note, error) {
+	ctx, span := tracing.Start(ctx, "NoteService.CreateNote")
+	res, err := s.next.CreateNote(ctx, recruiterID, note)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedNoteService) GetNote(ctx context.Context, recruiterID, noteID string) (*models.Note, error) {
+	ctx, span := tracing.Start(ctx, "NoteService.GetNote")
+	res, err := s.next.GetNote(ctx, recruiterID, noteID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedNoteService) GetNotes(ctx context.Context, recruiterID, subjectType, subjectID string, args *models.SearchArgs) ([]*models.Note, int, error) {
+	ctx, span := tracing.Start(ctx, "NoteService.GetNotes")
+	res, total, err := s.next.GetNotes(ctx, recruiterID, subjectType, subjectID, args)
+	tracing.End(span, err)
+	return res, total, err
+}
+
+func (s *tracedNoteService) GetMentions(ctx context.Context, recruiterID string, args *models.SearchArgs) ([]*models.Note, int, error) {
+	ctx, span := tracing.Start(ctx, "NoteService.GetMentions")
+	res, total, err := s.next.GetMentions(ctx, recruiterID, args)
+	tracing.End(span, err)
+	return res, total, err
+}
+
+func (s *tracedNoteService) UpdateNote(ctx context.Context, recruiterID string, note *models.Note) (*models.Note, error) {
+	ctx, span := tracing.Start(ctx, "NoteService.UpdateNote")
+	res, err := s.next.UpdateNote(ctx, recruiterID, note)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedNoteService) DeleteNote(ctx context.Context, recruiterID, noteID string) error {
+	ctx, span := tracing.Start(ctx, "NoteService.DeleteNote")
+	err := s.next.DeleteNote(ctx, recruiterID, noteID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedNoteService) GetNoteHistory(ctx context.Context, recruiterID, noteID string) ([]*models.NoteRevision, error) {
+	ctx, span := tracing.Start(ctx, "NoteService.GetNoteHistory")
+	res, err := s.next.GetNoteHistory(ctx, recruiterID, noteID)
+	tracing.End(span, err)
+	return res, err
+}
+
+type tracedSavedSearchService struct {
+	next SavedSearchService
+}
+
+func (s *tracedSavedSearchService) CreateSavedSearch(ctx context.Context, recruiterID string, savedSearch *models.SavedSearch) (*models.SavedSearch, error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchService.CreateSavedSearch")
+	res, err := s.next.CreateSavedSearch(ctx, recruiterID, savedSearch)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedSavedSearchService) GetSavedSearch(ctx context.Context, recruiterID, savedSearchID string) (*models.SavedSearch, error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchService.GetSavedSearch")
+	res, err := s.next.GetSavedSearch(ctx, recruiterID, savedSearchID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedSavedSearchService) GetSavedSearches(ctx context.Context, recruiterID string, args *models.SearchArgs) ([]*models.SavedSearch, int, error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchService.GetSavedSearches")
+	res, total, err := s.next.GetSavedSearches(ctx, recruiterID, args)
+	tracing.End(span, err)
+	return res, total, err
+}
+
+func (s *tracedSavedSearchService) DeleteSavedSearch(ctx context.Context, recruiterID, savedSearchID string) error {
+	ctx, span := tracing.Start(ctx, "SavedSearchService.DeleteSavedSearch")
+	err := s.next.DeleteSavedSearch(ctx, recruiterID, savedSearchID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedSavedSearchService) RunSavedSearch(ctx context.Context, recruiterID, savedSearchID string, page *models.SearchArgs) ([]*models.Candidate, int, error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchService.RunSavedSearch")
+	res, total, err := s.next.RunSavedSearch(ctx, recruiterID, savedSearchID, page)
+	tracing.End(span, err)
+	return res, total, err
+}
+
+func (s *tracedSavedSearchService) GetSavedSearchAlerts(ctx context.Context, recruiterID string, unseenOnly bool, args *models.SearchArgs) ([]*models.SavedSearchAlert, int, error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchService.GetSavedSearchAlerts")
+	res, total, err := s.next.GetSavedSearchAlerts(ctx, recruiterID, unseenOnly, args)
+	tracing.End(span, err)
+	return res, total, err
+}
+
+func (s *tracedSavedSearchService) MarkSavedSearchAlertsSeen(ctx context.Context, recruiterID string, alertIDs []string) error {
+	ctx, span := tracing.Start(ctx, "SavedSearchService.MarkSavedSearchAlertsSeen")
+	err := s.next.MarkSavedSearchAlertsSeen(ctx, recruiterID, alertIDs)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedSavedSearchService) ProcessSavedSearchAlerts(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchService.ProcessSavedSearchAlerts")
+	res, err := s.next.ProcessSavedSearchAlerts(ctx)
+	tracing.End(span, err)
+	return res, err
+}
+
+type tracedNotificationService struct {
+	next NotificationService
+}
+
+func (s *tracedNotificationService) GetNotifications(ctx context.Context, userID string, args *models.SearchArgs) ([]*models.Notification, int, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.GetNotifications")
+	res, total, err := s.next.GetNotifications(ctx, userID, args)
+	tracing.End(span, err)
+	return res, total, err
+}
+
+func (s *tracedNotificationService) GetNotificationPreferences(ctx context.Context, userID string) ([]*models.NotificationPreference, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.GetNotificationPreferences")
+	res, err := s.next.GetNotificationPreferences(ctx, userID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedNotificationService) UpdateNotificationPreferences(ctx context.Context, userID string, preferences []*models.NotificationPreference) error {
+	ctx, span := tracing.Start(ctx, "NotificationService.UpdateNotificationPreferences")
+	err := s.next.UpdateNotificationPreferences(ctx, userID, preferences)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedNotificationService) DeliverNotifications(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.DeliverNotifications")
+	res, err := s.next.DeliverNotifications(ctx)
+	tracing.End(span, err)
+	return res, err
+}
+
+type tracedWebhookService struct {
+	next WebhookService
+}
+
+func (s *tracedWebhookService) CreateWebhook(ctx context.Context, recruiterID, companyID string, webhook *models.Webhook) (*models.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.CreateWebhook")
+	res, err := s.next.CreateWebhook(ctx, recruiterID, companyID, webhook)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedWebhookService) GetWebhook(ctx context.Context, recruiterID, companyID, webhookID string) (*models.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetWebhook")
+	res, err := s.next.GetWebhook(ctx, recruiterID, companyID, webhookID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedWebhookService) GetWebhooks(ctx context.Context, recruiterID, companyID string) ([]*models.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetWebhooks")
+	res, err := s.next.GetWebhooks(ctx, recruiterID, companyID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedWebhookService) UpdateWebhook(ctx context.Context, recruiterID, companyID string, webhook *models.Webhook) (*models.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.UpdateWebhook")
+	res, err := s.next.UpdateWebhook(ctx, recruiterID, companyID, webhook)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedWebhookService) DeleteWebhook(ctx context.Context, recruiterID, companyID, webhookID string) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.DeleteWebhook")
+	err := s.next.DeleteWebhook(ctx, recruiterID, companyID, webhookID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedWebhookService) GetWebhookDeliveries(ctx context.Context, recruiterID, companyID, webhookID, status string, args *models.SearchArgs) ([]*models.WebhookDelivery, int, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetWebhookDeliveries")
+	res, total, err := s.next.GetWebhookDeliveries(ctx, recruiterID, companyID, webhookID, status, args)
+	tracing.End(span, err)
+	return res, total, err
+}
+
+func (s *tracedWebhookService) ReplayWebhookDelivery(ctx context.Context, recruiterID, companyID, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.ReplayWebhookDelivery")
+	res, err := s.next.ReplayWebhookDelivery(ctx, recruiterID, companyID, webhookID, deliveryID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedWebhookService) DeliverWebhooks(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.DeliverWebhooks")
+	res, err := s.next.DeliverWebhooks(ctx)
+	tracing.End(span, err)
+	return res, err
+}
+
+type tracedEventService struct {
+	next EventService
+}
+
+func (s *tracedEventService) PublishEvents(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "EventService.PublishEvents")
+	res, err := s.next.PublishEvents(ctx)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedEventService) ClosePublisher() error {
+	return s.next.ClosePublisher()
+}
+
+type tracedProvisioningService struct {
+	next ProvisioningService
+}
+
+func (s *tracedProvisioningService) ProvisionCandidate(ctx context.Context, candidate *models.CandidateProvision) (*models.ProvisionedUser, error) {
+	ctx, span := tracing.Start(ctx, "ProvisioningService.ProvisionCandidate")
+	res, err := s.next.ProvisionCandidate(ctx, candidate)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedProvisioningService) ProvisionRecruiter(ctx context.Context, recruiter *models.RecruiterProvision) (*models.ProvisionedUser, error) {
+	ctx, span := tracing.Start(ctx, "ProvisioningService.ProvisionRecruiter")
+	res, err := s.next.ProvisionRecruiter(ctx, recruiter)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedProvisioningService) FindOrCreateCompany(ctx context.Context, company *models.Company) (*models.Company, bool, error) {
+	ctx, span := tracing.Start(ctx, "ProvisioningService.FindOrCreateCompany")
+	res, created, err := s.next.FindOrCreateCompany(ctx, company)
+	tracing.End(span, err)
+	return res, created, err
+}
+
+type tracedMembershipService struct {
+	next MembershipService
+}
+
+func (s *tracedMembershipService) GetMembers(ctx context.Context, recruiterID, companyID string) ([]*models.CompanyMember, error) {
+	ctx, span := tracing.Start(ctx, "MembershipService.GetMembers")
+	res, err := s.next.GetMembers(ctx, recruiterID, companyID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedMembershipService) UpdateMemberRole(ctx context.Context, recruiterID, companyID, memberID, role string) (*models.Membership, error) {
+	ctx, span := tracing.Start(ctx, "MembershipService.UpdateMemberRole")
+	res, err := s.next.UpdateMemberRole(ctx, recruiterID, companyID, memberID, role)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedMembershipService) RemoveMember(ctx context.Context, recruiterID, companyID, memberID string) error {
+	ctx, span := tracing.Start(ctx, "MembershipService.RemoveMember")
+	err := s.next.RemoveMember(ctx, recruiterID, companyID, memberID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedMembershipService) CreateInvitation(ctx context.Context, recruiterID, companyID string, invitation *models.Invitation) (*models.Invitation, error) {
+	ctx, span := tracing.Start(ctx, "MembershipService.CreateInvitation")
+	res, err := s.next.CreateInvitation(ctx, recruiterID, companyID, invitation)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedMembershipService) GetInvitations(ctx context.Context, recruiterID, companyID string, args *models.SearchArgs) ([]*models.Invitation, int, error) {
+	ctx, span := tracing.Start(ctx, "MembershipService.GetInvitations")
+	res, total, err := s.next.GetInvitations(ctx, recruiterID, companyID, args)
+	tracing.End(span, err)
+	return res, total, err
+}
+
+func (s *tracedMembershipService) RevokeInvitation(ctx context.Context, recruiterID, companyID, invitationID string) error {
+	ctx, span := tracing.Start(ctx, "MembershipService.RevokeInvitation")
+	err := s.next.RevokeInvitation(ctx, recruiterID, companyID, invitationID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedMembershipService) AcceptInvitation(ctx context.Context, recruiterID, token string) (*models.Membership, error) {
+	ctx, span := tracing.Start(ctx, "MembershipService.AcceptInvitation")
+	res, err := s.next.AcceptInvitation(ctx, recruiterID, token)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedMembershipService) CreateJoinRequest(ctx context.Context, recruiterID, companyID, message string) (*models.JoinRequest, error) {
+	ctx, span := tracing.Start(ctx, "MembershipService.CreateJoinRequest")
+	res, err := s.next.CreateJoinRequest(ctx, recruiterID, companyID, message)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedMembershipService) GetJoinRequests(ctx context.Context, recruiterID, companyID, status string, args *models.SearchArgs) ([]*models.JoinRequest, int, error) {
+	ctx, span := tracing.Start(ctx, "MembershipService.GetJoinRequests")
+	res, total, err := s.next.GetJoinRequests(ctx, recruiterID, companyID, status, args)
+	tracing.End(span, err)
+	return res, total, err
+}
+
+func (s *tracedMembershipService) ApproveJoinRequest(ctx context.Context, recruiterID, companyID, joinRequestID, role string) (*models.JoinRequest, error) {
+	ctx, span := tracing.Start(ctx, "MembershipService.ApproveJoinRequest")
+	res, err := s.next.ApproveJoinRequest(ctx, recruiterID, companyID, joinRequestID, role)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedMembershipService) RejectJoinRequest(ctx context.Context, recruiterID, companyID, joinRequestID string) (*models.JoinRequest, error) {
+	ctx, span := tracing.Start(ctx, "MembershipService.RejectJoinRequest")
+	res, err := s.next.RejectJoinRequest(ctx, recruiterID, companyID, joinRequestID)
+	tracing.End(span, err)
+	return res, err
+}
+
+type tracedDomainService struct {
+	next DomainService
+}
+
+func (s *tracedDomainService) GetDomains(ctx context.Context, recruiterID, companyID string) ([]*models.CompanyDomain, error) {
+	ctx, span := tracing.Start(ctx, "DomainService.GetDomains")
+	res, err := s.next.GetDomains(ctx, recruiterID, companyID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedDomainService) ClaimDomain(ctx context.Context, recruiterID, companyID string, domain *models.CompanyDomain) (*models.CompanyDomain, error) {
+	ctx, span := tracing.Start(ctx, "DomainService.ClaimDomain")
+	res, err := s.next.ClaimDomain(ctx, recruiterID, companyID, domain)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedDomainService) VerifyDomain(ctx context.Context, recruiterID, companyID, domainID, code string) (*models.CompanyDomain, error) {
+	ctx, span := tracing.Start(ctx, "DomainService.VerifyDomain")
+	res, err := s.next.VerifyDomain(ctx, recruiterID, companyID, domainID, code)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedDomainService) DeleteDomain(ctx context.Context, recruiterID, companyID, domainID string) error {
+	ctx, span := tracing.Start(ctx, "DomainService.DeleteDomain")
+	err := s.next.DeleteDomain(ctx, recruiterID, companyID, domainID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedDomainService) JoinCompany(ctx context.Context, recruiterID, companyID string) (*models.Membership, error) {
+	ctx, span := tracing.Start(ctx, "DomainService.JoinCompany")
+	res, err := s.next.JoinCompany(ctx, recruiterID, companyID)
+	tracing.End(span, err)
+	return res, err
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/config"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/logging"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/repository"
+	"github.com/Zhiyenbek/sp-users-main-service/internal/webhook"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultWebhookBatchSize    = 50
+	defaultWebhookMaxAttempts  = 8
+	defaultWebhookRetryBackoff = 30 * time.Second
+	defaultWebhookTimeout      = 10 * time.Second
+	// webhookLease is how long a claimed delivery is hidden from other workers.
+	webhookLease = 5 * time.Minute
+)
+
+type webhookService struct {
+	cfg           *config.Configs
+	logger        *zap.SugaredLogger
+	webhookRepo   repository.WebhookRepository
+	recruiterRepo repository.RecruiterRepository
+	sender        webhook.Sender
+}
+
+func NewWebhookService(repo *repository.Repository, cfg *config.Configs, logger *zap.SugaredLogger) *webhookService {
+	timeout := defaultWebhookTimeout
+	if cfg.Webhooks != nil && cfg.Webhooks.Timeout > 0 {
+		timeout = cfg.Webhooks.Timeout
+	}
+	allowPrivate := cfg.Webhooks != nil && cfg.Webhooks.AllowPrivateNetworks
+	return &webhookService{
+		webhookRepo:   repo.WebhookRepository,
+		recruiterRepo: repo.RecruiterRepository,
+		sender:        webhook.NewHTTPSender(timeout, allowPrivate),
+		cfg:           cfg,
+		logger:        logger,
+	}
+}
+
+// CreateWebhook subscribes a webhook for the company of the recruiter.
+// A secret is generated when none is given; it is only returned here.
+func (s *webhookService) CreateWebhook(ctx context.Context, recruiterID, companyID string, wh *models.Webhook) (*models.Webhook, error) {
+	if err := s.authorize(ctx, recruiterID, companyID); err != nil {
+		return nil, err
+	}
+	if err := s.validateWebhook(wh, true); err != nil {
+		return nil, err
+	}
+	if wh.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		wh.Secret = secret
+	}
+	wh.CompanyPublicID = companyID
+	wh.CreatedBy = recruiterID
+
+	publicID, err := s.webhookRepo.CreateWebhook(ctx, wh)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.webhookRepo.GetWebhook(ctx, publicID)
+	if err != nil {
+		return nil, err
+	}
+	res.Secret = wh.Secret
+	return res, nil
+}
+
+func (s *webhookService) GetWebhook(ctx context.Context, recruiterID, companyID, webhookID string) (*models.Webhook, error) {
+	if err := s.authorize(ctx, recruiterID, companyID); err != nil {
+		return nil, err
+	}
+	return s.companyWebhook(ctx, companyID, webhookID)
+}
+
+func (s *webhookService) GetWebhooks(ctx context.Context, recruiterID, companyID string) ([]*models.Webhook, error) {
+	if err := s.authorize(ctx, recruiterID, companyID); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.GetWebhooks(ctx, companyID)
+}
+
+// UpdateWebhook changes the URL, secret, event types or active state of the webhook.
+func (s *webhookService) UpdateWebhook(ctx context.Context, recruiterID, companyID string, wh *models.Webhook) (*models.Webhook, error) {
+	if err := s.authorize(ctx, recruiterID, companyID); err != nil {
+		return nil, err
+	}
+	if err := s.validateWebhook(wh, false); err != nil {
+		return nil, err
+	}
+	if _, err := s.companyWebhook(ctx, companyID, wh.PublicID); err != nil {
+		return nil, err
+	}
+	if err := s.webhookRepo.UpdateWebhook(ctx, wh); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.GetWebhook(ctx, wh.PublicID)
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, recruiterID, companyID, webhookID string) error {
+	if err := s.authorize(ctx, recruiterID, companyID); err != nil {
+		return err
+	}
+	if _, err := s.companyWebhook(ctx, companyID, webhookID); err != nil {
+		return err
+	}
+	return s.webhookRepo.DeleteWebhook(ctx, webhookID)
+}
+
+func (s *webhookService) GetWebhookDeliveries(ctx context.Context, recruiterID, companyID, webhookID, status string, args *models.SearchArgs) ([]*models.WebhookDelivery, int, error) {
+	if err := s.authorize(ctx, recruiterID, companyID); err != nil {
+		return nil, 0, err
+	}
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliveryDelivered, models.WebhookDeliveryDead:
+	default:
+		return nil, 0, models.ErrInvalidInput.WithField("status", "is not a known delivery status")
+	}
+	if _, err := s.companyWebhook(ctx, companyID, webhookID); err != nil {
+		return nil, 0, err
+	}
+	return s.webhookRepo.GetDeliveries(ctx, webhookID, status, args)
+}
+
+// ReplayWebhookDelivery queues a delivery to be sent again, e.g. after it was moved to the dead-letter state.
+func (s *webhookService) ReplayWebhookDelivery(ctx context.Context, recruiterID, companyID, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+	if err := s.authorize(ctx, recruiterID, companyID); err != nil {
+		return nil, err
+	}
+	if _, err := s.companyWebhook(ctx, companyID, webhookID); err != nil {
+		return nil, err
+	}
+	delivery, err := s.webhookRepo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.WebhookPublicID != webhookID {
+		return nil, models.ErrDeliveryNotFound
+	}
+	if err := s.webhookRepo.ReplayDelivery(ctx, deliveryID); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.GetDelivery(ctx, deliveryID)
+}
+
+// DeliverWebhooks sends the webhook deliveries that are due and returns how many were delivered.
+// Failed deliveries are retried with exponential backoff; after the maximum number of attempts
+// they are moved to the dead-letter state, from which they can be replayed.
+func (s *webhookService) DeliverWebhooks(ctx context.Context) (int, error) {
+	batchSize, maxAttempts, backoff := defaultWebhookBatchSize, defaultWebhookMaxAttempts, defaultWebhookRetryBackoff
+	if cfg := s.cfg.Webhooks; cfg != nil {
+		if cfg.BatchSize > 0 {
+			batchSize = cfg.BatchSize
+		}
+		if cfg.MaxAttempts > 0 {
+			maxAttempts = cfg.MaxAttempts
+		}
+		if cfg.RetryBackoff > 0 {
+			backoff = cfg.RetryBackoff
+		}
+	}
+
+	pending, err := s.webhookRepo.ClaimPendingDeliveries(ctx, batchSize, webhookLease)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, d := range pending {
+		statusCode, err := s.sender.Send(d)
+		if err == nil {
+			delivered++
+			if err := s.webhookRepo.RecordDeliveryAttempt(ctx, d.PublicID, true, statusCode, "", nil); err != nil {
+				logging.FromContext(ctx, s.logger).Errorf("failed to record delivery of webhook delivery %s: %v", d.PublicID, err)
+			}
+			continue
+		}
+
+		var nextAttemptAt *time.Time
+		if d.Attempts+1 < maxAttempts {
+			next := time.Now().Add(retryDelay(backoff, d.Attempts))
+			nextAttemptAt = &next
+		}
+		logging.FromContext(ctx, s.logger).Warnf("failed to deliver webhook delivery %s (attempt %d): %v", d.PublicID, d.Attempts+1, err)
+		if err := s.webhookRepo.RecordDeliveryAttempt(ctx, d.PublicID, false, statusCode, err.Error(), nextAttemptAt); err != nil {
+			logging.FromContext(ctx, s.logger).Errorf("failed to record delivery of webhook delivery %s: %v", d.PublicID, err)
+		}
+	}
+	return delivered, nil
+}
+
+// authorize checks that the recruiter is an admin or owner of the company, who manage its webhooks.
+func (s *webhookService) authorize(ctx context.Context, recruiterID, companyID string) error {
+	membership, err := s.recruiterRepo.GetMembership(ctx, recruiterID)
+	if err != nil {
+		return err
+	}
+	if membership.CompanyPublicID != companyID || !membership.IsAdmin() {
+		return models.ErrPermissionDenied
+	}
+	return nil
+}
+
+// companyWebhook returns the webhook if it belongs to the company.
+// Webhooks of other companies are reported as not found.
+func (s *webhookService) companyWebhook(ctx context.Context, companyID, webhookID string) (*models.Webhook, error) {
+	wh, err := s.webhookRepo.GetWebhook(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if wh.CompanyPublicID != companyID {
+		return nil, models.ErrWebhookNotFound
+	}
+	return wh, nil
+}
+
+// validateWebhook checks the URL and event types. On update only the fields that are set are checked.
+// URLs must use https outside the dev profile. Hosts that are addresses of the internal network are
+// refused here already, the sender checks the addresses host names resolve to when it connects.
+func (s *webhookService) validateWebhook(wh *models.Webhook, create bool) error {
+	if create || wh.URL != "" {
+		u, err := url.Parse(wh.URL)
+		if err != nil || u.Host == "" {
+			return models.ErrInvalidInput.WithField("url", "must be an absolute URL")
+		}
+		switch {
+		case u.Scheme == "https":
+		case u.Scheme == "http" && s.cfg.Profile == config.ProfileDev:
+		case s.cfg.Profile == config.ProfileDev:
+			return models.ErrInvalidInput.WithField("url", "must be an http or https URL")
+		default:
+			return models.ErrInvalidInput.WithField("url", "must be an https URL")
+		}
+		if !s.allowPrivate() && !isPublicHost(u.Hostname()) {
+			return models.ErrInvalidInput.WithField("url", "must not point to a private network")
+		}
+	}
+	if create && len(wh.EventTypes) == 0 {
+		return models.ErrInvalidInput.WithField("event_types", "must not be empty")
+	}
+	if wh.EventTypes != nil {
+		if len(wh.EventTypes) == 0 {
+			return models.ErrInvalidInput.WithField("event_types", "must not be empty")
+		}
+		for _, eventType := range wh.EventTypes {
+			if !isWebhookEventType(eventType) {
+				return models.ErrInvalidInput.WithField("event_types", fmt.Sprintf("%q is not a known event type", eventType))
+			}
+		}
+	}
+	return nil
+}
+
+func isWebhookEventType(eventType string) bool {
+	for _, t := range models.WebhookEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (s *webhookService) allowPrivate() bool {
+	return s.cfg.Webhooks != nil && s.cfg.Webhooks.AllowPrivateNetworks
+}
+
+// isPublicHost tells whether the host may be public, host names being checked once resolved
+func isPublicHost(host string) bool {
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return false
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return webhook.IsPublic(addr)
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type queryLogger struct{}
+
+// NewQueryLogger returns a pgx logger that turns the statements pgx reports into spans.
+// pgx v4 has no tracing hooks, so the spans are created once a statement has finished, starting
+// the reported duration earlier. The connection must log at pgx.LogLevelInfo or a more verbose
+// level for successful statements to be reported. Query arguments are never recorded.
+func NewQueryLogger() pgx.Logger {
+	return queryLogger{}
+}
+
+func (queryLogger) Log(ctx context.Context, _ pgx.LogLevel, msg string, data map[string]interface{}) {
+	switch msg {
+	case "Query", "Exec", "SendBatch":
+	default:
+		return
+	}
+	if !trace.SpanFromContext(ctx).IsRecording() {
+		return
+	}
+
+	end := time.Now()
+	start := end
+	if d, ok := data["time"].(time.Duration); ok {
+		start = end.Add(-d)
+	}
+	sql, _ := data["sql"].(string)
+	name := "postgres " + strings.ToLower(msg)
+	if op := operation(sql); op != "" {
+		name = "postgres " + op
+	}
+
+	_, span := Start(ctx, name,
+		trace.WithTimestamp(start),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL),
+	)
+	if sql != "" {
+		span.SetAttributes(semconv.DBStatement(sql), semconv.DBOperation(operation(sql)))
+	}
+	var err error
+	if data["err"] != nil {
+		err = toError(data["err"])
+	}
+	End(span, err)
+}
+
+// operation returns the SQL command of the statement, such as SELECT or UPDATE
+func operation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
+
+func toError(v interface{}) error {
+	if err, ok := v.(error); ok {
+		return err
+	}
+	return errors.New(fmt.Sprint(v))
+}
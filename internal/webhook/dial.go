@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+// ErrPrivateAddress is returned when a webhook URL resolves to an address of the internal network
+var ErrPrivateAddress = errors.New("webhook address is not public")
+
+// sharedAddressSpace is the carrier-grade NAT range, private to the provider's network
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// IsPublic tells whether deliveries may be sent to the address. Loopback, private, link-local,
+// unique-local, multicast and unspecified addresses are refused, so a webhook cannot reach the
+// services, metadata endpoints and pods next to this one.
+func IsPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// publicOnly is a net.Dialer Control refusing to connect to addresses that are not public. It runs
+// on the address the host resolved to right before connecting, so a DNS answer changing between a
+// check and the connection cannot get around it.
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !IsPublic(addr) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, addr)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+)
+
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Body is the JSON document POSTed to the webhook URL.
+type Body struct {
+	ID        string                 `json:"id"`
+	Event     string                 `json:"event"`
+	CreatedAt time.Time              `json:"created_at"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// Sender POSTs a delivery to its webhook and returns the HTTP status code of the response.
+type Sender interface {
+	Send(delivery *models.WebhookDelivery) (int, error)
+}
+
+type httpSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender creates a Sender that gives up on a delivery after timeout. Unless allowPrivate is set,
+// it refuses to connect to addresses that are not public, see IsPublic.
+func NewHTTPSender(timeout time.Duration, allowPrivate bool) Sender {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = publicOnly
+	}
+	return &httpSender{
+		client: &http.Client{
+			Timeout: timeout,
+			// no proxy from the environment, the addresses it connects to would not be checked
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+				MaxIdleConns:        10,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			// a redirect would resend the signed payload to a URL the company did not register
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (s *httpSender) Send(delivery *models.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(&Body{
+		ID:        delivery.PublicID,
+		Event:     delivery.EventType,
+		CreatedAt: delivery.CreatedAt,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "sp-users-main-service-webhooks")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.PublicID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, "sha256="+Sign(delivery.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook secret.
+// Receivers recompute it to verify the payload, and reject old timestamps to prevent replays.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Zhiyenbek/sp-users-main-service/internal/models"
+)
+
+func TestPublicOnly(t *testing.T) {
+	tests := []struct {
+		address string
+		public  bool
+	}{
+		{address: "127.0.0.1:443"},
+		{address: "127.1.2.3:443"},
+		{address: "[::1]:443"},
+		{address: "10.0.0.1:443"},
+		{address: "172.16.0.1:443"},
+		{address: "192.168.1.1:443"},
+		{address: "169.254.169.254:80"},
+		{address: "[fe80::1%eth0]:443"},
+		{address: "[fc00::1]:443"},
+		{address: "100.64.0.1:443"},
+		{address: "0.0.0.0:443"},
+		{address: "[::]:443"},
+		{address: "224.0.0.1:443"},
+		{address: "[::ffff:127.0.0.1]:443"},
+		{address: "[::ffff:10.0.0.1]:443"},
+		{address: "[::ffff:169.254.169.254]:80"},
+		{address: "93.184.216.34:443", public: true},
+		{address: "[2606:4700:4700::1111]:443", public: true},
+		{address: "[::ffff:93.184.216.34]:443", public: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := publicOnly("tcp", tt.address, nil)
+			if tt.public && err != nil {
+				t.Fatalf("expected the address to be allowed, got %v", err)
+			}
+			if !tt.public && !errors.Is(err, ErrPrivateAddress) {
+				t.Fatalf("expected ErrPrivateAddress, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSenderRefusesPrivateAddresses(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+	}))
+	defer server.Close()
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, url := range []string{server.URL, "http://localhost:" + port} {
+		_, err := NewHTTPSender(time.Second, false).Send(testDelivery(url))
+		if !errors.Is(err, ErrPrivateAddress) {
+			t.Errorf("%s: expected ErrPrivateAddress, got %v", url, err)
+		}
+	}
+	if n := atomic.LoadInt32(&hits); n != 0 {
+		t.Fatalf("expected no request to reach the server, got %d", n)
+	}
+}
+
+func TestSenderDoesNotFollowRedirects(t *testing.T) {
+	var followed int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/webhook", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/elsewhere", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/elsewhere", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&followed, 1)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	status, err := NewHTTPSender(time.Second, true).Send(testDelivery(server.URL + "/webhook"))
+	if err == nil || status != http.StatusTemporaryRedirect {
+		t.Fatalf("expected the redirect to fail the delivery, got %d %v", status, err)
+	}
+	if n := atomic.LoadInt32(&followed); n != 0 {
+		t.Fatalf("expected the redirect not to be followed, got %d requests", n)
+	}
+}
+
+func TestSenderSignsBody(t *testing.T) {
+	delivery := testDelivery("")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		want := "sha256=" + Sign(delivery.Secret, r.Header.Get(HeaderTimestamp), body)
+		if got := r.Header.Get(HeaderSignature); got != want {
+			t.Errorf("expected signature %s, got %s", want, got)
+		}
+		if got := r.Header.Get(HeaderDelivery); got != delivery.PublicID {
+			t.Errorf("expected delivery %s, got %s", delivery.PublicID, got)
+		}
+	}))
+	defer server.Close()
+	delivery.URL = server.URL
+
+	if _, err := NewHTTPSender(time.Second, true).Send(delivery); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"delivery","event":"application.received"}`)
+	const want = "47bf6d7388e77705abede8cad87257f0db0732247ccb85e922ac2bea2fbb3aab"
+	if got := Sign("whsec_test", "1700000000", body); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+	if got := Sign("whsec_test", "1700000001", body); got == want {
+		t.Fatal("expected the timestamp to be signed")
+	}
+}
+
+func testDelivery(url string) *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		PublicID:  "delivery",
+		EventType: "application.received",
+		Payload:   map[string]interface{}{"candidate_public_id": "candidate"},
+		CreatedAt: time.Now(),
+		URL:       url,
+		Secret:    "whsec_test",
+	}
+}
@@ -0,0 +1,24 @@
+-- Skills used to be matched by their exact name, so databases created before idx_skills_normalized_name can
+-- hold names that differ only in case. Every group is merged into its oldest skill: the candidates and
+-- positions of the duplicates move to it, then the duplicates are deleted and the index can be created.
+CREATE TEMPORARY TABLE duplicate_skills ON COMMIT DROP AS
+SELECT id, min(id) OVER (PARTITION BY lower(name)) AS kept_id
+FROM skills;
+
+DELETE FROM duplicate_skills WHERE id = kept_id;
+
+INSERT INTO candidate_skills (candidate_id, skill_id)
+SELECT cs.candidate_id, d.kept_id
+FROM candidate_skills cs
+JOIN duplicate_skills d ON d.id = cs.skill_id
+ON CONFLICT DO NOTHING;
+
+INSERT INTO position_skills (position_id, skill_id)
+SELECT ps.position_id, d.kept_id
+FROM position_skills ps
+JOIN duplicate_skills d ON d.id = ps.skill_id
+ON CONFLICT DO NOTHING;
+
+DELETE FROM skills WHERE id IN (SELECT id FROM duplicate_skills);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_skills_normalized_name ON skills (lower(name));
@@ -0,0 +1,20 @@
+-- Recruiters used to belong to a company without a role, so every company of a database created before
+-- roles has no owner. Roles are added with the recruiter default, and the earliest recruiter of each
+-- company without an owner becomes its owner.
+ALTER TABLE recruiters ALTER COLUMN company_public_id DROP NOT NULL;
+ALTER TABLE recruiters ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'recruiter'
+    CHECK (role IN ('owner', 'admin', 'recruiter', 'viewer'));
+CREATE INDEX IF NOT EXISTS idx_recruiters_company ON recruiters (company_public_id, role);
+
+UPDATE recruiters r
+SET role = 'owner'
+FROM (
+    SELECT DISTINCT ON (company_public_id) id
+    FROM recruiters
+    WHERE company_public_id IS NOT NULL
+    ORDER BY company_public_id, id
+) earliest
+WHERE r.id = earliest.id
+AND NOT EXISTS (
+    SELECT 1 FROM recruiters o WHERE o.company_public_id = r.company_public_id AND o.role = 'owner'
+);
@@ -0,0 +1,3 @@
+-- Users signed up before the auth service reported whether it verified their email, so none of them
+-- counts as verified and their recruiters ask to join companies instead of joining by their domain.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT FALSE;
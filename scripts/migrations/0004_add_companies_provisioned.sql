@@ -0,0 +1,5 @@
+-- Companies found or created by name when recruiters sign up are marked provisioned, and are unique by
+-- name so concurrent sign ups naming a new company create it once. Existing companies were created
+-- otherwise and keep the default.
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS provisioned BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_companies_provisioned_name ON companies (lower(name)) WHERE provisioned;
@@ -0,0 +1,374 @@
+-- The tables of shortlists, notes, saved searches, notifications, domain events, webhooks, invitations,
+-- join requests and company domains, with their indexes and triggers, as scripts/init.sql creates them.
+-- Databases created before them have none, and the service is not ready until they exist.
+CREATE TABLE IF NOT EXISTS shortlists (
+    id SERIAL PRIMARY KEY,
+    public_id UUID UNIQUE DEFAULT uuid_generate_v4() NOT NULL,
+    recruiter_public_id UUID NOT NULL,
+    company_public_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    shared BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_shortlists_recruiters FOREIGN KEY (recruiter_public_id) REFERENCES recruiters(public_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_shortlists_company ON shortlists (company_public_id) WHERE shared;
+
+CREATE TABLE IF NOT EXISTS shortlist_candidates (
+    shortlist_id INT,
+    candidate_id INT,
+    note TEXT NOT NULL DEFAULT '',
+    added_by UUID NOT NULL,
+    added_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (shortlist_id, candidate_id),
+    CONSTRAINT fk_shortlist_candidates_shortlists FOREIGN KEY (shortlist_id) REFERENCES shortlists(id) ON DELETE CASCADE,
+    CONSTRAINT fk_shortlist_candidates_candidates FOREIGN KEY (candidate_id) REFERENCES candidates(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS recruiter_notes (
+    id SERIAL PRIMARY KEY,
+    public_id UUID UNIQUE DEFAULT uuid_generate_v4() NOT NULL,
+    company_public_id UUID NOT NULL,
+    author_public_id UUID NOT NULL,
+    subject_type VARCHAR(20) NOT NULL CHECK (subject_type IN ('candidate', 'interview')),
+    subject_public_id UUID NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    rating SMALLINT CHECK (rating BETWEEN 1 AND 5),
+    version INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_recruiter_notes_recruiters FOREIGN KEY (author_public_id) REFERENCES recruiters(public_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_recruiter_notes_subject ON recruiter_notes (company_public_id, subject_type, subject_public_id);
+
+CREATE TABLE IF NOT EXISTS recruiter_note_revisions (
+    note_id INT,
+    version INT,
+    body TEXT NOT NULL,
+    rating SMALLINT,
+    edited_by UUID NOT NULL,
+    edited_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (note_id, version),
+    CONSTRAINT fk_recruiter_note_revisions_notes FOREIGN KEY (note_id) REFERENCES recruiter_notes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS recruiter_note_mentions (
+    note_id INT,
+    recruiter_public_id UUID,
+    PRIMARY KEY (note_id, recruiter_public_id),
+    CONSTRAINT fk_recruiter_note_mentions_notes FOREIGN KEY (note_id) REFERENCES recruiter_notes(id) ON DELETE CASCADE,
+    CONSTRAINT fk_recruiter_note_mentions_recruiters FOREIGN KEY (recruiter_public_id) REFERENCES recruiters(public_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id SERIAL PRIMARY KEY,
+    public_id UUID UNIQUE DEFAULT uuid_generate_v4() NOT NULL,
+    recruiter_public_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}',
+    last_run_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_saved_searches_recruiters FOREIGN KEY (recruiter_public_id) REFERENCES recruiters(public_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS saved_search_matches (
+    saved_search_id INT,
+    candidate_id INT,
+    matched_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (saved_search_id, candidate_id),
+    CONSTRAINT fk_saved_search_matches_saved_searches FOREIGN KEY (saved_search_id) REFERENCES saved_searches(id) ON DELETE CASCADE,
+    CONSTRAINT fk_saved_search_matches_candidates FOREIGN KEY (candidate_id) REFERENCES candidates(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS saved_search_alerts (
+    id SERIAL PRIMARY KEY,
+    public_id UUID UNIQUE DEFAULT uuid_generate_v4() NOT NULL,
+    saved_search_id INT NOT NULL,
+    candidate_id INT NOT NULL,
+    seen BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_saved_search_alerts_saved_searches FOREIGN KEY (saved_search_id) REFERENCES saved_searches(id) ON DELETE CASCADE,
+    CONSTRAINT fk_saved_search_alerts_candidates FOREIGN KEY (candidate_id) REFERENCES candidates(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS notification_outbox (
+    id SERIAL PRIMARY KEY,
+    public_id UUID UNIQUE DEFAULT uuid_generate_v4() NOT NULL,
+    user_public_id UUID,
+    -- the address of notifications sent to no user, like invitations
+    recipient_email VARCHAR(50),
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMP,
+    CONSTRAINT fk_notification_outbox_users FOREIGN KEY (user_public_id) REFERENCES users(public_id) ON DELETE CASCADE,
+    CONSTRAINT chk_notification_outbox_recipient CHECK (user_public_id IS NOT NULL OR recipient_email IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_outbox_pending ON notification_outbox (next_attempt_at) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id SERIAL PRIMARY KEY,
+    notification_id INT NOT NULL,
+    attempt INT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    recipient VARCHAR(50) NOT NULL DEFAULT '',
+    error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_notification_deliveries_outbox FOREIGN KEY (notification_id) REFERENCES notification_outbox(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_public_id UUID,
+    event_type VARCHAR(50),
+    email_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    PRIMARY KEY (user_public_id, event_type),
+    CONSTRAINT fk_notification_preferences_users FOREIGN KEY (user_public_id) REFERENCES users(public_id) ON DELETE CASCADE
+);
+
+-- Interviews are evaluated and applied to by other services, so their notifications are queued
+-- by triggers in the same transaction as the change.
+CREATE OR REPLACE FUNCTION notify_interview_evaluated() RETURNS trigger AS $$
+BEGIN
+    IF NEW.results IS NOT NULL AND NEW.results IS DISTINCT FROM OLD.results THEN
+        INSERT INTO notification_outbox (user_public_id, event_type, payload)
+        SELECT c.public_id, 'interview.evaluated', jsonb_build_object(
+            'interview_public_id', NEW.public_id,
+            'position_public_id', p.public_id,
+            'position_name', p.name
+        )
+        FROM user_interviews ui
+        JOIN candidates c ON c.id = ui.candidate_id
+        JOIN positions p ON p.id = ui.position_id
+        WHERE ui.interview_id = NEW.id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_interviews_evaluated ON interviews;
+CREATE TRIGGER trg_interviews_evaluated AFTER UPDATE OF results ON interviews
+    FOR EACH ROW EXECUTE PROCEDURE notify_interview_evaluated();
+
+CREATE OR REPLACE FUNCTION notify_application_received() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO notification_outbox (user_public_id, event_type, payload)
+    SELECT p.recruiter_public_id, 'application.received', jsonb_build_object(
+        'candidate_public_id', c.public_id,
+        'position_public_id', p.public_id,
+        'position_name', p.name
+    )
+    FROM positions p, candidates c
+    WHERE p.id = NEW.position_id AND c.id = NEW.candidate_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_user_interviews_application ON user_interviews;
+CREATE TRIGGER trg_user_interviews_application AFTER INSERT ON user_interviews
+    FOR EACH ROW EXECUTE PROCEDURE notify_application_received();
+
+CREATE TABLE IF NOT EXISTS domain_event_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    public_id UUID UNIQUE DEFAULT uuid_generate_v4() NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_public_id UUID NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    occurred_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_domain_event_outbox_unpublished ON domain_event_outbox (id) WHERE published_at IS NULL;
+
+-- Interview results are stored by the interview pipeline, so interview.result_stored is written
+-- to the outbox by a trigger in the same transaction as the results.
+CREATE OR REPLACE FUNCTION publish_interview_result_stored() RETURNS trigger AS $$
+BEGIN
+    IF NEW.results IS NULL THEN
+        RETURN NEW;
+    END IF;
+    IF TG_OP = 'UPDATE' THEN
+        IF NEW.results IS NOT DISTINCT FROM OLD.results THEN
+            RETURN NEW;
+        END IF;
+    END IF;
+    INSERT INTO domain_event_outbox (event_type, aggregate_type, aggregate_public_id, payload)
+    VALUES ('interview.result_stored', 'interview', NEW.public_id, jsonb_build_object(
+        'interview_public_id', NEW.public_id,
+        'candidates', COALESCE((
+            SELECT jsonb_agg(jsonb_build_object(
+                'candidate_public_id', c.public_id,
+                'position_public_id', p.public_id
+            ))
+            FROM user_interviews ui
+            JOIN candidates c ON c.id = ui.candidate_id
+            JOIN positions p ON p.id = ui.position_id
+            WHERE ui.interview_id = NEW.id
+        ), '[]'::jsonb)
+    ));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_interviews_result_stored ON interviews;
+CREATE TRIGGER trg_interviews_result_stored AFTER INSERT OR UPDATE OF results ON interviews
+    FOR EACH ROW EXECUTE PROCEDURE publish_interview_result_stored();
+
+CREATE TABLE IF NOT EXISTS company_webhooks (
+    id SERIAL PRIMARY KEY,
+    public_id UUID UNIQUE DEFAULT uuid_generate_v4() NOT NULL,
+    company_public_id UUID NOT NULL,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_company_webhooks_companies FOREIGN KEY (company_public_id) REFERENCES companies(public_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id SERIAL PRIMARY KEY,
+    public_id UUID UNIQUE DEFAULT uuid_generate_v4() NOT NULL,
+    webhook_id INT NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_status_code INT,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP,
+    CONSTRAINT fk_webhook_deliveries_company_webhooks FOREIGN KEY (webhook_id) REFERENCES company_webhooks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+-- Webhook deliveries are queued for every active webhook of the company subscribed to the event,
+-- in the same transaction as the application or interview result written by the other services.
+CREATE OR REPLACE FUNCTION enqueue_company_webhooks(company UUID, event TEXT, payload JSONB) RETURNS void AS $$
+BEGIN
+    INSERT INTO webhook_deliveries (webhook_id, event_type, payload)
+    SELECT w.id, event, payload
+    FROM company_webhooks w
+    WHERE w.company_public_id = company AND w.active AND event = ANY(w.event_types);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION webhook_application_received() RETURNS trigger AS $$
+BEGIN
+    PERFORM enqueue_company_webhooks(r.company_public_id, 'application.received', jsonb_build_object(
+        'candidate_public_id', c.public_id,
+        'position_public_id', p.public_id,
+        'position_name', p.name,
+        'interview_public_id', i.public_id
+    ))
+    FROM positions p
+    JOIN recruiters r ON r.public_id = p.recruiter_public_id
+    JOIN candidates c ON c.id = NEW.candidate_id
+    LEFT JOIN interviews i ON i.id = NEW.interview_id
+    WHERE p.id = NEW.position_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_user_interviews_webhooks ON user_interviews;
+CREATE TRIGGER trg_user_interviews_webhooks AFTER INSERT ON user_interviews
+    FOR EACH ROW EXECUTE PROCEDURE webhook_application_received();
+
+CREATE OR REPLACE FUNCTION webhook_interview_result_stored() RETURNS trigger AS $$
+BEGIN
+    IF NEW.results IS NULL THEN
+        RETURN NEW;
+    END IF;
+    IF TG_OP = 'UPDATE' THEN
+        IF NEW.results IS NOT DISTINCT FROM OLD.results THEN
+            RETURN NEW;
+        END IF;
+    END IF;
+    PERFORM enqueue_company_webhooks(r.company_public_id, 'interview.result_stored', jsonb_build_object(
+        'interview_public_id', NEW.public_id,
+        'candidate_public_id', c.public_id,
+        'position_public_id', p.public_id,
+        'position_name', p.name,
+        'results', NEW.results
+    ))
+    FROM user_interviews ui
+    JOIN candidates c ON c.id = ui.candidate_id
+    JOIN positions p ON p.id = ui.position_id
+    JOIN recruiters r ON r.public_id = p.recruiter_public_id
+    WHERE ui.interview_id = NEW.id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_interviews_webhooks ON interviews;
+CREATE TRIGGER trg_interviews_webhooks AFTER INSERT OR UPDATE OF results ON interviews
+    FOR EACH ROW EXECUTE PROCEDURE webhook_interview_result_stored();
+
+CREATE TABLE IF NOT EXISTS company_invitations (
+    id SERIAL PRIMARY KEY,
+    public_id UUID UNIQUE DEFAULT uuid_generate_v4() NOT NULL,
+    company_public_id UUID NOT NULL,
+    email VARCHAR(50) NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'admin', 'recruiter', 'viewer')),
+    -- the SHA-256 of the token sent by email, the token itself is never stored
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    invited_by UUID NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    accepted_at TIMESTAMP,
+    accepted_by UUID,
+    revoked_at TIMESTAMP,
+    CONSTRAINT fk_company_invitations_companies FOREIGN KEY (company_public_id) REFERENCES companies(public_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_company_invitations_company ON company_invitations (company_public_id, lower(email));
+
+CREATE TABLE IF NOT EXISTS company_join_requests (
+    id SERIAL PRIMARY KEY,
+    public_id UUID UNIQUE DEFAULT uuid_generate_v4() NOT NULL,
+    company_public_id UUID NOT NULL,
+    recruiter_public_id UUID NOT NULL,
+    message VARCHAR(200) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    decided_by UUID,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    decided_at TIMESTAMP,
+    CONSTRAINT fk_company_join_requests_companies FOREIGN KEY (company_public_id) REFERENCES companies(public_id) ON DELETE CASCADE,
+    CONSTRAINT fk_company_join_requests_recruiters FOREIGN KEY (recruiter_public_id) REFERENCES recruiters(public_id) ON DELETE CASCADE
+);
+-- a recruiter has at most one pending request per company, so repeated requests return the pending one
+CREATE UNIQUE INDEX IF NOT EXISTS idx_company_join_requests_pending ON company_join_requests (company_public_id, recruiter_public_id) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS company_domains (
+    id SERIAL PRIMARY KEY,
+    public_id UUID UNIQUE DEFAULT uuid_generate_v4() NOT NULL,
+    company_public_id UUID NOT NULL,
+    domain VARCHAR(253) NOT NULL,
+    method VARCHAR(10) NOT NULL CHECK (method IN ('dns', 'email')),
+    -- the value of the TXT record published by the company, for the dns method
+    dns_token VARCHAR(64),
+    -- the address the code was sent to and the hash of the code, for the email method
+    email VARCHAR(50),
+    code_hash VARCHAR(64),
+    code_expires_at TIMESTAMP,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    verified_at TIMESTAMP,
+    CONSTRAINT uq_company_domains UNIQUE (company_public_id, domain),
+    CONSTRAINT fk_company_domains_companies FOREIGN KEY (company_public_id) REFERENCES companies(public_id) ON DELETE CASCADE
+);
+-- a domain is verified by one company at most, which its recruiters can join
+CREATE UNIQUE INDEX IF NOT EXISTS idx_company_domains_verified ON company_domains (domain) WHERE verified_at IS NOT NULL;